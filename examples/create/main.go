@@ -25,52 +25,21 @@ func main() {
 		),
 	)
 
-	res, err := client.Domains.Create(ctx, namecheap.DomainCreateArgs{
-		DomainName: "some-domain-name-foobar.xyz",
-		Years:      1,
-
-		RegistrantFirstName:     "Jane",
-		RegistrantLastName:      "Smith",
-		RegistrantAddress1:      "16 Pennsylvania St",
-		RegistrantCity:          "Not Lake City",
-		RegistrantStateProvince: "UM",
-		RegistrantPostalCode:    "02134",
-		RegistrantCountry:       "US",
-		RegistrantPhone:         "+1.2145550000",
-		RegistrantEmailAddress:  "webmaster@example.com",
-
-		TechFirstName:     "Jane",
-		TechLastName:      "Smith",
-		TechAddress1:      "16 Pennsylvania St",
-		TechCity:          "Not Lake City",
-		TechStateProvince: "UM",
-		TechPostalCode:    "02134",
-		TechCountry:       "US",
-		TechPhone:         "+1.2145550000",
-		TechEmailAddress:  "webmaster@example.com",
-
-		AdminFirstName:     "Jane",
-		AdminLastName:      "Smith",
-		AdminAddress1:      "16 Pennsylvania St",
-		AdminCity:          "Not Lake City",
-		AdminStateProvince: "UM",
-		AdminPostalCode:    "02134",
-		AdminCountry:       "US",
-		AdminPhone:         "+1.2145550000",
-		AdminEmailAddress:  "webmaster@example.com",
-
-		AuxBillingFirstName:     "Jane",
-		AuxBillingLastName:      "Smith",
-		AuxBillingAddress1:      "16 Pennsylvania St",
-		AuxBillingCity:          "Not Lake City",
-		AuxBillingStateProvince: "UM",
-		AuxBillingPostalCode:    "02134",
-		AuxBillingCountry:       "US",
-		AuxBillingPhone:         "+1.2145550000",
-		AuxBillingEmailAddress:  "webmaster@example.com",
-
-		Nameservers: "ns1.some-dns-servers.invalid,ns2.other-nameservers-here.example",
+	// The same contact is used for the Registrant, Tech, Admin and AuxBilling roles
+	args := namecheap.NewDomainCreateArgs("some-domain-name-foobar.xyz", 1, namecheap.Contact{
+		FirstName:     "Jane",
+		LastName:      "Smith",
+		Address1:      "16 Pennsylvania St",
+		City:          "Not Lake City",
+		StateProvince: "UM",
+		PostalCode:    "02134",
+		Country:       "US",
+		Phone:         "+1.2145550000",
+		EmailAddress:  "webmaster@example.com",
 	})
+	args.Nameservers = "ns1.some-dns-servers.invalid,ns2.other-nameservers-here.example"
+
+	res, err := client.Domains.Create(ctx, args)
 	if err != nil {
 		log.Println("error while creating/registering domain:", err)
 	}
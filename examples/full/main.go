@@ -54,12 +54,15 @@ func main() {
 		return
 	}
 
+	// Pricing is loaded once for all the TLDs and reused for every lookup
+	catalog := namecheap.NewPricingCatalog(client, nil)
+
 	for _, domain := range availabilityResults {
-		if domain.Available != "true" {
+		if !domain.Available {
 			fmt.Printf("%s is not available\n", domain.Domain)
 			continue
 		}
-		if domain.IsPremiumName == "true" {
+		if domain.IsPremiumName {
 			fmt.Printf("[PREMIUM] Price for %s: %s\n", domain.Domain, domain.PremiumRegistrationPrice)
 		} else {
 			domainInfo, err := publicsuffix.Parse(domain.Domain)
@@ -68,20 +71,14 @@ func main() {
 				continue
 			}
 
-			pricing, err := client.UsersService.GetPricing(ctx, namecheap.UserGetPricingArgs{
-				ProductType:     namecheap.ProductTypeDomain,
-				ProductCategory: namecheap.ProductCategoryDomains,
-				ProductName:     domainInfo.TLD,
-				ActionName:      namecheap.ActionNameRegister,
-			})
+			p, err := catalog.Price(ctx, domainInfo.TLD, namecheap.ActionNameRegister, 1)
 			if err != nil {
 				log.Fatalln(err)
 			}
-			p := (*pricing).ProductType.ProductCategory[0].Product[0].Price[0]
 			if p.RegularPrice == p.YourPrice {
 				fmt.Printf("[REGULAR] Price for %s: %s\n", domainInfo.String(), p.RegularPrice)
 			} else {
-				fmt.Printf("[SALE] Price for %s: %s -> %s\n", domainInfo.String(), ansiWrap(p.RegularPrice, ansiStrikethrough), p.YourPrice)
+				fmt.Printf("[SALE] Price for %s: %s -> %s\n", domainInfo.String(), ansiWrap(p.RegularPrice.String(), ansiStrikethrough), p.YourPrice)
 			}
 		}
 	}
@@ -4,7 +4,8 @@ require (
 	github.com/hashicorp/go-cleanhttp v0.5.2
 	github.com/stretchr/testify v1.7.0
 	github.com/weppos/publicsuffix-go v0.15.0
-	golang.org/x/net v0.0.0-20200324143707-d3edc9973b7e // indirect
+	golang.org/x/net v0.0.0-20200324143707-d3edc9973b7e
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c
 )
 
 go 1.15
@@ -0,0 +1,230 @@
+package namecheap
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// acmeChallengeLabel is the host label of the DNS-01 challenge records
+const acmeChallengeLabel = "_acme-challenge"
+
+const (
+	defaultACMEPropagationTimeout = time.Hour
+	defaultACMEPollingInterval    = 15 * time.Second
+)
+
+// ACMEDNSProviderConfig is the configuration of ACMEDNSProvider, the zero values are replaced with the defaults
+type ACMEDNSProviderConfig struct {
+	// TTL of the challenge records, it's raised to MinTTL
+	// Default value: MinTTL
+	TTL int
+	// How long the ACME client waits for the challenge records to propagate to the Namecheap nameservers
+	// Default value: 1 hour
+	PropagationTimeout time.Duration
+	// How often the ACME client checks the propagation
+	// Default value: 15 seconds
+	PollingInterval time.Duration
+}
+
+// ACMEDNSProvider solves the ACME DNS-01 challenges with the _acme-challenge TXT records. It implements the provider
+// interface of lego (challenge.Provider and challenge.ProviderTimeout) and is used the same way by the other ACME
+// clients, e.g. certmagic:
+//
+//	provider := namecheap.NewACMEDNSProvider(client, nil)
+//	err := legoClient.Challenge.SetDNS01Provider(provider)
+//
+// The challenge records are added and removed keeping the other host records of the domain. The challenges of the
+// same domain presented concurrently, e.g. for domain.net and *.domain.net, are merged into a single SetHosts call.
+type ACMEDNSProvider struct {
+	client *Client
+	config ACMEDNSProviderConfig
+
+	m sync.Mutex
+	// pending changes of the challenge records by the domain they're written to
+	pending map[string]*acmeChallengeBatch
+}
+
+// acmeChallengeBatch is the changes of the challenge records of a domain written by a single SetHosts call
+type acmeChallengeBatch struct {
+	changes []acmeChallengeChange
+	done    chan struct{}
+	err     error
+}
+
+type acmeChallengeChange struct {
+	record DomainsDNSHostRecord
+	remove bool
+}
+
+// NewACMEDNSProvider returns a DNS-01 provider writing the challenge records through the client, nil config means the defaults
+func NewACMEDNSProvider(client *Client, config *ACMEDNSProviderConfig) *ACMEDNSProvider {
+	provider := &ACMEDNSProvider{
+		client:  client,
+		pending: map[string]*acmeChallengeBatch{},
+	}
+	if config != nil {
+		provider.config = *config
+	}
+	if provider.config.TTL < MinTTL {
+		provider.config.TTL = MinTTL
+	}
+	if provider.config.PropagationTimeout <= 0 {
+		provider.config.PropagationTimeout = defaultACMEPropagationTimeout
+	}
+	if provider.config.PollingInterval <= 0 {
+		provider.config.PollingInterval = defaultACMEPollingInterval
+	}
+	return provider
+}
+
+// Present adds the challenge record of the domain, e.g. domain.net, *.domain.net or www.domain.net
+func (p *ACMEDNSProvider) Present(domain, token, keyAuth string) error {
+	return p.PresentContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUp removes the challenge record of the domain added by Present
+func (p *ACMEDNSProvider) CleanUp(domain, token, keyAuth string) error {
+	return p.CleanUpContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentContext is Present with a context
+func (p *ACMEDNSProvider) PresentContext(ctx context.Context, domain, token, keyAuth string) error {
+	zone, record, err := p.challengeRecord(domain, keyAuth)
+	if err != nil {
+		return err
+	}
+	if err := p.change(ctx, zone, acmeChallengeChange{record: record}); err != nil {
+		return fmt.Errorf("unable to present the DNS-01 challenge of %s: %v", domain, err)
+	}
+	return nil
+}
+
+// CleanUpContext is CleanUp with a context
+func (p *ACMEDNSProvider) CleanUpContext(ctx context.Context, domain, token, keyAuth string) error {
+	zone, record, err := p.challengeRecord(domain, keyAuth)
+	if err != nil {
+		return err
+	}
+	if err := p.change(ctx, zone, acmeChallengeChange{record: record, remove: true}); err != nil {
+		return fmt.Errorf("unable to clean up the DNS-01 challenge of %s: %v", domain, err)
+	}
+	return nil
+}
+
+// Timeout returns how long the ACME client waits for the propagation and how often it checks it
+func (p *ACMEDNSProvider) Timeout() (timeout, interval time.Duration) {
+	return p.config.PropagationTimeout, p.config.PollingInterval
+}
+
+// ACMEChallengeValue returns the TXT value of the DNS-01 challenge: the unpadded base64url encoded SHA-256 digest of the key authorization
+func ACMEChallengeValue(keyAuth string) string {
+	digest := sha256.Sum256([]byte(keyAuth))
+	return base64.RawURLEncoding.EncodeToString(digest[:])
+}
+
+// challengeRecord returns the registered domain the challenge record of the domain is written to, and the record
+func (p *ACMEDNSProvider) challengeRecord(domain string, keyAuth string) (string, DomainsDNSHostRecord, error) {
+	name := strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(domain, "*."), "."))
+
+	parsedDomain, err := ParseDomain(name)
+	if err != nil {
+		return "", DomainsDNSHostRecord{}, fmt.Errorf("invalid DNS-01 challenge domain %s: %v", domain, err)
+	}
+
+	hostName := acmeChallengeLabel
+	if parsedDomain.TRD != "" {
+		hostName += "." + parsedDomain.TRD
+	}
+
+	return parsedDomain.SLD + "." + parsedDomain.TLD, DomainsDNSHostRecord{
+		HostName:   hostName,
+		RecordType: RecordTypeTXT,
+		Address:    ACMEChallengeValue(keyAuth),
+		TTL:        p.config.TTL,
+	}, nil
+}
+
+// change queues the change of the challenge record and waits until it's written. The first change of a batch writes it,
+// the batch takes the changes queued until the current host records are read, e.g. while waiting for a previous write.
+func (p *ACMEDNSProvider) change(ctx context.Context, zone string, change acmeChallengeChange) error {
+	p.m.Lock()
+	batch, joined := p.pending[zone]
+	if !joined {
+		batch = &acmeChallengeBatch{done: make(chan struct{})}
+		p.pending[zone] = batch
+	}
+	batch.changes = append(batch.changes, change)
+	p.m.Unlock()
+
+	if joined {
+		select {
+		case <-batch.done:
+			return batch.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	_, batch.err = p.client.DomainsDNS.updateHosts(ctx, zone, func(result DomainDNSGetHostsResult) (*DomainsDNSSetHostsArgs, error) {
+		records, changed := applyACMEChallengeChanges(HostRecordsFromDetailed(result.Hosts), p.takeChanges(zone, batch))
+		if !changed {
+			return nil, nil
+		}
+		return &DomainsDNSSetHostsArgs{
+			Domain:    zone,
+			Records:   records,
+			EmailType: result.EmailType,
+		}, nil
+	})
+
+	// close the batch for the new changes if reading the host records failed before the update func ran
+	p.takeChanges(zone, batch)
+	close(batch.done)
+
+	return batch.err
+}
+
+// takeChanges closes the batch for the new changes and returns its changes
+func (p *ACMEDNSProvider) takeChanges(zone string, batch *acmeChallengeBatch) []acmeChallengeChange {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if p.pending[zone] == batch {
+		delete(p.pending, zone)
+	}
+	return batch.changes
+}
+
+// applyACMEChallengeChanges adds the missing challenge records and removes the ones being cleaned up,
+// it reports whether the records were changed
+func applyACMEChallengeChanges(records []DomainsDNSHostRecord, changes []acmeChallengeChange) ([]DomainsDNSHostRecord, bool) {
+	changed := false
+	for _, change := range changes {
+		key := change.record.Key()
+
+		kept := make([]DomainsDNSHostRecord, 0, len(records)+1)
+		for _, record := range records {
+			if record.Key() != key {
+				kept = append(kept, record)
+			}
+		}
+
+		switch {
+		case change.remove:
+			changed = changed || len(kept) != len(records)
+		case len(kept) == len(records):
+			kept = append(kept, change.record)
+			changed = true
+		default:
+			// presented already, e.g. by a retried order
+			kept = records
+		}
+		records = kept
+	}
+	return records, changed
+}
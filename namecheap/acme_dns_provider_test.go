@@ -0,0 +1,237 @@
+package namecheap
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const fakeACMEGetHostsResponse = `
+	<?xml version="1.0" encoding="utf-8"?>
+	<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+		<Errors />
+		<CommandResponse Type="namecheap.domains.dns.getHosts">
+			<DomainDNSGetHostsResult Domain="domain.net" EmailType="MX" IsUsingOurDNS="true">
+				<host HostId="1" Name="@" Type="MX" Address="mx1.domain.net." MXPref="5" TTL="1800" IsActive="true" IsDDNSEnabled="false" />
+				<host HostId="2" Name="_acme-challenge" Type="TXT" Address="61rBZ_4knHblO0MNoxFsXZ_eTFUHum0B6IVRbhvUn5I" MXPref="10" TTL="60" IsActive="true" IsDDNSEnabled="false" />
+				<host HostId="3" Name="www" Type="A" Address="192.0.2.1" MXPref="10" TTL="300" IsActive="true" IsDDNSEnabled="false" />
+			</DomainDNSGetHostsResult>
+		</CommandResponse>
+	</ApiResponse>
+`
+
+func TestACMEChallengeValue(t *testing.T) {
+	assert.Equal(t, "61rBZ_4knHblO0MNoxFsXZ_eTFUHum0B6IVRbhvUn5I", ACMEChallengeValue("token.thumbprint"))
+}
+
+func TestNewACMEDNSProvider(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		provider := NewACMEDNSProvider(setupClient(nil), nil)
+
+		timeout, interval := provider.Timeout()
+		assert.Equal(t, time.Hour, timeout)
+		assert.Equal(t, 15*time.Second, interval)
+		assert.Equal(t, MinTTL, provider.config.TTL)
+	})
+
+	t.Run("min_ttl", func(t *testing.T) {
+		provider := NewACMEDNSProvider(setupClient(nil), &ACMEDNSProviderConfig{TTL: 30})
+
+		assert.Equal(t, MinTTL, provider.config.TTL)
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		provider := NewACMEDNSProvider(setupClient(nil), &ACMEDNSProviderConfig{TTL: 300, PropagationTimeout: time.Minute, PollingInterval: time.Second})
+
+		timeout, interval := provider.Timeout()
+		assert.Equal(t, time.Minute, timeout)
+		assert.Equal(t, time.Second, interval)
+		assert.Equal(t, 300, provider.config.TTL)
+	})
+}
+
+func TestACMEDNSProvider(t *testing.T) {
+	setupProvider := func(getHostsResponse string) (*ACMEDNSProvider, *url.Values, func()) {
+		sentSetHosts := url.Values{}
+		mockServer := newRecordsMockServer(getHostsResponse, &sentSetHosts)
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		return NewACMEDNSProvider(client, nil), &sentSetHosts, mockServer.Close
+	}
+
+	t.Run("present", func(t *testing.T) {
+		provider, sent, closeServer := setupProvider(fakeRecordsGetHostsResponse)
+		defer closeServer()
+
+		err := provider.Present("domain.net", "token", "token.thumbprint")
+		if err != nil {
+			t.Fatal("Unable to present challenge", err)
+		}
+
+		assert.Equal(t, "domain.net", sent.Get("SLD")+"."+sent.Get("TLD"))
+		assert.Equal(t, "MX", sent.Get("EmailType"))
+		assert.Equal(t, "mx1.domain.net.", sent.Get("Address1"))
+		assert.Equal(t, "5", sent.Get("MXPref1"))
+		assert.Equal(t, `0 issue "letsencrypt.org"`, sent.Get("Address3"))
+		assert.Equal(t, "300", sent.Get("TTL4"))
+		assert.Equal(t, "_acme-challenge", sent.Get("HostName5"))
+		assert.Equal(t, "TXT", sent.Get("RecordType5"))
+		assert.Equal(t, "61rBZ_4knHblO0MNoxFsXZ_eTFUHum0B6IVRbhvUn5I", sent.Get("Address5"))
+		assert.Equal(t, "60", sent.Get("TTL5"))
+	})
+
+	t.Run("present_subdomain_wildcard", func(t *testing.T) {
+		provider, sent, closeServer := setupProvider(fakeRecordsGetHostsResponse)
+		defer closeServer()
+
+		err := provider.Present("*.Shop.Domain.net.", "token", "wildcard.thumbprint")
+		if err != nil {
+			t.Fatal("Unable to present challenge", err)
+		}
+
+		assert.Equal(t, "_acme-challenge.shop", sent.Get("HostName5"))
+		assert.Equal(t, "s4Qws40yK4HZFkXV46YsG65Q5ZI6eFRxYcRF_Flt91Y", sent.Get("Address5"))
+	})
+
+	t.Run("present_existing", func(t *testing.T) {
+		provider, sent, closeServer := setupProvider(fakeACMEGetHostsResponse)
+		defer closeServer()
+
+		err := provider.Present("domain.net", "token", "token.thumbprint")
+
+		assert.NoError(t, err)
+		assert.Empty(t, sent.Get("Command"))
+	})
+
+	t.Run("clean_up", func(t *testing.T) {
+		provider, sent, closeServer := setupProvider(fakeACMEGetHostsResponse)
+		defer closeServer()
+
+		err := provider.CleanUp("domain.net", "token", "token.thumbprint")
+		if err != nil {
+			t.Fatal("Unable to clean up challenge", err)
+		}
+
+		assert.Equal(t, "@", sent.Get("HostName1"))
+		assert.Equal(t, "www", sent.Get("HostName2"))
+		assert.Empty(t, sent.Get("HostName3"))
+	})
+
+	t.Run("clean_up_missing", func(t *testing.T) {
+		provider, sent, closeServer := setupProvider(fakeRecordsGetHostsResponse)
+		defer closeServer()
+
+		err := provider.CleanUp("domain.net", "token", "token.thumbprint")
+
+		assert.NoError(t, err)
+		assert.Empty(t, sent.Get("Command"))
+	})
+
+	t.Run("invalid_domain", func(t *testing.T) {
+		provider := NewACMEDNSProvider(setupClient(nil), nil)
+
+		err := provider.Present("localhost", "token", "token.thumbprint")
+
+		assert.EqualError(t, err, "invalid DNS-01 challenge domain localhost: invalid domain: incorrect format")
+	})
+
+	t.Run("get_hosts_error", func(t *testing.T) {
+		provider, _, closeServer := setupProvider(`
+			<?xml version="1.0" encoding="utf-8"?>
+			<ApiResponse Status="ERROR" xmlns="http://api.namecheap.com/xml.response">
+				<Errors>
+					<Error Number="2019166">Domain not found</Error>
+				</Errors>
+			</ApiResponse>
+		`)
+		defer closeServer()
+
+		err := provider.Present("domain.net", "token", "token.thumbprint")
+
+		assert.EqualError(t, err, "unable to present the DNS-01 challenge of domain.net: unable to get the host records of domain.net: Domain not found (2019166)")
+		assert.Empty(t, provider.pending)
+	})
+}
+
+func TestACMEDNSProviderConcurrentChallenges(t *testing.T) {
+	var m sync.Mutex
+	var sentSetHosts []url.Values
+	release := make(chan struct{})
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := ioutil.ReadAll(request.Body)
+		query, _ := url.ParseQuery(string(body))
+
+		switch query.Get("Command") {
+		case "namecheap.domains.dns.getHosts":
+			<-release
+			_, _ = writer.Write([]byte(fakeRecordsGetHostsResponse))
+		case "namecheap.domains.dns.setHosts":
+			m.Lock()
+			sentSetHosts = append(sentSetHosts, query)
+			m.Unlock()
+			_, _ = writer.Write([]byte(fakeRecordsSetHostsResponse))
+		}
+	}))
+	defer mockServer.Close()
+
+	client := setupClient(nil)
+	client.BaseURL = mockServer.URL
+	provider := NewACMEDNSProvider(client, nil)
+
+	pendingChanges := func() int {
+		provider.m.Lock()
+		defer provider.m.Unlock()
+		if batch := provider.pending["domain.net"]; batch != nil {
+			return len(batch.changes)
+		}
+		return 0
+	}
+	waitForPendingChanges := func(count int) {
+		deadline := time.Now().Add(5 * time.Second)
+		for pendingChanges() != count {
+			if time.Now().After(deadline) {
+				t.Fatalf("Timed out waiting for %d pending changes", count)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	present := func(i int, domain string, keyAuth string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = provider.PresentContext(context.TODO(), domain, "token", keyAuth)
+		}()
+	}
+
+	present(0, "domain.net", "token.thumbprint")
+	waitForPendingChanges(1)
+	present(1, "*.domain.net", "wildcard.thumbprint")
+	waitForPendingChanges(2)
+	close(release)
+	wg.Wait()
+
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+	if assert.Len(t, sentSetHosts, 1) {
+		sent := sentSetHosts[0]
+		assert.Equal(t, "_acme-challenge", sent.Get("HostName5"))
+		assert.Equal(t, "61rBZ_4knHblO0MNoxFsXZ_eTFUHum0B6IVRbhvUn5I", sent.Get("Address5"))
+		assert.Equal(t, "_acme-challenge", sent.Get("HostName6"))
+		assert.Equal(t, "s4Qws40yK4HZFkXV46YsG65Q5ZI6eFRxYcRF_Flt91Y", sent.Get("Address6"))
+		assert.Equal(t, "www", sent.Get("HostName4"))
+	}
+	assert.Empty(t, provider.pending)
+}
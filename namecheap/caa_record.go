@@ -0,0 +1,175 @@
+package namecheap
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	CAATagIssue     = "issue"
+	CAATagIssueWild = "issuewild"
+	CAATagIodef     = "iodef"
+)
+
+// CAAFlagCritical is the issuer critical flag, CAs that don't understand the tag must refuse the issuance
+const CAAFlagCritical uint8 = 128
+
+// CAARecord is the content of a CAA host record. It's encoded into DomainsDNSHostRecord.Address,
+// so every record has its own flag and tag, unlike DomainsDNSSetHostsArgs.Flag and Tag which apply to the whole request:
+//
+//	records := []DomainsDNSHostRecord{
+//		CAAIssue("letsencrypt.org").HostRecord("@"),
+//		CAAIssue("sectigo.com").HostRecord("@"),
+//		CAAIodef("mailto:security@domain.net").HostRecord("@"),
+//	}
+type CAARecord struct {
+	// 0, or CAAFlagCritical
+	Flag uint8
+	// Possible values are issue, issuewild or iodef
+	Tag string
+	// Issuer domain with the optional parameters for issue and issuewild, e.g. letsencrypt.org; validationmethods=dns-01,
+	// or ";" to forbid the issuance. The mailto: or http(s):// URL to report the violations to for iodef.
+	Value string
+}
+
+// CAAIssue returns the record authorizing the CA of the issuer domain to issue certificates
+func CAAIssue(issuer string) CAARecord {
+	return CAARecord{Tag: CAATagIssue, Value: issuer}
+}
+
+// CAAIssueWild returns the record authorizing the CA of the issuer domain to issue wildcard certificates
+func CAAIssueWild(issuer string) CAARecord {
+	return CAARecord{Tag: CAATagIssueWild, Value: issuer}
+}
+
+// CAAIodef returns the record with the mailto: or http(s):// URL the CAs report the policy violations to
+func CAAIodef(reportURL string) CAARecord {
+	return CAARecord{Tag: CAATagIodef, Value: reportURL}
+}
+
+// ParseCAAAddress decodes the Address of a CAA host record, e.g. 0 issue "letsencrypt.org".
+// The value may be quoted or not, the tag is converted to lower case. The record isn't validated.
+func ParseCAAAddress(address string) (*CAARecord, error) {
+	fields := strings.Fields(address)
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("invalid CAA address: %s, expected: flag tag value", address)
+	}
+
+	flag, err := strconv.ParseUint(fields[0], 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CAA flag: %s, allowed values are 0 to 255", fields[0])
+	}
+
+	// the value may contain spaces, so cut the flag and the tag off instead of joining the rest of the fields
+	value := strings.TrimSpace(address)
+	value = strings.TrimSpace(value[len(fields[0]):])
+	value = strings.TrimSpace(value[len(fields[1]):])
+
+	if strings.HasPrefix(value, `"`) {
+		if len(value) < 2 || !strings.HasSuffix(value, `"`) {
+			return nil, fmt.Errorf("invalid CAA value: %s, unterminated quoted string", value)
+		}
+		value = strings.Replace(value[1:len(value)-1], `\"`, `"`, -1)
+	}
+
+	return &CAARecord{
+		Flag:  uint8(flag),
+		Tag:   strings.ToLower(fields[1]),
+		Value: value,
+	}, nil
+}
+
+// CAA decodes the CAA record returned by GetHosts
+func (d DomainsDNSHostRecordDetailed) CAA() (*CAARecord, error) {
+	if !strings.EqualFold(d.Type, RecordTypeCAA) {
+		return nil, fmt.Errorf("%s record of host %s isn't a CAA record", d.Type, d.Name)
+	}
+	return ParseCAAAddress(d.Address)
+}
+
+// Address encodes the record the way SetHosts accepts it and GetHosts returns it, e.g. 0 issue "letsencrypt.org"
+func (c CAARecord) Address() string {
+	return fmt.Sprintf(`%d %s "%s"`, c.Flag, strings.ToLower(c.Tag), strings.Replace(c.Value, `"`, `\"`, -1))
+}
+
+// HostRecord returns the CAA host record of the host name, e.g. @
+func (c CAARecord) HostRecord(hostName string) DomainsDNSHostRecord {
+	return DomainsDNSHostRecord{
+		HostName:   hostName,
+		RecordType: RecordTypeCAA,
+		Address:    c.Address(),
+	}
+}
+
+func (c CAARecord) String() string {
+	return c.Address()
+}
+
+// Validate checks the tag, the issuer domain syntax of issue and issuewild, and the iodef URL.
+// The returned error is a *ValidationError listing all the issues.
+func (c CAARecord) Validate() error {
+	issues := &ValidationError{}
+
+	switch strings.ToLower(c.Tag) {
+	case CAATagIssue, CAATagIssueWild:
+		if err := validateCAAIssuer(c.Value); err != nil {
+			issues.add("Value", fmt.Sprintf("invalid Value value: %s, %v", c.Value, err))
+		}
+	case CAATagIodef:
+		if err := validateCAAIodef(c.Value); err != nil {
+			issues.add("Value", fmt.Sprintf("invalid Value value: %s, %v", c.Value, err))
+		}
+	default:
+		issues.add("Tag", fmt.Sprintf("invalid Tag value: %s, allowed values are issue, issuewild, iodef", c.Tag))
+	}
+
+	return issues.errorOrNil()
+}
+
+// validateCAAIssuer checks the issue value: an optional issuer domain followed by the optional ; key=value parameters
+func validateCAAIssuer(value string) error {
+	parts := strings.Split(value, ";")
+
+	issuer := strings.TrimSpace(parts[0])
+	if issuer != "" && !isValidRecordTarget(issuer) {
+		return fmt.Errorf("issuer domain is invalid")
+	}
+
+	for _, parameter := range parts[1:] {
+		parameter = strings.TrimSpace(parameter)
+		if parameter == "" {
+			continue
+		}
+		keyValue := strings.SplitN(parameter, "=", 2)
+		if len(keyValue) != 2 || strings.TrimSpace(keyValue[0]) == "" {
+			return fmt.Errorf("parameter %s must be key=value", parameter)
+		}
+	}
+
+	return nil
+}
+
+// validateCAAIodef checks the iodef value: a mailto: or http(s):// URL
+func validateCAAIodef(value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("URL is invalid")
+	}
+
+	switch parsed.Scheme {
+	case "mailto":
+		if !strings.Contains(parsed.Opaque, "@") {
+			return fmt.Errorf("mailto: URL must contain an email address")
+		}
+	case "http", "https":
+		if parsed.Host == "" {
+			return fmt.Errorf("%s:// URL must contain a host", parsed.Scheme)
+		}
+	default:
+		return fmt.Errorf("URL must start with mailto:, http:// or https://")
+	}
+
+	return nil
+}
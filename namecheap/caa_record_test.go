@@ -0,0 +1,292 @@
+package namecheap
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCAARecordAddress(t *testing.T) {
+	var cases = map[string]struct {
+		Record   CAARecord
+		Expected string
+	}{
+		"issue": {
+			Record:   CAAIssue("letsencrypt.org"),
+			Expected: `0 issue "letsencrypt.org"`,
+		},
+		"issuewild": {
+			Record:   CAAIssueWild("sectigo.com"),
+			Expected: `0 issuewild "sectigo.com"`,
+		},
+		"iodef": {
+			Record:   CAAIodef("mailto:security@domain.net"),
+			Expected: `0 iodef "mailto:security@domain.net"`,
+		},
+		"critical_upper_case_tag": {
+			Record:   CAARecord{Flag: CAAFlagCritical, Tag: "ISSUE", Value: "letsencrypt.org"},
+			Expected: `128 issue "letsencrypt.org"`,
+		},
+		"forbid_issuance": {
+			Record:   CAAIssue(";"),
+			Expected: `0 issue ";"`,
+		},
+		"escaped_quote": {
+			Record:   CAARecord{Tag: CAATagIssue, Value: `ca.net; note="x"`},
+			Expected: `0 issue "ca.net; note=\"x\""`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.Expected, c.Record.Address())
+			assert.Equal(t, c.Expected, c.Record.String())
+		})
+	}
+
+	t.Run("host_record", func(t *testing.T) {
+		record := CAAIssue("letsencrypt.org").HostRecord("@")
+
+		assert.Equal(t, DomainsDNSHostRecord{HostName: "@", RecordType: RecordTypeCAA, Address: `0 issue "letsencrypt.org"`}, record)
+	})
+}
+
+func TestParseCAAAddress(t *testing.T) {
+	var cases = map[string]struct {
+		Address  string
+		Expected CAARecord
+	}{
+		"quoted": {
+			Address:  `0 issue "letsencrypt.org"`,
+			Expected: CAARecord{Flag: 0, Tag: "issue", Value: "letsencrypt.org"},
+		},
+		"unquoted": {
+			Address:  `0 issuewild sectigo.com`,
+			Expected: CAARecord{Flag: 0, Tag: "issuewild", Value: "sectigo.com"},
+		},
+		"critical_upper_case_tag": {
+			Address:  `128 IODEF "https://domain.net/caa"`,
+			Expected: CAARecord{Flag: 128, Tag: "iodef", Value: "https://domain.net/caa"},
+		},
+		"spaces_in_value": {
+			Address:  `  0  issue  "ca.net; account=123; validationmethods=dns-01"  `,
+			Expected: CAARecord{Flag: 0, Tag: "issue", Value: "ca.net; account=123; validationmethods=dns-01"},
+		},
+		"escaped_quote": {
+			Address:  `0 issue "ca.net; note=\"x\""`,
+			Expected: CAARecord{Flag: 0, Tag: "issue", Value: `ca.net; note="x"`},
+		},
+		"empty_value": {
+			Address:  `0 issue ""`,
+			Expected: CAARecord{Flag: 0, Tag: "issue", Value: ""},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			record, err := ParseCAAAddress(c.Address)
+			if err != nil {
+				t.Fatal("Unable to parse CAA address", err)
+			}
+
+			assert.Equal(t, c.Expected, *record)
+		})
+	}
+
+	var errorCases = map[string]struct {
+		Address       string
+		ExpectedError string
+	}{
+		"missing_tag": {
+			Address:       `0`,
+			ExpectedError: "invalid CAA address: 0, expected: flag tag value",
+		},
+		"invalid_flag": {
+			Address:       `256 issue "letsencrypt.org"`,
+			ExpectedError: "invalid CAA flag: 256, allowed values are 0 to 255",
+		},
+		"unterminated_quote": {
+			Address:       `0 issue "letsencrypt.org`,
+			ExpectedError: `invalid CAA value: "letsencrypt.org, unterminated quoted string`,
+		},
+	}
+
+	for name, c := range errorCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseCAAAddress(c.Address)
+
+			assert.EqualError(t, err, c.ExpectedError)
+		})
+	}
+
+	t.Run("round_trip", func(t *testing.T) {
+		records := []CAARecord{
+			CAAIssue("letsencrypt.org"),
+			CAAIssueWild(";"),
+			CAAIodef("https://domain.net/caa?report=1"),
+			{Flag: CAAFlagCritical, Tag: CAATagIssue, Value: `ca.net; note="x"`},
+		}
+
+		for _, record := range records {
+			parsed, err := ParseCAAAddress(record.Address())
+			if err != nil {
+				t.Fatal("Unable to parse CAA address", err)
+			}
+
+			assert.Equal(t, record, *parsed)
+		}
+	})
+}
+
+func TestDomainsDNSHostRecordDetailedCAA(t *testing.T) {
+	t.Run("caa", func(t *testing.T) {
+		detailed := DomainsDNSHostRecordDetailed{Name: "@", Type: "CAA", Address: `0 issue "letsencrypt.org"`, MXPref: 10, TTL: 3600}
+
+		record, err := detailed.CAA()
+		if err != nil {
+			t.Fatal("Unable to decode CAA record", err)
+		}
+
+		assert.Equal(t, CAAIssue("letsencrypt.org"), *record)
+	})
+
+	t.Run("not_caa", func(t *testing.T) {
+		detailed := DomainsDNSHostRecordDetailed{Name: "www", Type: "A", Address: "192.0.2.1"}
+
+		_, err := detailed.CAA()
+
+		assert.EqualError(t, err, "A record of host www isn't a CAA record")
+	})
+}
+
+func TestCAARecordValidate(t *testing.T) {
+	var validCases = map[string]CAARecord{
+		"issuer":            CAAIssue("letsencrypt.org"),
+		"issuer_parameters": CAAIssue("letsencrypt.org; validationmethods=dns-01; accounturi=https://acme.net/acct/1"),
+		"forbid_issuance":   CAAIssueWild(";"),
+		"empty_issuer":      CAAIssue(""),
+		"iodef_mailto":      CAAIodef("mailto:security@domain.net"),
+		"iodef_https":       CAAIodef("https://domain.net/caa"),
+		"upper_case_tag":    {Tag: "ISSUE", Value: "sectigo.com"},
+	}
+
+	for name, record := range validCases {
+		t.Run(name, func(t *testing.T) {
+			assert.NoError(t, record.Validate())
+		})
+	}
+
+	var errorCases = map[string]struct {
+		Record         CAARecord
+		ExpectedError  string
+		ExpectedFields []string
+	}{
+		"invalid_issuer": {
+			Record:         CAAIssue("lets encrypt"),
+			ExpectedError:  "invalid Value value: lets encrypt, issuer domain is invalid",
+			ExpectedFields: []string{"Value"},
+		},
+		"invalid_parameter": {
+			Record:         CAAIssue("letsencrypt.org; dns-01"),
+			ExpectedError:  "invalid Value value: letsencrypt.org; dns-01, parameter dns-01 must be key=value",
+			ExpectedFields: []string{"Value"},
+		},
+		"iodef_mailto_without_address": {
+			Record:         CAAIodef("mailto:security"),
+			ExpectedError:  "invalid Value value: mailto:security, mailto: URL must contain an email address",
+			ExpectedFields: []string{"Value"},
+		},
+		"iodef_without_host": {
+			Record:         CAAIodef("https:///caa"),
+			ExpectedError:  "invalid Value value: https:///caa, https:// URL must contain a host",
+			ExpectedFields: []string{"Value"},
+		},
+		"iodef_unsupported_scheme": {
+			Record:         CAAIodef("ftp://domain.net"),
+			ExpectedError:  "invalid Value value: ftp://domain.net, URL must start with mailto:, http:// or https://",
+			ExpectedFields: []string{"Value"},
+		},
+		"unknown_tag": {
+			Record:         CAARecord{Tag: "contactemail", Value: "security@domain.net"},
+			ExpectedError:  "invalid Tag value: contactemail, allowed values are issue, issuewild, iodef",
+			ExpectedFields: []string{"Tag"},
+		},
+	}
+
+	for name, c := range errorCases {
+		t.Run(name, func(t *testing.T) {
+			err := c.Record.Validate()
+
+			assert.EqualError(t, err, c.ExpectedError)
+			if validationErr, ok := err.(*ValidationError); assert.True(t, ok) {
+				assert.Equal(t, c.ExpectedFields, validationErr.Fields())
+			}
+		})
+	}
+}
+
+func TestCAARecordSetHosts(t *testing.T) {
+	t.Run("multiple_cas", func(t *testing.T) {
+		sentSetHosts := url.Values{}
+		mockServer := newRecordsMockServer(fakeRecordsGetHostsResponse, &sentSetHosts)
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		_, err := client.DomainsDNS.SetHosts(context.TODO(), &DomainsDNSSetHostsArgs{
+			Domain: "domain.net",
+			Records: []DomainsDNSHostRecord{
+				CAAIssue("letsencrypt.org").HostRecord("@"),
+				CAAIssue("sectigo.com").HostRecord("@"),
+				CAAIssueWild(";").HostRecord("@"),
+				CAAIodef("mailto:security@domain.net").HostRecord("@"),
+			},
+		})
+		if err != nil {
+			t.Fatal("Unable to set hosts", err)
+		}
+
+		assert.Equal(t, `0 issue "letsencrypt.org"`, sentSetHosts.Get("Address1"))
+		assert.Equal(t, `0 issue "sectigo.com"`, sentSetHosts.Get("Address2"))
+		assert.Equal(t, `0 issuewild ";"`, sentSetHosts.Get("Address3"))
+		assert.Equal(t, `0 iodef "mailto:security@domain.net"`, sentSetHosts.Get("Address4"))
+		assert.Equal(t, "CAA", sentSetHosts.Get("RecordType4"))
+		assert.Empty(t, sentSetHosts.Get("Flag"))
+		assert.Empty(t, sentSetHosts.Get("Tag"))
+	})
+
+	t.Run("invalid_issuer", func(t *testing.T) {
+		client := setupClient(nil)
+
+		_, err := client.DomainsDNS.SetHosts(context.TODO(), &DomainsDNSSetHostsArgs{
+			Domain:  "domain.net",
+			Records: []DomainsDNSHostRecord{CAAIssue("lets encrypt").HostRecord("@")},
+		})
+
+		assert.EqualError(t, err, `Records[0].Address "0 issue "lets encrypt"" is invalid: invalid Value value: lets encrypt, issuer domain is invalid`)
+	})
+
+	t.Run("decode_get_hosts", func(t *testing.T) {
+		sentSetHosts := url.Values{}
+		mockServer := newRecordsMockServer(fakeRecordsGetHostsResponse, &sentSetHosts)
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		response, err := client.DomainsDNS.GetHosts(context.TODO(), "domain.net")
+		if err != nil {
+			t.Fatal("Unable to get hosts", err)
+		}
+
+		record, err := response.DomainDNSGetHostsResult.Hosts[2].CAA()
+		if err != nil {
+			t.Fatal("Unable to decode CAA record", err)
+		}
+
+		assert.Equal(t, CAAIssue("letsencrypt.org"), *record)
+	})
+}
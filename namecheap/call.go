@@ -0,0 +1,137 @@
+package namecheap
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+const (
+	ResponseStatusOK    = "OK"
+	ResponseStatusError = "ERROR"
+)
+
+// CallResponse is a generic representation of the API response envelope.
+// It's returned by Client.Call for the commands that have no dedicated method in the SDK.
+type CallResponse struct {
+	XMLName          xml.Name         `xml:"ApiResponse"`
+	Status           string           `xml:"Status,attr"`
+	Errors           []ResponseStatus `xml:"Errors>Error"`
+	Warnings         []ResponseStatus `xml:"Warnings>Warning"`
+	RequestedCommand string           `xml:"RequestedCommand"`
+	CommandResponse  *XMLNode         `xml:"CommandResponse"`
+	Server           string           `xml:"Server"`
+	ExecutionTime    string           `xml:"ExecutionTime"`
+
+	// Raw is the unparsed response body
+	Raw []byte `xml:"-"`
+}
+
+// ResponseStatus is an error or a warning item of the API response envelope
+type ResponseStatus struct {
+	Message string `xml:",chardata"`
+	Number  string `xml:"Number,attr"`
+}
+
+func (r ResponseStatus) String() string {
+	return fmt.Sprintf("%s (%s)", r.Message, r.Number)
+}
+
+// XMLNode is a generic parsed XML element
+type XMLNode struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Content string     `xml:",chardata"`
+	Nodes   []XMLNode  `xml:",any"`
+}
+
+// Name returns the local name of the element
+func (n *XMLNode) Name() string {
+	return n.XMLName.Local
+}
+
+// Attr returns the value of the attribute with the given local name (case-insensitive)
+// and reports whether the attribute exists
+func (n *XMLNode) Attr(name string) (string, bool) {
+	for _, attr := range n.Attrs {
+		if strings.EqualFold(attr.Name.Local, name) {
+			return attr.Value, true
+		}
+	}
+	return "", false
+}
+
+// Text returns the trimmed character data of the element
+func (n *XMLNode) Text() string {
+	return strings.TrimSpace(n.Content)
+}
+
+// Find returns the first descendant element matching the slash-separated path of
+// local names (case-insensitive), e.g. "DomainGetInfoResult/DnsDetails", or nil if there's no such element
+func (n *XMLNode) Find(path string) *XMLNode {
+	nodes := n.FindAll(path)
+	if len(nodes) == 0 {
+		return nil
+	}
+	return nodes[0]
+}
+
+// FindAll returns all descendant elements matching the slash-separated path of local names (case-insensitive)
+func (n *XMLNode) FindAll(path string) []*XMLNode {
+	current := []*XMLNode{n}
+	for _, name := range strings.Split(strings.Trim(path, "/"), "/") {
+		var next []*XMLNode
+		for _, node := range current {
+			for i := range node.Nodes {
+				if strings.EqualFold(node.Nodes[i].XMLName.Local, name) {
+					next = append(next, &node.Nodes[i])
+				}
+			}
+		}
+		current = next
+	}
+	return current
+}
+
+// Call sends an arbitrary API command with the given params and returns the parsed envelope.
+// It's an escape hatch for the commands the SDK doesn't wrap yet. Authentication and the retry policy
+// are applied the same way as for the typed methods, and the params map isn't modified.
+//
+// When the API responds with errors, both the response and the error describing them are returned.
+//
+// Namecheap doc: https://www.namecheap.com/support/api/methods/
+func (c *Client) Call(ctx context.Context, command string, params map[string]string) (*CallResponse, error) {
+	if command == "" {
+		return nil, fmt.Errorf("command is required")
+	}
+
+	body := make(map[string]string, len(params)+1)
+	for k, v := range params {
+		body[k] = v
+	}
+	body["Command"] = command
+
+	_, rawBody, err := c.do(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var response CallResponse
+	err = decodeBody(bytes.NewReader(rawBody), &response)
+	if err != nil {
+		return nil, err
+	}
+	response.Raw = rawBody
+
+	if len(response.Errors) > 0 {
+		errMessages := []string{}
+		for _, e := range response.Errors {
+			errMessages = append(errMessages, e.String())
+		}
+		return &response, fmt.Errorf("%s", strings.Join(errMessages, "; "))
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,131 @@
+package namecheap
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCall(t *testing.T) {
+	fakeResponse := `
+		<?xml version="1.0" encoding="utf-8"?>
+		<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+			<Errors />
+			<Warnings />
+			<RequestedCommand>namecheap.domains.getregistrarlock</RequestedCommand>
+			<CommandResponse Type="namecheap.domains.getRegistrarLock">
+				<DomainGetRegistrarLockResult Domain="domain.com" RegistrarLockStatus="true">
+					<Note>first</Note>
+					<Note>second</Note>
+				</DomainGetRegistrarLockResult>
+			</CommandResponse>
+			<Server>PHX01SBAPIEXT05</Server>
+			<GMTTimeDifference>--4:00</GMTTimeDifference>
+			<ExecutionTime>0.011</ExecutionTime>
+		</ApiResponse>
+	`
+
+	t.Run("request_command_and_params", func(t *testing.T) {
+		var sentBody url.Values
+
+		mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			body, _ := ioutil.ReadAll(request.Body)
+			query, _ := url.ParseQuery(string(body))
+			sentBody = query
+			_, _ = writer.Write([]byte(fakeResponse))
+		}))
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		params := map[string]string{"DomainName": "domain.com"}
+
+		_, err := client.Call(context.TODO(), "namecheap.domains.getRegistrarLock", params)
+		if err != nil {
+			t.Fatal("Unable to call command", err)
+		}
+
+		assert.Equal(t, "namecheap.domains.getRegistrarLock", sentBody.Get("Command"))
+		assert.Equal(t, "domain.com", sentBody.Get("DomainName"))
+		assert.Equal(t, ncApiKey, sentBody.Get("ApiKey"))
+		assert.Equal(t, map[string]string{"DomainName": "domain.com"}, params)
+	})
+
+	t.Run("response_parsing", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			_, _ = writer.Write([]byte(fakeResponse))
+		}))
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		response, err := client.Call(context.TODO(), "namecheap.domains.getRegistrarLock", nil)
+		if err != nil {
+			t.Fatal("Unable to call command", err)
+		}
+
+		assert.Equal(t, ResponseStatusOK, response.Status)
+		assert.Equal(t, "namecheap.domains.getregistrarlock", response.RequestedCommand)
+		assert.Contains(t, string(response.Raw), "DomainGetRegistrarLockResult")
+
+		result := response.CommandResponse.Find("DomainGetRegistrarLockResult")
+		if assert.NotNil(t, result) {
+			status, ok := result.Attr("RegistrarLockStatus")
+			assert.True(t, ok)
+			assert.Equal(t, "true", status)
+		}
+
+		notes := response.CommandResponse.FindAll("domaingetregistrarlockresult/note")
+		if assert.Len(t, notes, 2) {
+			assert.Equal(t, "first", notes[0].Text())
+			assert.Equal(t, "second", notes[1].Text())
+		}
+
+		assert.Nil(t, response.CommandResponse.Find("DomainGetRegistrarLockResult/Unknown"))
+	})
+
+	t.Run("errors_parsing", func(t *testing.T) {
+		fakeResponse := `
+			<?xml version="1.0" encoding="utf-8"?>
+			<ApiResponse Status="ERROR" xmlns="http://api.namecheap.com/xml.response">
+				<Errors>
+					<Error Number="2019166">Domain not found</Error>
+					<Error Number="2016166">Domain is not associated with your account</Error>
+				</Errors>
+				<Warnings />
+				<RequestedCommand>namecheap.domains.getregistrarlock</RequestedCommand>
+			</ApiResponse>
+		`
+
+		mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			_, _ = writer.Write([]byte(fakeResponse))
+		}))
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		response, err := client.Call(context.TODO(), "namecheap.domains.getRegistrarLock", nil)
+
+		assert.EqualError(t, err, "Domain not found (2019166); Domain is not associated with your account (2016166)")
+		if assert.NotNil(t, response) {
+			assert.Equal(t, ResponseStatusError, response.Status)
+			assert.Len(t, response.Errors, 2)
+		}
+	})
+
+	t.Run("empty_command", func(t *testing.T) {
+		client := setupClient(nil)
+
+		_, err := client.Call(context.TODO(), "", nil)
+
+		assert.EqualError(t, err, "command is required")
+	})
+}
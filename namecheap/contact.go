@@ -0,0 +1,175 @@
+package namecheap
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	ContactRoleRegistrant = "Registrant"
+	ContactRoleTech       = "Tech"
+	ContactRoleAdmin      = "Admin"
+	ContactRoleAuxBilling = "AuxBilling"
+	ContactRoleBilling    = "Billing"
+)
+
+// RequiredContactRoles are the contact roles required to register a domain
+var RequiredContactRoles = []string{ContactRoleRegistrant, ContactRoleTech, ContactRoleAdmin, ContactRoleAuxBilling}
+
+var validPhoneFormat = regexp.MustCompile(`^\+[0-9]{1,3}\.[0-9]{1,14}$`)
+
+// Contact is a domain contact. The same set of fields is used for the Registrant, Tech, Admin, AuxBilling and Billing roles.
+type Contact struct {
+	OrganizationName    string // Optional, max 255 characters
+	JobTitle            string // Optional, max 255 characters
+	FirstName           string // Required, max 255 characters
+	LastName            string // Required, max 255 characters
+	Address1            string // Required, max 255 characters
+	Address2            string // Optional, max 255 characters
+	City                string // Required, max 50 characters
+	StateProvince       string // Required, max 50 characters
+	StateProvinceChoice string // Optional, max 50 characters
+	PostalCode          string // Required, max 50 characters
+	Country             string // Required, ISO 3166-1 alpha-2 code, e.g. US
+	Phone               string // Required, in the format +NNN.NNNNNNNNNN
+	PhoneExt            string // Optional, max 50 characters
+	Fax                 string // Optional, in the format +NNN.NNNNNNNNNN
+	EmailAddress        string // Required, max 255 characters
+}
+
+// Validate checks the required fields, the field lengths, the phone and fax format, the country code and the email address.
+// The returned *ValidationError names every offending field.
+func (c Contact) Validate() error {
+	issues := &ValidationError{}
+	c.validate("", issues)
+	return issues.errorOrNil()
+}
+
+// IsEmpty reports whether none of the fields is set
+func (c Contact) IsEmpty() bool {
+	return c == Contact{}
+}
+
+func (c Contact) validate(prefix string, issues *ValidationError) {
+	fields := []struct {
+		name      string
+		value     string
+		required  bool
+		maxLength int
+	}{
+		{"OrganizationName", c.OrganizationName, false, 255},
+		{"JobTitle", c.JobTitle, false, 255},
+		{"FirstName", c.FirstName, true, 255},
+		{"LastName", c.LastName, true, 255},
+		{"Address1", c.Address1, true, 255},
+		{"Address2", c.Address2, false, 255},
+		{"City", c.City, true, 50},
+		{"StateProvince", c.StateProvince, true, 50},
+		{"StateProvinceChoice", c.StateProvinceChoice, false, 50},
+		{"PostalCode", c.PostalCode, true, 50},
+		{"Country", c.Country, true, 50},
+		{"Phone", c.Phone, true, 50},
+		{"PhoneExt", c.PhoneExt, false, 50},
+		{"Fax", c.Fax, false, 50},
+		{"EmailAddress", c.EmailAddress, true, 255},
+	}
+
+	for _, field := range fields {
+		name := prefix + field.name
+		if strings.TrimSpace(field.value) == "" {
+			if field.required {
+				issues.add(name, fmt.Sprintf("%s is required", name))
+			}
+			continue
+		}
+		if utf8.RuneCountInString(field.value) > field.maxLength {
+			issues.add(name, fmt.Sprintf("invalid %s value: maximum length is %d characters", name, field.maxLength))
+		}
+	}
+
+	if c.Country != "" && !isValidCountryCode(c.Country) {
+		issues.add(prefix+"Country", fmt.Sprintf("invalid %sCountry value: %s, must be an ISO 3166-1 alpha-2 code", prefix, c.Country))
+	}
+
+	if c.Phone != "" && !validPhoneFormat.MatchString(c.Phone) {
+		issues.add(prefix+"Phone", fmt.Sprintf("invalid %sPhone value: %s, must be in the format +NNN.NNNNNNNNNN", prefix, c.Phone))
+	}
+
+	if c.Fax != "" && !validPhoneFormat.MatchString(c.Fax) {
+		issues.add(prefix+"Fax", fmt.Sprintf("invalid %sFax value: %s, must be in the format +NNN.NNNNNNNNNN", prefix, c.Fax))
+	}
+
+	if c.EmailAddress != "" {
+		address, err := mail.ParseAddress(c.EmailAddress)
+		if err != nil || address.Address != c.EmailAddress {
+			issues.add(prefix+"EmailAddress", fmt.Sprintf("invalid %sEmailAddress value: %s", prefix, c.EmailAddress))
+		}
+	}
+}
+
+// toParams adds the contact fields prefixed with the role to the params.
+// The fields are only sent if not empty, the required ones are checked by the validation.
+func (c Contact) toParams(role string, params map[string]string) {
+	values := map[string]string{
+		"OrganizationName":    c.OrganizationName,
+		"JobTitle":            c.JobTitle,
+		"FirstName":           c.FirstName,
+		"LastName":            c.LastName,
+		"Address1":            c.Address1,
+		"Address2":            c.Address2,
+		"City":                c.City,
+		"StateProvince":       c.StateProvince,
+		"StateProvinceChoice": c.StateProvinceChoice,
+		"PostalCode":          c.PostalCode,
+		"Country":             c.Country,
+		"Phone":               c.Phone,
+		"PhoneExt":            c.PhoneExt,
+		"Fax":                 c.Fax,
+		"EmailAddress":        c.EmailAddress,
+	}
+
+	for name, value := range values {
+		if value != "" {
+			params[role+name] = value
+		}
+	}
+}
+
+func isValidCountryCode(code string) bool {
+	if len(code) != 2 {
+		return false
+	}
+	return strings.Contains(iso3166Alpha2Codes, " "+strings.ToUpper(code)+" ")
+}
+
+// iso3166Alpha2Codes is the space-separated list of the officially assigned ISO 3166-1 alpha-2 codes,
+// including UK, which registries accept as an alias of GB
+const iso3166Alpha2Codes = " " +
+	"AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ " +
+	"BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ BR BS BT BV BW BY BZ " +
+	"CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ " +
+	"DE DJ DK DM DO DZ " +
+	"EC EE EG EH ER ES ET " +
+	"FI FJ FK FM FO FR " +
+	"GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY " +
+	"HK HM HN HR HT HU " +
+	"ID IE IL IM IN IO IQ IR IS IT " +
+	"JE JM JO JP " +
+	"KE KG KH KI KM KN KP KR KW KY KZ " +
+	"LA LB LC LI LK LR LS LT LU LV LY " +
+	"MA MC MD ME MF MG MH MK ML MM MN MO MP MQ MR MS MT MU MV MW MX MY MZ " +
+	"NA NC NE NF NG NI NL NO NP NR NU NZ " +
+	"OM " +
+	"PA PE PF PG PH PK PL PM PN PR PS PT PW PY " +
+	"QA " +
+	"RE RO RS RU RW " +
+	"SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ " +
+	"TC TD TF TG TH TJ TK TL TM TN TO TR TT TV TW TZ " +
+	"UA UG UK UM US UY UZ " +
+	"VA VC VE VG VI VN VU " +
+	"WF WS " +
+	"YE YT " +
+	"ZA ZM ZW "
@@ -0,0 +1,143 @@
+package namecheap
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var fakeContact = Contact{
+	FirstName:     "Jane",
+	LastName:      "Smith",
+	Address1:      "16 Pennsylvania St",
+	City:          "Not Lake City",
+	StateProvince: "UM",
+	PostalCode:    "02134",
+	Country:       "US",
+	Phone:         "+1.2145550000",
+	EmailAddress:  "webmaster@example.com",
+}
+
+func TestContactValidate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		assert.Nil(t, fakeContact.Validate())
+	})
+
+	t.Run("all_issues_listed", func(t *testing.T) {
+		contact := fakeContact
+		contact.FirstName = ""
+		contact.Phone = "12145550000"
+		contact.Country = "XX"
+		contact.EmailAddress = "webmaster"
+
+		err := contact.Validate()
+
+		var validationErr *ValidationError
+		if assert.True(t, errors.As(err, &validationErr)) {
+			assert.Equal(t, []string{"FirstName", "Country", "Phone", "EmailAddress"}, validationErr.Fields())
+		}
+		assert.EqualError(t, err, "FirstName is required; "+
+			"invalid Country value: XX, must be an ISO 3166-1 alpha-2 code; "+
+			"invalid Phone value: 12145550000, must be in the format +NNN.NNNNNNNNNN; "+
+			"invalid EmailAddress value: webmaster")
+	})
+}
+
+func TestDomainCreateArgsValidate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		args := NewDomainCreateArgs("domain.com", 1, fakeContact)
+		assert.Nil(t, args.Validate())
+	})
+
+	t.Run("field_names_prefixed_with_role", func(t *testing.T) {
+		args := NewDomainCreateArgs("domain", 11, fakeContact)
+		args.RegistrantPhone = "+1-214-555-0000"
+		args.AdminEmailAddress = ""
+		args.BillingFirstName = "Jane"
+
+		err := args.Validate()
+
+		var validationErr *ValidationError
+		if assert.True(t, errors.As(err, &validationErr)) {
+			assert.Equal(t, []string{
+				"DomainName", "Years", "RegistrantPhone", "AdminEmailAddress",
+				"BillingLastName", "BillingAddress1", "BillingCity", "BillingStateProvince",
+				"BillingPostalCode", "BillingCountry", "BillingPhone", "BillingEmailAddress",
+			}, validationErr.Fields())
+		}
+		assert.Contains(t, err.Error(), "invalid RegistrantPhone value: +1-214-555-0000, must be in the format +NNN.NNNNNNNNNN")
+		assert.Contains(t, err.Error(), "AdminEmailAddress is required")
+	})
+
+	t.Run("create_not_sent_when_invalid", func(t *testing.T) {
+		var called bool
+		mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			called = true
+		}))
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		_, err := client.Domains.Create(context.TODO(), DomainCreateArgs{DomainName: "domain.com", Years: 1})
+
+		assert.NotNil(t, err)
+		assert.False(t, called)
+	})
+}
+
+func TestDomainCreateArgsContacts(t *testing.T) {
+	t.Run("same_contact_for_all_roles", func(t *testing.T) {
+		args := NewDomainCreateArgs("domain.com", 2, fakeContact)
+
+		for _, role := range RequiredContactRoles {
+			assert.Equal(t, fakeContact, args.GetContact(role), role)
+		}
+		assert.True(t, args.GetContact(ContactRoleBilling).IsEmpty())
+	})
+
+	t.Run("billing_has_no_organization", func(t *testing.T) {
+		var args DomainCreateArgs
+		contact := fakeContact
+		contact.OrganizationName = "NC"
+		args.SetContact(ContactRoleBilling, contact)
+
+		assert.Equal(t, fakeContact, args.GetContact(ContactRoleBilling))
+	})
+
+	t.Run("params", func(t *testing.T) {
+		var sentBody string
+		mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			body, _ := ioutil.ReadAll(request.Body)
+			sentBody = string(body)
+			_, _ = writer.Write([]byte(newPolicyTestCreateResponse("domain.com", "20.61")))
+		}))
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		args := NewDomainCreateArgs("domain.com", 2, fakeContact)
+		args.AuxBillingOrganizationName = "NC"
+
+		_, err := client.Domains.Create(context.TODO(), args)
+		if err != nil {
+			t.Fatal("Unable to create domain", err)
+		}
+
+		query, _ := url.ParseQuery(sentBody)
+		assert.Equal(t, "domain.com", query.Get("DomainName"))
+		assert.Equal(t, "2", query.Get("Years"))
+		assert.Equal(t, "+1.2145550000", query.Get("TechPhone"))
+		assert.Equal(t, "NC", query.Get("AuxBillingOrganizationName"))
+		assert.Equal(t, []string{"webmaster@example.com"}, query["AuxBillingEmailAddress"])
+		assert.Empty(t, query.Get("RegistrantOrganizationName"))
+		assert.NotContains(t, query, "BillingFirstName")
+	})
+}
@@ -0,0 +1,316 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var allowedEstimateActionValues = []string{ActionNameRegister, ActionNameRenew, ActionNameTransfer, ActionNameReactivate}
+
+// PlannedOperation is a single purchase of a cart to be estimated
+type PlannedOperation struct {
+	// Possible values are REGISTER, RENEW, TRANSFER, REACTIVATE
+	Action string
+	// Domain name, e.g. domain.com
+	Domain string
+	// Number of years, minimum value is 1, and maximum value is 10.
+	// TRANSFER and REACTIVATE are charged once, at the price of a single year
+	// Default value: 1
+	Years int
+}
+
+func (o PlannedOperation) String() string {
+	return fmt.Sprintf("%s %s for %d year(s)", strings.ToLower(o.Action), o.Domain, o.Years)
+}
+
+// CostEstimateArgs struct is an input arguments for CostEstimator.Estimate function
+type CostEstimateArgs struct {
+	// Operations to be estimated
+	Operations []PlannedOperation
+	// Promotional (coupon) code to be applied to the regular prices
+	PromotionCode string
+	// Whether to compare the total against the account balance
+	CompareBalance bool
+}
+
+// CostEstimateItem is the price of a single PlannedOperation
+type CostEstimateItem struct {
+	Operation PlannedOperation
+	// Whether the premium price of the domain has been used
+	IsPremium bool
+	// Price of the operation for all the years, excluding fees
+	Price Money
+	// ICANN fee for all the years
+	IcannFee Money
+	// Early Access Program fee
+	EapFee Money
+	// Price and the fees together
+	Total Money
+	// Issues that may prevent the operation, e.g. the domain isn't available for registration
+	Warnings []string
+}
+
+// CostEstimate is an itemized quote of a cart
+type CostEstimate struct {
+	Items []CostEstimateItem
+	Total Money
+
+	// The fields below are only set when CostEstimateArgs.CompareBalance is true
+
+	// Available balance of the account
+	AvailableBalance *Money
+	// Whether the available balance covers the total
+	IsBalanceSufficient bool
+	// Amount missing from the available balance, zero when the balance is sufficient
+	Shortfall Money
+}
+
+// Warnings returns the warnings of all the items
+func (e CostEstimate) Warnings() []string {
+	var warnings []string
+	for _, item := range e.Items {
+		for _, warning := range item.Warnings {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", item.Operation.Domain, warning))
+		}
+	}
+	return warnings
+}
+
+// CostEstimator quotes carts of registrations, renewals, transfers and reactivations.
+// Regular prices come from a PricingCatalog, premium prices and fees from DomainsService.CheckMany.
+type CostEstimator struct {
+	client         *Client
+	catalogOptions PricingCatalogOptions
+
+	m        sync.Mutex
+	catalogs map[string]*PricingCatalog
+}
+
+// NewCostEstimator returns a new CostEstimator. The catalog options are used for the pricing without a promotion code,
+// the promotion code pricing is only cached in memory.
+func NewCostEstimator(client *Client, catalogOptions *PricingCatalogOptions) *CostEstimator {
+	estimator := &CostEstimator{
+		client:   client,
+		catalogs: map[string]*PricingCatalog{},
+	}
+
+	if catalogOptions != nil {
+		estimator.catalogOptions = *catalogOptions
+	}
+	estimator.catalogOptions.ProductType = ProductTypeDomain
+	estimator.catalogOptions.PromotionCode = ""
+
+	return estimator
+}
+
+// NewCostEstimatorWithCatalog returns a new CostEstimator using the given domain pricing catalog
+// for the carts without a promotion code
+func NewCostEstimatorWithCatalog(client *Client, catalog *PricingCatalog) *CostEstimator {
+	estimator := NewCostEstimator(client, &catalog.options)
+	estimator.catalogs[""] = catalog
+	return estimator
+}
+
+func (ce *CostEstimator) catalog(promotionCode string) *PricingCatalog {
+	ce.m.Lock()
+	defer ce.m.Unlock()
+
+	catalog, ok := ce.catalogs[promotionCode]
+	if !ok {
+		options := ce.catalogOptions
+		if promotionCode != "" {
+			options.PromotionCode = promotionCode
+			options.CacheFile = ""
+		}
+		catalog = NewPricingCatalog(ce.client, &options)
+		ce.catalogs[promotionCode] = catalog
+	}
+
+	return catalog
+}
+
+// Estimate returns an itemized quote of the operations including ICANN and EAP fees
+func (ce *CostEstimator) Estimate(ctx context.Context, args *CostEstimateArgs) (*CostEstimate, error) {
+	if args == nil || len(args.Operations) == 0 {
+		return nil, fmt.Errorf("Operations are required")
+	}
+
+	operations, err := validatePlannedOperations(args.Operations)
+	if err != nil {
+		return nil, err
+	}
+
+	domains := make([]string, 0, len(operations))
+	for _, operation := range operations {
+		domains = append(domains, operation.Domain)
+	}
+
+	checkResults, err := ce.client.Domains.CheckMany(ctx, &DomainsCheckManyArgs{DomainList: domains})
+	if err != nil {
+		return nil, err
+	}
+
+	checkByDomain := map[string]DomainCheckResult{}
+	for _, result := range checkResults {
+		checkByDomain[strings.ToLower(result.Domain)] = result
+	}
+
+	catalog := ce.catalog(args.PromotionCode)
+	estimate := &CostEstimate{}
+
+	for _, operation := range operations {
+		check, ok := checkByDomain[operation.Domain]
+		if !ok {
+			return nil, fmt.Errorf("no availability result for %s", operation.Domain)
+		}
+
+		item, err := estimateOperation(ctx, catalog, operation, check)
+		if err != nil {
+			return nil, err
+		}
+
+		estimate.Total, err = estimate.Total.Add(item.Total)
+		if err != nil {
+			return nil, err
+		}
+		estimate.Items = append(estimate.Items, *item)
+	}
+
+	if args.CompareBalance {
+		balances, err := ce.client.UsersService.GetBalances(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		available := balances.AvailableBalance
+		estimate.AvailableBalance = &available
+
+		cmp, err := estimate.Total.Cmp(available)
+		if err != nil {
+			return nil, err
+		}
+
+		estimate.IsBalanceSufficient = cmp <= 0
+		if !estimate.IsBalanceSufficient {
+			estimate.Shortfall, _ = estimate.Total.Sub(available)
+		}
+	}
+
+	return estimate, nil
+}
+
+func estimateOperation(ctx context.Context, catalog *PricingCatalog, operation PlannedOperation, check DomainCheckResult) (*CostEstimateItem, error) {
+	item := &CostEstimateItem{Operation: operation}
+	years := int64(operation.Years)
+
+	if operation.Action == ActionNameRegister && !check.Available {
+		item.Warnings = append(item.Warnings, "domain isn't available for registration")
+	}
+
+	var premiumPrice Money
+	if check.IsPremiumName {
+		switch operation.Action {
+		case ActionNameRegister:
+			// the premium registration price covers the first year, the rest are renewed at the premium renewal price
+			var err error
+			premiumPrice, err = check.PremiumRegistrationPrice.Add(check.PremiumRenewalPrice.MulInt(years - 1))
+			if err != nil {
+				return nil, err
+			}
+		case ActionNameRenew:
+			premiumPrice = check.PremiumRenewalPrice.MulInt(years)
+		case ActionNameTransfer:
+			premiumPrice = check.PremiumTransferPrice
+		case ActionNameReactivate:
+			premiumPrice = check.PremiumRestorePrice
+		}
+	}
+
+	if !premiumPrice.IsZero() {
+		item.IsPremium = true
+		item.Price = premiumPrice
+	} else {
+		parsedDomain, err := ParseDomain(operation.Domain)
+		if err != nil {
+			return nil, err
+		}
+
+		entry, err := catalog.Price(ctx, parsedDomain.TLD, operation.Action, pricedYears(operation))
+		if err != nil {
+			return nil, err
+		}
+		// the catalog prices are per year, like the premium renewal price
+		yearPrice := entry.Price
+		if !entry.CouponPrice.IsZero() {
+			yearPrice = entry.CouponPrice
+		}
+		item.Price = yearPrice.MulInt(int64(pricedYears(operation)))
+	}
+
+	if operation.Action == ActionNameRegister || operation.Action == ActionNameRenew {
+		item.IcannFee = check.IcannFee.MulInt(years)
+	} else {
+		item.IcannFee = check.IcannFee
+	}
+	if operation.Action == ActionNameRegister {
+		item.EapFee = check.EapFee
+	}
+
+	total, err := SumMoney(item.Price, item.IcannFee, item.EapFee)
+	if err != nil {
+		return nil, err
+	}
+	item.Total = total
+
+	return item, nil
+}
+
+// pricedYears returns the number of years the operation is charged for, transfers and reactivations are charged once
+func pricedYears(operation PlannedOperation) int {
+	if operation.Action == ActionNameRegister || operation.Action == ActionNameRenew {
+		return operation.Years
+	}
+	return 1
+}
+
+func validatePlannedOperations(operations []PlannedOperation) ([]PlannedOperation, error) {
+	normalized := make([]PlannedOperation, 0, len(operations))
+	errMessages := []string{}
+
+	for i, operation := range operations {
+		operation.Action = strings.ToUpper(strings.TrimSpace(operation.Action))
+		operation.Domain = strings.ToLower(strings.TrimSpace(operation.Domain))
+		if operation.Years == 0 {
+			operation.Years = 1
+		}
+
+		if !isValidEstimateAction(operation.Action) {
+			errMessages = append(errMessages, fmt.Sprintf("invalid Operations[%d].Action value: %s", i, operation.Action))
+		}
+		if _, err := ParseDomain(operation.Domain); err != nil {
+			errMessages = append(errMessages, fmt.Sprintf("invalid Operations[%d].Domain value %q: %s", i, operation.Domain, err))
+		}
+		if operation.Years < 1 || operation.Years > 10 {
+			errMessages = append(errMessages, fmt.Sprintf("invalid Operations[%d].Years value: %d, minimum value is 1, and maximum value is 10", i, operation.Years))
+		}
+
+		normalized = append(normalized, operation)
+	}
+
+	if len(errMessages) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(errMessages, "; "))
+	}
+
+	return normalized, nil
+}
+
+func isValidEstimateAction(action string) bool {
+	for _, value := range allowedEstimateActionValues {
+		if action == value {
+			return true
+		}
+	}
+	return false
+}
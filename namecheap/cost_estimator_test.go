@@ -0,0 +1,206 @@
+package namecheap
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newCostEstimatorMockServer(balance string, sentPromotionCodes *[]string) *httptest.Server {
+	return httptest.NewServer(newCostEstimatorMockHandler(balance, sentPromotionCodes))
+}
+
+// newCostEstimatorMockHandler responds to the check, pricing and balances commands
+func newCostEstimatorMockHandler(balance string, sentPromotionCodes *[]string) http.HandlerFunc {
+	fakeCheckResponse := `
+		<?xml version="1.0" encoding="utf-8"?>
+		<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+			<Errors />
+			<CommandResponse Type="namecheap.domains.check">
+				<DomainCheckResult Domain="new.com" Available="true" IsPremiumName="false" PremiumRegistrationPrice="0" PremiumRenewalPrice="0" PremiumRestorePrice="0" PremiumTransferPrice="0" IcannFee="0.18" EapFee="0" />
+				<DomainCheckResult Domain="premium.com" Available="true" IsPremiumName="true" PremiumRegistrationPrice="100.00" PremiumRenewalPrice="20.00" PremiumRestorePrice="65.00" PremiumTransferPrice="20.00" IcannFee="0.18" EapFee="50.00" />
+				<DomainCheckResult Domain="mine.net" Available="false" IsPremiumName="false" PremiumRegistrationPrice="0" PremiumRenewalPrice="0" PremiumRestorePrice="0" PremiumTransferPrice="0" IcannFee="0.18" EapFee="0" />
+			</CommandResponse>
+		</ApiResponse>
+	`
+
+	fakeBalancesResponse := `
+		<?xml version="1.0" encoding="utf-8"?>
+		<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+			<Errors />
+			<CommandResponse Type="namecheap.users.getBalances">
+				<UserGetBalancesResult Currency="USD" AvailableBalance="` + balance + `" AccountBalance="` + balance + `" EarnedAmount="0.00" WithdrawableAmount="0.00" FundsRequiredForAutoRenew="0.00" />
+			</CommandResponse>
+		</ApiResponse>
+	`
+
+	return func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := ioutil.ReadAll(request.Body)
+		query, _ := url.ParseQuery(string(body))
+
+		switch query.Get("Command") {
+		case "namecheap.domains.check":
+			_, _ = writer.Write([]byte(fakeCheckResponse))
+		case "namecheap.users.getPricing":
+			if sentPromotionCodes != nil {
+				*sentPromotionCodes = append(*sentPromotionCodes, query.Get("PromotionCode"))
+			}
+			_, _ = writer.Write([]byte(fakePricingResponse))
+		case "namecheap.users.getBalances":
+			_, _ = writer.Write([]byte(fakeBalancesResponse))
+		}
+	}
+}
+
+func TestCostEstimator(t *testing.T) {
+	operations := []PlannedOperation{
+		{Action: ActionNameRegister, Domain: "new.com", Years: 2},
+		{Action: "register", Domain: "premium.com", Years: 2},
+		{Action: ActionNameRenew, Domain: "mine.net"},
+	}
+
+	t.Run("itemized_total", func(t *testing.T) {
+		mockServer := newCostEstimatorMockServer("1000.00", nil)
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		_, err := NewCostEstimator(client, nil).Estimate(context.TODO(), &CostEstimateArgs{
+			Operations: []PlannedOperation{{Action: ActionNameRenew, Domain: "mine.net"}},
+		})
+		assert.EqualError(t, err, "no renew price found for net for 1 year(s)")
+
+		estimate, err := NewCostEstimator(client, nil).Estimate(context.TODO(), &CostEstimateArgs{
+			Operations: operations[:2],
+		})
+		if err != nil {
+			t.Fatal("Unable to estimate", err)
+		}
+
+		if assert.Len(t, estimate.Items, 2) {
+			regular := estimate.Items[0]
+			assert.False(t, regular.IsPremium)
+			assert.Equal(t, MustParseMoney("22.86", "USD"), regular.Price)
+			assert.Equal(t, MustParseMoney("0.36", "USD"), regular.IcannFee)
+			assert.Equal(t, MustParseMoney("23.22", "USD"), regular.Total)
+
+			premium := estimate.Items[1]
+			assert.True(t, premium.IsPremium)
+			assert.Equal(t, ActionNameRegister, premium.Operation.Action)
+			assert.Equal(t, MustParseMoney("120.00", "USD"), premium.Price)
+			assert.Equal(t, MustParseMoney("50.00", "USD"), premium.EapFee)
+			assert.Equal(t, MustParseMoney("170.36", "USD"), premium.Total)
+		}
+
+		assert.Equal(t, MustParseMoney("193.58", "USD"), estimate.Total)
+		assert.Nil(t, estimate.AvailableBalance)
+		assert.Empty(t, estimate.Warnings())
+	})
+
+	t.Run("unavailable_domain_warning", func(t *testing.T) {
+		mockServer := newCostEstimatorMockServer("1000.00", nil)
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		estimate, err := NewCostEstimator(client, nil).Estimate(context.TODO(), &CostEstimateArgs{
+			Operations: []PlannedOperation{{Action: ActionNameRegister, Domain: "mine.net"}},
+		})
+		if err != nil {
+			t.Fatal("Unable to estimate", err)
+		}
+
+		assert.Equal(t, []string{"mine.net: domain isn't available for registration"}, estimate.Warnings())
+	})
+
+	t.Run("balance_comparison", func(t *testing.T) {
+		mockServer := newCostEstimatorMockServer("100.00", nil)
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		estimator := NewCostEstimator(client, nil)
+
+		estimate, err := estimator.Estimate(context.TODO(), &CostEstimateArgs{
+			Operations:     operations[:2],
+			CompareBalance: true,
+		})
+		if err != nil {
+			t.Fatal("Unable to estimate", err)
+		}
+
+		assert.Equal(t, MustParseMoney("100.00", "USD"), *estimate.AvailableBalance)
+		assert.False(t, estimate.IsBalanceSufficient)
+		assert.Equal(t, MustParseMoney("93.58", "USD"), estimate.Shortfall)
+
+		estimate, err = estimator.Estimate(context.TODO(), &CostEstimateArgs{
+			Operations:     operations[:1],
+			CompareBalance: true,
+		})
+		if err != nil {
+			t.Fatal("Unable to estimate", err)
+		}
+
+		assert.True(t, estimate.IsBalanceSufficient)
+		assert.True(t, estimate.Shortfall.IsZero())
+	})
+
+	t.Run("promotion_code_catalog", func(t *testing.T) {
+		var sentPromotionCodes []string
+		mockServer := newCostEstimatorMockServer("100.00", &sentPromotionCodes)
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		estimator := NewCostEstimator(client, nil)
+		for _, promotionCode := range []string{"", "PROMO", "", "PROMO"} {
+			_, err := estimator.Estimate(context.TODO(), &CostEstimateArgs{
+				Operations:    operations[:1],
+				PromotionCode: promotionCode,
+			})
+			if err != nil {
+				t.Fatal("Unable to estimate", err)
+			}
+		}
+
+		assert.Equal(t, []string{"", "PROMO"}, sentPromotionCodes)
+	})
+
+	t.Run("invalid_operations", func(t *testing.T) {
+		client := setupClient(nil)
+
+		_, err := NewCostEstimator(client, nil).Estimate(context.TODO(), &CostEstimateArgs{
+			Operations: []PlannedOperation{
+				{Action: "BUY", Domain: "domain.com"},
+				{Action: ActionNameRegister, Domain: "domain", Years: 11},
+			},
+		})
+
+		if assert.NotNil(t, err) {
+			assert.Contains(t, err.Error(), "invalid Operations[0].Action value: BUY")
+			assert.Contains(t, err.Error(), `invalid Operations[1].Domain value "domain"`)
+			assert.Contains(t, err.Error(), "invalid Operations[1].Years value: 11")
+		}
+	})
+
+	t.Run("premium_currency_mismatch", func(t *testing.T) {
+		check := DomainCheckResult{
+			Domain:                   "premium.com",
+			IsPremiumName:            true,
+			PremiumRegistrationPrice: MustParseMoney("100.00", "USD"),
+			PremiumRenewalPrice:      MustParseMoney("80.00", "EUR"),
+		}
+
+		_, err := estimateOperation(context.TODO(), nil, PlannedOperation{Action: ActionNameRegister, Domain: "premium.com", Years: 2}, check)
+		assert.EqualError(t, err, "currency mismatch: USD and EUR")
+	})
+}
@@ -0,0 +1,206 @@
+package namecheap
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/go-cleanhttp"
+)
+
+// defaultDDNSUpdateURL is the endpoint of the Namecheap dynamic DNS update protocol
+const defaultDDNSUpdateURL = "https://dynamicdns.park-your-domain.com/update"
+
+// DDNSClientConfig is the configuration of DDNSClient, the zero values are replaced with the defaults
+type DDNSClientConfig struct {
+	// URL of the update endpoint, e.g. a local fake in the tests
+	// Default value: https://dynamicdns.park-your-domain.com/update
+	UpdateURL string
+	// Default value: cleanhttp.DefaultClient()
+	HTTPClient *http.Client
+}
+
+// DDNSUpdateArgs struct is an input arguments for DDNSClient.Update function
+type DDNSUpdateArgs struct {
+	// Host record to update, e.g. www or *. The host must have dynamic DNS enabled (IsDDNSEnabled)
+	// Default value: @
+	Host string
+	// Domain the host belongs to, e.g. domain.net. Required
+	Domain string
+	// Dynamic DNS password of the domain, shown in Advanced DNS of the dashboard. It isn't the API key. Required
+	Password string
+	// IPv4 address to set. If empty, then Namecheap sets the address the request comes from
+	IP string
+}
+
+// DDNSUpdateResult is the result of a successful update
+type DDNSUpdateResult struct {
+	Host   string
+	Domain string
+	// Address set by Namecheap
+	IP string
+}
+
+// DDNSClient updates the A records of the hosts with dynamic DNS enabled over the password-based update protocol
+// of Namecheap, it doesn't use the API credentials:
+//
+//	ddns := namecheap.NewDDNSClient(nil)
+//	result, err := ddns.Update(ctx, &namecheap.DDNSUpdateArgs{Host: "office", Domain: "domain.net", Password: password})
+type DDNSClient struct {
+	config DDNSClientConfig
+}
+
+type ddnsResponse struct {
+	XMLName  xml.Name           `xml:"interface-response"`
+	IP       string             `xml:"IP"`
+	ErrCount int                `xml:"ErrCount"`
+	Errors   ddnsErrors         `xml:"errors"`
+	Results  []ddnsResponseItem `xml:"responses>response"`
+}
+
+// ddnsErrors is the list of the numbered errors, e.g. <Err1>Passwords do not match</Err1>
+type ddnsErrors struct {
+	Items []ddnsError `xml:",any"`
+}
+
+type ddnsError struct {
+	Message string `xml:",chardata"`
+}
+
+type ddnsResponseItem struct {
+	Number  string `xml:"ResponseNumber"`
+	Message string `xml:"ResponseString"`
+}
+
+// NewDDNSClient returns a dynamic DNS client, nil config means the defaults
+func NewDDNSClient(config *DDNSClientConfig) *DDNSClient {
+	client := &DDNSClient{}
+	if config != nil {
+		client.config = *config
+	}
+	if client.config.UpdateURL == "" {
+		client.config.UpdateURL = defaultDDNSUpdateURL
+	}
+	if client.config.HTTPClient == nil {
+		client.config.HTTPClient = cleanhttp.DefaultClient()
+	}
+	return client
+}
+
+// Update sets the address of the host. The errors never include the password
+func (c *DDNSClient) Update(ctx context.Context, args *DDNSUpdateArgs) (*DDNSUpdateResult, error) {
+	if args == nil {
+		return nil, fmt.Errorf("args is required")
+	}
+
+	host := args.Host
+	if host == "" {
+		host = "@"
+	}
+	if args.Domain == "" {
+		return nil, fmt.Errorf("Domain is required")
+	}
+	if args.Password == "" {
+		return nil, fmt.Errorf("Password is required")
+	}
+	if args.IP != "" {
+		if ip := net.ParseIP(args.IP); ip == nil || ip.To4() == nil {
+			return nil, fmt.Errorf("invalid IP value: %s, IPv4 address is required", args.IP)
+		}
+	}
+
+	updateURL, err := url.Parse(c.config.UpdateURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid UpdateURL: %v", err)
+	}
+
+	query := url.Values{}
+	query.Set("host", host)
+	query.Set("domain", args.Domain)
+	query.Set("password", args.Password)
+	if args.IP != "" {
+		query.Set("ip", args.IP)
+	}
+	updateURL.RawQuery = query.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, updateURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to build the dynamic DNS request: %v", withoutURL(err))
+	}
+
+	response, err := c.config.HTTPClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("unable to update %s: %v", ddnsHostName(host, args.Domain), withoutURL(err))
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to update %s: unexpected status %s", ddnsHostName(host, args.Domain), response.Status)
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(response.Body, 64*1024))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read server response: %s", err)
+	}
+
+	var parsed ddnsResponse
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	// the responses declare utf-16 but are sent in ASCII
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	if err := decoder.Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("unable to parse server response: %s", err)
+	}
+
+	if parsed.ErrCount > 0 || len(parsed.Errors.Items) > 0 {
+		return nil, fmt.Errorf("unable to update %s: %s", ddnsHostName(host, args.Domain), parsed.errorMessage())
+	}
+
+	return &DDNSUpdateResult{
+		Host:   host,
+		Domain: args.Domain,
+		IP:     parsed.IP,
+	}, nil
+}
+
+// errorMessage joins the errors of the response in the form "message (number)" of the API errors
+func (r ddnsResponse) errorMessage() string {
+	var messages []string
+	for i, responseError := range r.Errors.Items {
+		message := strings.TrimSpace(responseError.Message)
+		if i < len(r.Results) && r.Results[i].Number != "" {
+			message = fmt.Sprintf("%s (%s)", message, r.Results[i].Number)
+		}
+		messages = append(messages, message)
+	}
+	if len(messages) == 0 {
+		return "unknown error"
+	}
+	return strings.Join(messages, "; ")
+}
+
+// ddnsHostName returns the full name of the host, e.g. www.domain.net
+func ddnsHostName(host string, domain string) string {
+	if host == "@" {
+		return domain
+	}
+	return host + "." + domain
+}
+
+// withoutURL drops the request URL, which includes the password, from the errors of the HTTP client
+func withoutURL(err error) error {
+	var urlError *url.Error
+	if errors.As(err, &urlError) {
+		return urlError.Err
+	}
+	return err
+}
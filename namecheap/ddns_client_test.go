@@ -0,0 +1,199 @@
+package namecheap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const fakeDDNSSuccessResponse = `<?xml version="1.0" encoding="utf-16"?>
+<interface-response>
+	<Command>SETDNSHOST</Command>
+	<Language>eng</Language>
+	<IP>%s</IP>
+	<ErrCount>0</ErrCount>
+	<errors />
+	<ResponseCount>0</ResponseCount>
+	<Done>true</Done>
+	<debug><![CDATA[]]></debug>
+</interface-response>`
+
+const fakeDDNSErrorResponse = `<?xml version="1.0" encoding="utf-16"?>
+<interface-response>
+	<Command>SETDNSHOST</Command>
+	<Language>eng</Language>
+	<ErrCount>1</ErrCount>
+	<errors>
+		<Err1>Passwords do not match</Err1>
+	</errors>
+	<ResponseCount>1</ResponseCount>
+	<responses>
+		<response>
+			<ResponseNumber>304156</ResponseNumber>
+			<ResponseString>Validation error; invalid ; password</ResponseString>
+		</response>
+	</responses>
+	<Done>true</Done>
+	<debug><![CDATA[]]></debug>
+</interface-response>`
+
+// fakeDDNSServer is a local fake of the dynamic DNS update endpoint, it accepts the password "secret"
+type fakeDDNSServer struct {
+	*httptest.Server
+
+	m       sync.Mutex
+	queries []url.Values
+	// hosts answered with the error response regardless of the password
+	failingHosts map[string]bool
+}
+
+func newFakeDDNSServer() *fakeDDNSServer {
+	server := &fakeDDNSServer{failingHosts: map[string]bool{}}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		query := request.URL.Query()
+
+		server.m.Lock()
+		server.queries = append(server.queries, query)
+		failing := server.failingHosts[query.Get("host")]
+		server.m.Unlock()
+
+		if request.URL.Path != "/update" {
+			writer.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if failing || query.Get("password") != "secret" {
+			_, _ = writer.Write([]byte(fakeDDNSErrorResponse))
+			return
+		}
+
+		ip := query.Get("ip")
+		if ip == "" {
+			ip = "198.51.100.1"
+		}
+		_, _ = writer.Write([]byte(strings.Replace(fakeDDNSSuccessResponse, "%s", ip, 1)))
+	}))
+	return server
+}
+
+func (s *fakeDDNSServer) Queries() []url.Values {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return append([]url.Values(nil), s.queries...)
+}
+
+func (s *fakeDDNSServer) SetFailing(host string, failing bool) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.failingHosts[host] = failing
+}
+
+func TestNewDDNSClient(t *testing.T) {
+	client := NewDDNSClient(nil)
+
+	assert.Equal(t, "https://dynamicdns.park-your-domain.com/update", client.config.UpdateURL)
+	assert.NotNil(t, client.config.HTTPClient)
+}
+
+func TestDDNSClientUpdate(t *testing.T) {
+	server := newFakeDDNSServer()
+	defer server.Close()
+
+	client := NewDDNSClient(&DDNSClientConfig{UpdateURL: server.URL + "/update"})
+
+	t.Run("update", func(t *testing.T) {
+		result, err := client.Update(context.TODO(), &DDNSUpdateArgs{Host: "office", Domain: "domain.net", Password: "secret", IP: "192.0.2.10"})
+		if err != nil {
+			t.Fatal("Unable to update", err)
+		}
+
+		assert.Equal(t, &DDNSUpdateResult{Host: "office", Domain: "domain.net", IP: "192.0.2.10"}, result)
+
+		queries := server.Queries()
+		sent := queries[len(queries)-1]
+		assert.Equal(t, "office", sent.Get("host"))
+		assert.Equal(t, "domain.net", sent.Get("domain"))
+		assert.Equal(t, "secret", sent.Get("password"))
+		assert.Equal(t, "192.0.2.10", sent.Get("ip"))
+	})
+
+	t.Run("default_host_and_ip", func(t *testing.T) {
+		result, err := client.Update(context.TODO(), &DDNSUpdateArgs{Domain: "domain.net", Password: "secret"})
+		if err != nil {
+			t.Fatal("Unable to update", err)
+		}
+
+		assert.Equal(t, "@", result.Host)
+		assert.Equal(t, "198.51.100.1", result.IP)
+
+		queries := server.Queries()
+		_, hasIP := queries[len(queries)-1]["ip"]
+		assert.False(t, hasIP)
+	})
+
+	t.Run("error_response", func(t *testing.T) {
+		_, err := client.Update(context.TODO(), &DDNSUpdateArgs{Host: "office", Domain: "domain.net", Password: "wrong", IP: "192.0.2.10"})
+
+		assert.EqualError(t, err, "unable to update office.domain.net: Passwords do not match (304156)")
+	})
+
+	t.Run("unexpected_status", func(t *testing.T) {
+		client := NewDDNSClient(&DDNSClientConfig{UpdateURL: server.URL + "/missing"})
+
+		_, err := client.Update(context.TODO(), &DDNSUpdateArgs{Domain: "domain.net", Password: "secret"})
+
+		assert.EqualError(t, err, "unable to update domain.net: unexpected status 404 Not Found")
+	})
+
+	t.Run("password_not_in_errors", func(t *testing.T) {
+		closedServer := httptest.NewServer(http.NotFoundHandler())
+		closedServer.Close()
+		client := NewDDNSClient(&DDNSClientConfig{UpdateURL: closedServer.URL})
+
+		_, err := client.Update(context.TODO(), &DDNSUpdateArgs{Domain: "domain.net", Password: "secret"})
+
+		if assert.Error(t, err) {
+			assert.True(t, strings.HasPrefix(err.Error(), "unable to update domain.net: "), err.Error())
+			assert.NotContains(t, err.Error(), "secret")
+		}
+	})
+
+	var errorCases = map[string]struct {
+		Args          *DDNSUpdateArgs
+		ExpectedError string
+	}{
+		"nil_args": {
+			Args:          nil,
+			ExpectedError: "args is required",
+		},
+		"missing_domain": {
+			Args:          &DDNSUpdateArgs{Password: "secret"},
+			ExpectedError: "Domain is required",
+		},
+		"missing_password": {
+			Args:          &DDNSUpdateArgs{Domain: "domain.net"},
+			ExpectedError: "Password is required",
+		},
+		"invalid_ip": {
+			Args:          &DDNSUpdateArgs{Domain: "domain.net", Password: "secret", IP: "192.0.2"},
+			ExpectedError: "invalid IP value: 192.0.2, IPv4 address is required",
+		},
+		"ipv6": {
+			Args:          &DDNSUpdateArgs{Domain: "domain.net", Password: "secret", IP: "2001:db8::1"},
+			ExpectedError: "invalid IP value: 2001:db8::1, IPv4 address is required",
+		},
+	}
+
+	for name, c := range errorCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := client.Update(context.TODO(), c.Args)
+
+			assert.EqualError(t, err, c.ExpectedError)
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultDDNSCheckInterval    = 5 * time.Minute
+	defaultDDNSRetryInterval    = 30 * time.Second
+	defaultDDNSMaxRetryInterval = 30 * time.Minute
+)
+
+// DDNSUpdaterConfig is the configuration of DDNSUpdater, the zero values are replaced with the defaults
+type DDNSUpdaterConfig struct {
+	// Domain the hosts belong to, e.g. domain.net. Required
+	Domain string
+	// Hosts kept pointing to the public address, e.g. @, www and vpn
+	// Default value: @
+	Hosts []string
+	// Dynamic DNS password of the domain. Required
+	Password string
+	// URL of the service the public IPv4 address is looked up from, see LookupClientIPFrom
+	// Default value: https://ipv4.icanhazip.com
+	IPLookupURL string
+	// Interval between the checks of the public address
+	// Default value: 5 minutes
+	CheckInterval time.Duration
+	// Interval before the check following a failed one, it's doubled after every failure up to MaxRetryInterval
+	// Default value: 30 seconds
+	RetryInterval time.Duration
+	// Default value: 30 minutes
+	MaxRetryInterval time.Duration
+}
+
+// DDNSUpdateOutcome is the result of the update of a single host made by DDNSUpdater.Check
+type DDNSUpdateOutcome struct {
+	Host   string
+	Result *DDNSUpdateResult
+	Err    error
+}
+
+// DDNSCheckResult is the result of a single check of the public address
+type DDNSCheckResult struct {
+	Time time.Time
+	// Public address detected, empty if the lookup failed
+	IP string
+	// Public address detected by the previous successful lookup, empty on the first check
+	PreviousIP string
+	// Updates of the hosts not pointing to IP yet, empty if none was needed
+	Updates []DDNSUpdateOutcome
+	// Error of the lookup or of the first failed update
+	Err error
+	// Delay before the next check, set by DDNSUpdater.Run
+	NextCheck time.Duration
+}
+
+// Changed reports whether the public address differs from the one detected by the previous check
+func (r DDNSCheckResult) Changed() bool {
+	return r.IP != "" && r.PreviousIP != "" && r.IP != r.PreviousIP
+}
+
+func (r DDNSCheckResult) String() string {
+	if r.IP == "" {
+		return fmt.Sprintf("lookup failed: %v", r.Err)
+	}
+
+	var updated, failed []string
+	for _, update := range r.Updates {
+		if update.Err != nil {
+			failed = append(failed, update.Host)
+		} else {
+			updated = append(updated, update.Host)
+		}
+	}
+
+	summary := r.IP
+	switch {
+	case len(r.Updates) == 0:
+		summary += ", up to date"
+	case len(updated) > 0:
+		summary += ", updated " + strings.Join(updated, ", ")
+	}
+	if len(failed) > 0 {
+		summary += fmt.Sprintf(", failed %s: %v", strings.Join(failed, ", "), r.Err)
+	}
+	return summary
+}
+
+// DDNSUpdater keeps the hosts of a domain pointing to the public address of the machine, e.g. an office router.
+// The hosts are updated on the first check and then only when the address changes, a failed update is retried
+// by the next check:
+//
+//	updater := namecheap.NewDDNSUpdater(nil, &namecheap.DDNSUpdaterConfig{Domain: "domain.net", Hosts: []string{"office"}, Password: password})
+//	err := updater.Run(ctx, func(result *namecheap.DDNSCheckResult) { log.Println(result) })
+type DDNSUpdater struct {
+	client *DDNSClient
+	config DDNSUpdaterConfig
+	now    func() time.Time
+
+	m sync.Mutex
+	// public address detected by the last successful lookup
+	ip string
+	// addresses the hosts were last updated to
+	addresses map[string]string
+}
+
+// NewDDNSUpdater returns an updater sending the updates through the client, nil client means NewDDNSClient(nil)
+func NewDDNSUpdater(client *DDNSClient, config *DDNSUpdaterConfig) *DDNSUpdater {
+	if client == nil {
+		client = NewDDNSClient(nil)
+	}
+	updater := &DDNSUpdater{
+		client:    client,
+		now:       time.Now,
+		addresses: map[string]string{},
+	}
+	if config != nil {
+		updater.config = *config
+	}
+	if len(updater.config.Hosts) == 0 {
+		updater.config.Hosts = []string{"@"}
+	}
+	if updater.config.IPLookupURL == "" {
+		updater.config.IPLookupURL = defaultClientIPLookupURL
+	}
+	if updater.config.CheckInterval <= 0 {
+		updater.config.CheckInterval = defaultDDNSCheckInterval
+	}
+	if updater.config.RetryInterval <= 0 {
+		updater.config.RetryInterval = defaultDDNSRetryInterval
+	}
+	if updater.config.MaxRetryInterval <= 0 {
+		updater.config.MaxRetryInterval = defaultDDNSMaxRetryInterval
+	}
+	if updater.config.MaxRetryInterval < updater.config.RetryInterval {
+		updater.config.MaxRetryInterval = updater.config.RetryInterval
+	}
+	return updater
+}
+
+// Check looks up the public address once and updates the hosts not pointing to it yet.
+// The result is returned along with the error if the lookup or an update failed.
+func (u *DDNSUpdater) Check(ctx context.Context) (*DDNSCheckResult, error) {
+	if err := u.validate(); err != nil {
+		return nil, err
+	}
+
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	result := &DDNSCheckResult{Time: u.now(), PreviousIP: u.ip}
+
+	ip, err := LookupClientIPFrom(ctx, u.config.IPLookupURL)
+	if err == nil && net.ParseIP(ip).To4() == nil {
+		err = fmt.Errorf("external IP %s isn't an IPv4 address", ip)
+	}
+	if err != nil {
+		result.Err = err
+		return result, err
+	}
+	result.IP = ip
+	u.ip = ip
+
+	for _, host := range u.config.Hosts {
+		if u.addresses[host] == ip {
+			continue
+		}
+
+		updateResult, err := u.client.Update(ctx, &DDNSUpdateArgs{
+			Host:     host,
+			Domain:   u.config.Domain,
+			Password: u.config.Password,
+			IP:       ip,
+		})
+		result.Updates = append(result.Updates, DDNSUpdateOutcome{Host: host, Result: updateResult, Err: err})
+		if err != nil {
+			if result.Err == nil {
+				result.Err = err
+			}
+			continue
+		}
+		u.addresses[host] = ip
+	}
+
+	return result, result.Err
+}
+
+// Run checks the public address every CheckInterval until the context is done, the failed checks are retried with
+// a backoff from RetryInterval to MaxRetryInterval. The result of every check is passed to report, it can be nil.
+// It returns the context error, or the configuration error without checking.
+func (u *DDNSUpdater) Run(ctx context.Context, report func(*DDNSCheckResult)) error {
+	if err := u.validate(); err != nil {
+		return err
+	}
+
+	failures := 0
+	for {
+		result, err := u.Check(ctx)
+
+		delay := u.config.CheckInterval
+		if err != nil {
+			delay = u.retryInterval(failures)
+			failures++
+		} else {
+			failures = 0
+		}
+
+		result.NextCheck = delay
+		if report != nil {
+			report(result)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// retryInterval returns the delay after the consecutive failure with the index, starting at 0
+func (u *DDNSUpdater) retryInterval(failure int) time.Duration {
+	interval := u.config.RetryInterval
+	for i := 0; i < failure && interval < u.config.MaxRetryInterval; i++ {
+		interval *= 2
+	}
+	if interval > u.config.MaxRetryInterval {
+		interval = u.config.MaxRetryInterval
+	}
+	return interval
+}
+
+func (u *DDNSUpdater) validate() error {
+	if u.config.Domain == "" {
+		return fmt.Errorf("Domain is required")
+	}
+	if u.config.Password == "" {
+		return fmt.Errorf("Password is required")
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package namecheap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeIPLookupServer answers with the configured address, or fails if it's empty
+type fakeIPLookupServer struct {
+	*httptest.Server
+
+	m  sync.Mutex
+	ip string
+}
+
+func newFakeIPLookupServer(ip string) *fakeIPLookupServer {
+	server := &fakeIPLookupServer{ip: ip}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		server.m.Lock()
+		ip := server.ip
+		server.m.Unlock()
+
+		if ip == "" {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = writer.Write([]byte(ip + "\n"))
+	}))
+	return server
+}
+
+func (s *fakeIPLookupServer) SetIP(ip string) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.ip = ip
+}
+
+func TestNewDDNSUpdater(t *testing.T) {
+	updater := NewDDNSUpdater(nil, &DDNSUpdaterConfig{Domain: "domain.net", Password: "secret", RetryInterval: time.Hour})
+
+	assert.Equal(t, []string{"@"}, updater.config.Hosts)
+	assert.Equal(t, "https://ipv4.icanhazip.com", updater.config.IPLookupURL)
+	assert.Equal(t, 5*time.Minute, updater.config.CheckInterval)
+	assert.Equal(t, time.Hour, updater.config.RetryInterval)
+	assert.Equal(t, time.Hour, updater.config.MaxRetryInterval)
+	assert.Equal(t, "https://dynamicdns.park-your-domain.com/update", updater.client.config.UpdateURL)
+}
+
+func TestDDNSUpdaterCheck(t *testing.T) {
+	ddnsServer := newFakeDDNSServer()
+	defer ddnsServer.Close()
+	lookupServer := newFakeIPLookupServer("192.0.2.10")
+	defer lookupServer.Close()
+
+	updater := NewDDNSUpdater(NewDDNSClient(&DDNSClientConfig{UpdateURL: ddnsServer.URL + "/update"}), &DDNSUpdaterConfig{
+		Domain:      "domain.net",
+		Hosts:       []string{"@", "vpn"},
+		Password:    "secret",
+		IPLookupURL: lookupServer.URL,
+	})
+
+	// the first check updates every host
+	result, err := updater.Check(context.TODO())
+	if err != nil {
+		t.Fatal("Unable to check", err)
+	}
+	assert.Equal(t, "192.0.2.10", result.IP)
+	assert.Empty(t, result.PreviousIP)
+	assert.False(t, result.Changed())
+	if assert.Len(t, result.Updates, 2) {
+		assert.Equal(t, "@", result.Updates[0].Host)
+		assert.Equal(t, "192.0.2.10", result.Updates[0].Result.IP)
+		assert.Equal(t, "vpn", result.Updates[1].Host)
+	}
+	assert.Equal(t, "192.0.2.10, updated @, vpn", result.String())
+	assert.Len(t, ddnsServer.Queries(), 2)
+
+	// unchanged address
+	result, err = updater.Check(context.TODO())
+	if err != nil {
+		t.Fatal("Unable to check", err)
+	}
+	assert.Empty(t, result.Updates)
+	assert.Equal(t, "192.0.2.10, up to date", result.String())
+	assert.Len(t, ddnsServer.Queries(), 2)
+
+	// changed address with a failing host
+	lookupServer.SetIP("192.0.2.20")
+	ddnsServer.SetFailing("vpn", true)
+
+	result, err = updater.Check(context.TODO())
+
+	assert.EqualError(t, err, "unable to update vpn.domain.net: Passwords do not match (304156)")
+	assert.Equal(t, err, result.Err)
+	assert.True(t, result.Changed())
+	assert.Equal(t, "192.0.2.10", result.PreviousIP)
+	assert.Len(t, result.Updates, 2)
+	assert.Equal(t, "192.0.2.20, updated @, failed vpn: unable to update vpn.domain.net: Passwords do not match (304156)", result.String())
+
+	// only the failed host is retried
+	ddnsServer.SetFailing("vpn", false)
+
+	result, err = updater.Check(context.TODO())
+	if err != nil {
+		t.Fatal("Unable to check", err)
+	}
+	if assert.Len(t, result.Updates, 1) {
+		assert.Equal(t, "vpn", result.Updates[0].Host)
+	}
+	queries := ddnsServer.Queries()
+	assert.Len(t, queries, 5)
+	assert.Equal(t, "192.0.2.20", queries[4].Get("ip"))
+}
+
+func TestDDNSUpdaterCheckLookupErrors(t *testing.T) {
+	var cases = map[string]struct {
+		IP            string
+		ExpectedError string
+	}{
+		"unavailable": {
+			IP:            "",
+			ExpectedError: "did not get OK status when looking up external IP: 503/503 Service Unavailable",
+		},
+		"ipv6": {
+			IP:            "2001:db8::1",
+			ExpectedError: "external IP 2001:db8::1 isn't an IPv4 address",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			lookupServer := newFakeIPLookupServer(c.IP)
+			defer lookupServer.Close()
+
+			updater := NewDDNSUpdater(nil, &DDNSUpdaterConfig{Domain: "domain.net", Password: "secret", IPLookupURL: lookupServer.URL})
+
+			result, err := updater.Check(context.TODO())
+
+			assert.EqualError(t, err, c.ExpectedError)
+			assert.Empty(t, result.IP)
+			assert.Empty(t, result.Updates)
+			assert.Equal(t, "lookup failed: "+c.ExpectedError, result.String())
+		})
+	}
+}
+
+func TestDDNSUpdaterRun(t *testing.T) {
+	t.Run("backoff", func(t *testing.T) {
+		ddnsServer := newFakeDDNSServer()
+		defer ddnsServer.Close()
+		lookupServer := newFakeIPLookupServer("")
+		defer lookupServer.Close()
+
+		updater := NewDDNSUpdater(NewDDNSClient(&DDNSClientConfig{UpdateURL: ddnsServer.URL + "/update"}), &DDNSUpdaterConfig{
+			Domain:           "domain.net",
+			Password:         "secret",
+			IPLookupURL:      lookupServer.URL,
+			CheckInterval:    time.Hour,
+			RetryInterval:    time.Millisecond,
+			MaxRetryInterval: 4 * time.Millisecond,
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var results []*DDNSCheckResult
+		err := updater.Run(ctx, func(result *DDNSCheckResult) {
+			results = append(results, result)
+			if len(results) == 4 {
+				lookupServer.SetIP("192.0.2.10")
+			}
+			if result.Err == nil {
+				cancel()
+			}
+		})
+
+		assert.Equal(t, context.Canceled, err)
+		if assert.Len(t, results, 5) {
+			var delays []time.Duration
+			for _, result := range results {
+				delays = append(delays, result.NextCheck)
+			}
+			assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond, 4 * time.Millisecond, time.Hour}, delays)
+			assert.Len(t, results[4].Updates, 1)
+		}
+		assert.Len(t, ddnsServer.Queries(), 1)
+	})
+
+	t.Run("invalid_config", func(t *testing.T) {
+		updater := NewDDNSUpdater(nil, &DDNSUpdaterConfig{Domain: "domain.net"})
+
+		err := updater.Run(context.TODO(), nil)
+
+		assert.EqualError(t, err, "Password is required")
+	})
+}
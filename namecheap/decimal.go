@@ -0,0 +1,161 @@
+package namecheap
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// decimalScale is the number of fractional digits a Decimal keeps
+	decimalScale = 6
+	decimalUnit  = int64(1000000)
+)
+
+// Decimal is an exact fixed-point decimal number with up to 6 fractional digits.
+// It's used for the prices the API returns as strings, so they can be compared and summed without float rounding.
+// The zero value is 0.
+type Decimal struct {
+	units int64
+}
+
+// ParseDecimal parses a plain decimal string like "12.88" or "-0.18".
+// An empty string is parsed as 0, the exponent notation isn't supported.
+func ParseDecimal(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Decimal{}, nil
+	}
+
+	negative := false
+	digits := s
+	if digits[0] == '-' || digits[0] == '+' {
+		negative = digits[0] == '-'
+		digits = digits[1:]
+	}
+
+	intPart := digits
+	fracPart := ""
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		intPart = digits[:i]
+		fracPart = digits[i+1:]
+	}
+
+	if (intPart == "" && fracPart == "") || !isDigits(intPart) || !isDigits(fracPart) {
+		return Decimal{}, fmt.Errorf("invalid decimal value: %s", s)
+	}
+
+	fracPart = strings.TrimRight(fracPart, "0")
+	if len(fracPart) > decimalScale {
+		return Decimal{}, fmt.Errorf("invalid decimal value: %s, maximum %d fractional digits are supported", s, decimalScale)
+	}
+	fracPart += strings.Repeat("0", decimalScale-len(fracPart))
+
+	if intPart == "" {
+		intPart = "0"
+	}
+
+	units, err := strconv.ParseInt(intPart+fracPart, 10, 64)
+	if err != nil {
+		return Decimal{}, fmt.Errorf("invalid decimal value: %s", s)
+	}
+
+	if negative {
+		units = -units
+	}
+
+	return Decimal{units: units}, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics if the string can't be parsed
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// NewDecimalFromInt returns a Decimal holding the integer value v
+func NewDecimalFromInt(v int64) Decimal {
+	return Decimal{units: v * decimalUnit}
+}
+
+// Add returns d + other
+func (d Decimal) Add(other Decimal) Decimal {
+	return Decimal{units: d.units + other.units}
+}
+
+// Sub returns d - other
+func (d Decimal) Sub(other Decimal) Decimal {
+	return Decimal{units: d.units - other.units}
+}
+
+// MulInt returns d * n
+func (d Decimal) MulInt(n int64) Decimal {
+	return Decimal{units: d.units * n}
+}
+
+// Cmp compares d and other and returns -1 if d < other, 0 if d == other and +1 if d > other
+func (d Decimal) Cmp(other Decimal) int {
+	switch {
+	case d.units < other.units:
+		return -1
+	case d.units > other.units:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// IsZero reports whether d is 0
+func (d Decimal) IsZero() bool {
+	return d.units == 0
+}
+
+// IsNegative reports whether d is less than 0
+func (d Decimal) IsNegative() bool {
+	return d.units < 0
+}
+
+// String returns the decimal representation of d with at least two fractional digits, e.g. "12.50"
+func (d Decimal) String() string {
+	units := d.units
+	sign := ""
+	if units < 0 {
+		sign = "-"
+		units = -units
+	}
+
+	intPart := units / decimalUnit
+	fracPart := fmt.Sprintf("%0*d", decimalScale, units%decimalUnit)
+	fracPart = strings.TrimRight(fracPart, "0")
+	for len(fracPart) < 2 {
+		fracPart += "0"
+	}
+
+	return fmt.Sprintf("%s%d.%s", sign, intPart, fracPart)
+}
+
+// Float64 returns the nearest float64 value of d. It's meant for display purposes only.
+func (d Decimal) Float64() float64 {
+	return float64(d.units) / float64(decimalUnit)
+}
+
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Decimal) UnmarshalText(text []byte) (err error) {
+	*d, err = ParseDecimal(string(text))
+	return err
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
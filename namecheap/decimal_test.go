@@ -0,0 +1,87 @@
+package namecheap
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDecimal(t *testing.T) {
+	successCases := []struct {
+		in  string
+		out string
+	}{
+		{"", "0.00"},
+		{"0", "0.00"},
+		{"12.88", "12.88"},
+		{"12.8", "12.80"},
+		{" 8.880 ", "8.88"},
+		{"-0.18", "-0.18"},
+		{"+3", "3.00"},
+		{".5", "0.50"},
+		{"1.123456", "1.123456"},
+		{"1000000", "1000000.00"},
+	}
+
+	for _, successCase := range successCases {
+		t.Run("success_"+successCase.in, func(t *testing.T) {
+			d, err := ParseDecimal(successCase.in)
+			if err != nil {
+				t.Fatal("Unable to parse decimal", err)
+			}
+
+			assert.Equal(t, successCase.out, d.String())
+		})
+	}
+
+	errorCases := []string{"abc", "1.2.3", "-", ".", "1,5", "1e3", "1.1234567"}
+
+	for _, errorCase := range errorCases {
+		t.Run("error_"+errorCase, func(t *testing.T) {
+			_, err := ParseDecimal(errorCase)
+
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	t.Run("exact_sum", func(t *testing.T) {
+		sum := Decimal{}
+		for i := 0; i < 10; i++ {
+			sum = sum.Add(MustParseDecimal("0.1"))
+		}
+
+		assert.Equal(t, 0, sum.Cmp(NewDecimalFromInt(1)))
+	})
+
+	t.Run("sub_and_mul", func(t *testing.T) {
+		d := MustParseDecimal("10.99").MulInt(3).Sub(MustParseDecimal("0.97"))
+
+		assert.Equal(t, "32.00", d.String())
+	})
+
+	t.Run("compare", func(t *testing.T) {
+		assert.Equal(t, -1, MustParseDecimal("1.5").Cmp(MustParseDecimal("1.51")))
+		assert.Equal(t, 1, MustParseDecimal("-1").Cmp(MustParseDecimal("-2")))
+		assert.True(t, MustParseDecimal("-0.01").IsNegative())
+		assert.True(t, MustParseDecimal("0.000").IsZero())
+	})
+}
+
+func TestDecimalXMLAttr(t *testing.T) {
+	type Obj struct {
+		Price Decimal `xml:"Price,attr"`
+		Fee   Decimal `xml:"Fee,attr"`
+	}
+
+	var obj Obj
+	err := xml.Unmarshal([]byte(`<Obj Price="13.48" Fee=""></Obj>`), &obj)
+	if err != nil {
+		t.Fatal("Unable to decode", err)
+	}
+
+	assert.Equal(t, "13.48", obj.Price.String())
+	assert.True(t, obj.Fee.IsZero())
+}
@@ -0,0 +1,341 @@
+package namecheap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DNSConfigVersion is the version of the DNSConfig schema written by this SDK
+const DNSConfigVersion = 1
+
+const (
+	DNSConfigFormatJSON = "json"
+	DNSConfigFormatYAML = "yaml"
+
+	NameserversModeDefault = "default"
+	NameserversModeCustom  = "custom"
+)
+
+// DNSConfig is the full DNS configuration of a domain, meant to be kept in a YAML or JSON file per domain:
+//
+//	version: 1
+//	domain: domain.net
+//	nameservers:
+//	  mode: default
+//	email_type: FWD
+//	records:
+//	  - host: www
+//	    type: A
+//	    address: 192.0.2.1
+//	    ttl: 300
+//	email_forwards:
+//	  - mailbox: info
+//	    forward_to: me@example.com
+type DNSConfig struct {
+	// Schema version, must be DNSConfigVersion
+	Version int    `json:"version" yaml:"version"`
+	Domain  string `json:"domain" yaml:"domain"`
+
+	Nameservers DNSConfigNameservers `json:"nameservers" yaml:"nameservers"`
+
+	// The settings below require the default Namecheap nameservers
+
+	// Possible values are MXE, MX, FWD, OX, GMAIL or NONE
+	EmailType     string                  `json:"email_type,omitempty" yaml:"email_type,omitempty"`
+	Records       []DNSConfigRecord       `json:"records,omitempty" yaml:"records,omitempty"`
+	EmailForwards []DNSConfigEmailForward `json:"email_forwards,omitempty" yaml:"email_forwards,omitempty"`
+}
+
+// DNSConfigNameservers selects the nameservers of the domain
+type DNSConfigNameservers struct {
+	// Possible values are default or custom
+	// Default value: default
+	Mode string `json:"mode" yaml:"mode"`
+	// Custom nameservers, minimum two are required for the custom mode
+	Servers []string `json:"servers,omitempty" yaml:"servers,omitempty"`
+}
+
+// DNSConfigRecord is a host record of DNSConfig, see DomainsDNSHostRecord
+type DNSConfigRecord struct {
+	Host    string `json:"host" yaml:"host"`
+	Type    string `json:"type" yaml:"type"`
+	Address string `json:"address" yaml:"address"`
+	MXPref  *uint8 `json:"mx_pref,omitempty" yaml:"mx_pref,omitempty"`
+	TTL     int    `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+}
+
+// DNSConfigEmailForward is an email forward of DNSConfig, used with the FWD email type
+type DNSConfigEmailForward struct {
+	Mailbox   string `json:"mailbox" yaml:"mailbox"`
+	ForwardTo string `json:"forward_to" yaml:"forward_to"`
+}
+
+// LoadDNSConfigFile reads and validates the config file, the format is picked by the extension: .json, .yaml or .yml
+func LoadDNSConfigFile(path string) (*DNSConfig, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := LoadDNSConfig(bytes.NewReader(content), strings.TrimPrefix(filepath.Ext(path), "."))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return config, nil
+}
+
+// LoadDNSConfig reads and validates the config in the format: json or yaml. Unknown fields are rejected.
+func LoadDNSConfig(r io.Reader, format string) (*DNSConfig, error) {
+	var config DNSConfig
+
+	switch strings.ToLower(format) {
+	case DNSConfigFormatJSON:
+		decoder := json.NewDecoder(r)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&config); err != nil {
+			return nil, fmt.Errorf("unable to parse the config: %v", err)
+		}
+	case DNSConfigFormatYAML, "yml":
+		decoder := yaml.NewDecoder(r)
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&config); err != nil {
+			return nil, fmt.Errorf("unable to parse the config: %v", err)
+		}
+	default:
+		return nil, fmt.Errorf("invalid format value: %s, allowed values are json, yaml", format)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// NewDNSConfigFromSetHostsArgs returns the config of a domain using the default nameservers with the host records
+func NewDNSConfigFromSetHostsArgs(args *DomainsDNSSetHostsArgs) *DNSConfig {
+	config := &DNSConfig{
+		Version:     DNSConfigVersion,
+		Domain:      args.Domain,
+		Nameservers: DNSConfigNameservers{Mode: NameserversModeDefault},
+		EmailType:   args.EmailType,
+	}
+	for _, record := range args.Records {
+		config.Records = append(config.Records, DNSConfigRecord{
+			Host:    record.HostName,
+			Type:    record.RecordType,
+			Address: record.Address,
+			MXPref:  record.MXPref,
+			TTL:     record.TTL,
+		})
+	}
+	return config
+}
+
+// IsCustomNameservers reports whether the config uses custom nameservers
+func (c *DNSConfig) IsCustomNameservers() bool {
+	return strings.EqualFold(c.Nameservers.Mode, NameserversModeCustom)
+}
+
+// SetHostsArgs returns the SetHosts args of the config, it's meaningful for the default nameservers only
+func (c *DNSConfig) SetHostsArgs() *DomainsDNSSetHostsArgs {
+	args := &DomainsDNSSetHostsArgs{
+		Domain:    c.Domain,
+		EmailType: c.EmailType,
+		Records:   []DomainsDNSHostRecord{},
+	}
+	for _, record := range c.Records {
+		args.Records = append(args.Records, DomainsDNSHostRecord{
+			HostName:   record.Host,
+			RecordType: record.Type,
+			Address:    record.Address,
+			MXPref:     record.MXPref,
+			TTL:        record.TTL,
+		})
+	}
+	return args
+}
+
+// EmailForwardingArgs returns the SetEmailForwarding forwards of the config
+func (c *DNSConfig) EmailForwardingArgs() []DomainsDNSEmailForward {
+	forwards := make([]DomainsDNSEmailForward, 0, len(c.EmailForwards))
+	for _, forward := range c.EmailForwards {
+		forwards = append(forwards, DomainsDNSEmailForward{MailBox: forward.Mailbox, ForwardTo: forward.ForwardTo})
+	}
+	return forwards
+}
+
+// Validate checks the config, the returned error is a *ValidationError listing all the issues
+func (c *DNSConfig) Validate() error {
+	issues := &ValidationError{}
+
+	if c.Version == 0 {
+		issues.add("version", "version is required")
+	} else if c.Version != DNSConfigVersion {
+		issues.add("version", fmt.Sprintf("unsupported version %d, supported version is %d", c.Version, DNSConfigVersion))
+	}
+
+	if _, err := ParseDomain(c.Domain); err != nil {
+		issues.add("domain", fmt.Sprintf("invalid domain value: %s, %v", c.Domain, err))
+	}
+
+	switch strings.ToLower(c.Nameservers.Mode) {
+	case "", NameserversModeDefault:
+		if len(c.Nameservers.Servers) > 0 {
+			issues.add("nameservers.servers", "nameservers.servers are only allowed for the custom mode")
+		}
+	case NameserversModeCustom:
+		if len(c.Nameservers.Servers) < 2 {
+			issues.add("nameservers.servers", "nameservers.servers must contain minimum two items for the custom mode")
+		}
+		if c.EmailType != "" || len(c.Records) > 0 || len(c.EmailForwards) > 0 {
+			issues.add("nameservers.mode", "email_type, records and email_forwards require the default nameservers")
+		}
+	default:
+		issues.add("nameservers.mode", fmt.Sprintf("invalid nameservers.mode value: %s, allowed values are default, custom", c.Nameservers.Mode))
+	}
+
+	if !c.IsCustomNameservers() {
+		if err := validateDomainsDNSSetHostsArgs(c.SetHostsArgs()); err != nil {
+			if recordIssues, ok := err.(*ValidationError); ok {
+				issues.Issues = append(issues.Issues, recordIssues.Issues...)
+			} else {
+				issues.add("records", err.Error())
+			}
+		}
+	}
+
+	if len(c.EmailForwards) > 0 && !strings.EqualFold(c.EmailType, EmailTypeForward) {
+		issues.add("email_forwards", "email_forwards require the FWD email_type")
+	}
+	for i, forward := range c.EmailForwards {
+		if forward.Mailbox == "" {
+			issues.add(fmt.Sprintf("email_forwards[%d].mailbox", i), fmt.Sprintf("email_forwards[%d].mailbox is required", i))
+		}
+		if !strings.Contains(forward.ForwardTo, "@") {
+			issues.add(fmt.Sprintf("email_forwards[%d].forward_to", i), fmt.Sprintf("invalid email_forwards[%d].forward_to value: %s", i, forward.ForwardTo))
+		}
+	}
+
+	return issues.errorOrNil()
+}
+
+// Write writes the config in the format: json or yaml
+func (c *DNSConfig) Write(w io.Writer, format string) error {
+	switch strings.ToLower(format) {
+	case DNSConfigFormatJSON:
+		return c.WriteJSON(w)
+	case DNSConfigFormatYAML, "yml":
+		return c.WriteYAML(w)
+	}
+	return fmt.Errorf("invalid format value: %s, allowed values are json, yaml", format)
+}
+
+// WriteJSON writes the config as indented JSON
+func (c *DNSConfig) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(c)
+}
+
+// WriteYAML writes the config as YAML
+func (c *DNSConfig) WriteYAML(w io.Writer) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// GetConfig reads the current DNS configuration of the domain: the nameservers and, for the default nameservers,
+// the host records, the email type and the email forwards
+func (dds DomainsDNSService) GetConfig(ctx context.Context, domain string) (*DNSConfig, error) {
+	nameservers, err := dds.GetList(ctx, domain)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the nameservers of %s: %v", domain, err)
+	}
+
+	if !nameservers.DomainDNSGetListResult.IsUsingOurDNS {
+		return &DNSConfig{
+			Version: DNSConfigVersion,
+			Domain:  domain,
+			Nameservers: DNSConfigNameservers{
+				Mode:    NameserversModeCustom,
+				Servers: nameservers.DomainDNSGetListResult.Nameservers,
+			},
+		}, nil
+	}
+
+	hosts, err := dds.GetHosts(ctx, domain)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the host records of %s: %v", domain, err)
+	}
+
+	result := hosts.DomainDNSGetHostsResult
+	config := NewDNSConfigFromSetHostsArgs(&DomainsDNSSetHostsArgs{
+		Domain:    domain,
+		Records:   HostRecordsFromDetailed(result.Hosts),
+		EmailType: result.EmailType,
+	})
+
+	if strings.EqualFold(result.EmailType, EmailTypeForward) {
+		forwarding, err := dds.GetEmailForwarding(ctx, domain)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get the email forwarding of %s: %v", domain, err)
+		}
+		for _, forward := range forwarding.DomainDNSGetEmailForwardingResult.Forwards {
+			config.EmailForwards = append(config.EmailForwards, DNSConfigEmailForward{Mailbox: forward.MailBox, ForwardTo: forward.ForwardTo})
+		}
+	}
+
+	return config, nil
+}
+
+// ApplyConfig makes the DNS configuration of the domain match the config. The custom nameservers are set with SetCustom.
+// For the default nameservers SetDefault is called if the domain isn't using them yet, then the host records
+// are replaced with SetHosts, and the email forwards with SetEmailForwarding for the FWD email type.
+func (dds DomainsDNSService) ApplyConfig(ctx context.Context, config *DNSConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	if config.IsCustomNameservers() {
+		if _, err := dds.SetCustom(ctx, config.Domain, config.Nameservers.Servers); err != nil {
+			return fmt.Errorf("unable to set the nameservers of %s: %v", config.Domain, err)
+		}
+		return nil
+	}
+
+	nameservers, err := dds.GetList(ctx, config.Domain)
+	if err != nil {
+		return fmt.Errorf("unable to get the nameservers of %s: %v", config.Domain, err)
+	}
+	if !nameservers.DomainDNSGetListResult.IsUsingOurDNS {
+		if _, err := dds.SetDefault(ctx, config.Domain); err != nil {
+			return fmt.Errorf("unable to set the default nameservers of %s: %v", config.Domain, err)
+		}
+	}
+
+	_, err = dds.updateHosts(ctx, config.Domain, func(result DomainDNSGetHostsResult) (*DomainsDNSSetHostsArgs, error) {
+		return config.SetHostsArgs(), nil
+	})
+	if err != nil {
+		return fmt.Errorf("unable to set the host records of %s: %v", config.Domain, err)
+	}
+
+	if strings.EqualFold(config.EmailType, EmailTypeForward) {
+		if _, err := dds.SetEmailForwarding(ctx, config.Domain, config.EmailForwardingArgs()); err != nil {
+			return fmt.Errorf("unable to set the email forwarding of %s: %v", config.Domain, err)
+		}
+	}
+
+	return nil
+}
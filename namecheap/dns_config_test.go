@@ -0,0 +1,320 @@
+package namecheap
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const fakeDNSConfigYAML = `version: 1
+domain: domain.net
+nameservers:
+  mode: default
+email_type: FWD
+records:
+  - host: '@'
+    type: A
+    address: 192.0.2.1
+    ttl: 300
+  - host: www
+    type: CNAME
+    address: domain.net.
+email_forwards:
+  - mailbox: info
+    forward_to: info@example.com
+`
+
+func TestLoadDNSConfig(t *testing.T) {
+	expected := &DNSConfig{
+		Version:     1,
+		Domain:      "domain.net",
+		Nameservers: DNSConfigNameservers{Mode: "default"},
+		EmailType:   "FWD",
+		Records: []DNSConfigRecord{
+			{Host: "@", Type: "A", Address: "192.0.2.1", TTL: 300},
+			{Host: "www", Type: "CNAME", Address: "domain.net."},
+		},
+		EmailForwards: []DNSConfigEmailForward{{Mailbox: "info", ForwardTo: "info@example.com"}},
+	}
+
+	t.Run("yaml", func(t *testing.T) {
+		config, err := LoadDNSConfig(strings.NewReader(fakeDNSConfigYAML), "yaml")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, expected, config)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		config, err := LoadDNSConfig(strings.NewReader(`{
+			"version": 1,
+			"domain": "domain.net",
+			"nameservers": {"mode": "default"},
+			"email_type": "FWD",
+			"records": [
+				{"host": "@", "type": "A", "address": "192.0.2.1", "ttl": 300},
+				{"host": "www", "type": "CNAME", "address": "domain.net."}
+			],
+			"email_forwards": [{"mailbox": "info", "forward_to": "info@example.com"}]
+		}`), "json")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, expected, config)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "dns-config")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "domain.net.yml")
+		if err := ioutil.WriteFile(path, []byte(fakeDNSConfigYAML), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		config, err := LoadDNSConfigFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, expected, config)
+	})
+
+	t.Run("round_trip", func(t *testing.T) {
+		for _, format := range []string{DNSConfigFormatJSON, DNSConfigFormatYAML} {
+			var buffer bytes.Buffer
+			if err := expected.Write(&buffer, format); err != nil {
+				t.Fatal(err)
+			}
+
+			config, err := LoadDNSConfig(&buffer, format)
+			if err != nil {
+				t.Fatal(format, err)
+			}
+			assert.Equal(t, expected, config, format)
+		}
+	})
+
+	t.Run("unknown_field", func(t *testing.T) {
+		_, err := LoadDNSConfig(strings.NewReader(fakeDNSConfigYAML+"ttl: 300\n"), "yaml")
+		assert.Error(t, err)
+
+		_, err = LoadDNSConfig(strings.NewReader(`{"version": 1, "domain": "domain.net", "nameserver": {}}`), "json")
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid_format", func(t *testing.T) {
+		_, err := LoadDNSConfig(strings.NewReader(""), "toml")
+		assert.EqualError(t, err, "invalid format value: toml, allowed values are json, yaml")
+	})
+}
+
+func TestDNSConfigValidate(t *testing.T) {
+	var cases = map[string]struct {
+		Config         DNSConfig
+		ExpectedFields []string
+	}{
+		"valid_custom": {
+			Config:         DNSConfig{Version: 1, Domain: "domain.net", Nameservers: DNSConfigNameservers{Mode: "custom", Servers: []string{"ns1.other.net", "ns2.other.net"}}},
+			ExpectedFields: nil,
+		},
+		"version_and_domain": {
+			Config:         DNSConfig{Version: 2},
+			ExpectedFields: []string{"version", "domain"},
+		},
+		"custom_with_records": {
+			Config: DNSConfig{Version: 1, Domain: "domain.net",
+				Nameservers: DNSConfigNameservers{Mode: "custom", Servers: []string{"ns1.other.net"}},
+				Records:     []DNSConfigRecord{{Host: "@", Type: "A", Address: "192.0.2.1"}},
+			},
+			ExpectedFields: []string{"nameservers.servers", "nameservers.mode"},
+		},
+		"invalid_records_and_forwards": {
+			Config: DNSConfig{Version: 1, Domain: "domain.net",
+				Records:       []DNSConfigRecord{{Host: "@", Type: "SRV", Address: "x"}},
+				EmailForwards: []DNSConfigEmailForward{{ForwardTo: "example.com"}},
+			},
+			ExpectedFields: []string{"Records[0].RecordType", "email_forwards", "email_forwards[0].mailbox", "email_forwards[0].forward_to"},
+		},
+	}
+
+	for name, testCase := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := testCase.Config.Validate()
+			if testCase.ExpectedFields == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			validationErr, ok := err.(*ValidationError)
+			if assert.True(t, ok, "expected *ValidationError, got %v", err) {
+				assert.Equal(t, testCase.ExpectedFields, validationErr.Fields())
+			}
+		})
+	}
+}
+
+func TestDomainsDNSConfig(t *testing.T) {
+	fakeGetListResponse := func(isUsingOurDNS string) string {
+		return `
+			<?xml version="1.0" encoding="utf-8"?>
+			<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+				<Errors />
+				<CommandResponse Type="namecheap.domains.dns.getList">
+					<DomainDNSGetListResult Domain="domain.net" IsUsingOurDNS="` + isUsingOurDNS + `">
+						<Nameserver>ns1.other.net</Nameserver>
+						<Nameserver>ns2.other.net</Nameserver>
+					</DomainDNSGetListResult>
+				</CommandResponse>
+			</ApiResponse>`
+	}
+
+	fakeGetHostsResponse := `
+		<?xml version="1.0" encoding="utf-8"?>
+		<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+			<Errors />
+			<CommandResponse Type="namecheap.domains.dns.getHosts">
+				<DomainDNSGetHostsResult Domain="domain.net" EmailType="FWD" IsUsingOurDNS="true">
+					<host HostId="1" Name="@" Type="A" Address="192.0.2.1" MXPref="10" TTL="300" />
+				</DomainDNSGetHostsResult>
+			</CommandResponse>
+		</ApiResponse>`
+
+	fakeGetEmailForwardingResponse := `
+		<?xml version="1.0" encoding="utf-8"?>
+		<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+			<Errors />
+			<CommandResponse Type="namecheap.domains.dns.getEmailForwarding">
+				<DomainDNSGetEmailForwardingResult Domain="domain.net">
+					<Forward mailbox="info">info@example.com</Forward>
+				</DomainDNSGetEmailForwardingResult>
+			</CommandResponse>
+		</ApiResponse>`
+
+	setupConfig := func(isUsingOurDNS string) (*Client, *[]url.Values, func()) {
+		var sent []url.Values
+		mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			body, _ := ioutil.ReadAll(request.Body)
+			query, _ := url.ParseQuery(string(body))
+			sent = append(sent, query)
+
+			switch query.Get("Command") {
+			case "namecheap.domains.dns.getList":
+				_, _ = writer.Write([]byte(fakeGetListResponse(isUsingOurDNS)))
+			case "namecheap.domains.dns.getHosts":
+				_, _ = writer.Write([]byte(fakeGetHostsResponse))
+			case "namecheap.domains.dns.getEmailForwarding":
+				_, _ = writer.Write([]byte(fakeGetEmailForwardingResponse))
+			default:
+				_, _ = writer.Write([]byte(`<?xml version="1.0" encoding="utf-8"?><ApiResponse Status="OK"><Errors /><CommandResponse /></ApiResponse>`))
+			}
+		}))
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		return client, &sent, mockServer.Close
+	}
+
+	commands := func(sent []url.Values) []string {
+		var names []string
+		for _, query := range sent {
+			names = append(names, query.Get("Command"))
+		}
+		return names
+	}
+
+	t.Run("get_config_default", func(t *testing.T) {
+		client, _, closeServer := setupConfig("true")
+		defer closeServer()
+
+		config, err := client.DomainsDNS.GetConfig(context.TODO(), "domain.net")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, &DNSConfig{
+			Version:       1,
+			Domain:        "domain.net",
+			Nameservers:   DNSConfigNameservers{Mode: "default"},
+			EmailType:     "FWD",
+			Records:       []DNSConfigRecord{{Host: "@", Type: "A", Address: "192.0.2.1", TTL: 300}},
+			EmailForwards: []DNSConfigEmailForward{{Mailbox: "info", ForwardTo: "info@example.com"}},
+		}, config)
+	})
+
+	t.Run("get_config_custom", func(t *testing.T) {
+		client, sent, closeServer := setupConfig("false")
+		defer closeServer()
+
+		config, err := client.DomainsDNS.GetConfig(context.TODO(), "domain.net")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, DNSConfigNameservers{Mode: "custom", Servers: []string{"ns1.other.net", "ns2.other.net"}}, config.Nameservers)
+		assert.Equal(t, []string{"namecheap.domains.dns.getList"}, commands(*sent))
+	})
+
+	t.Run("apply_default", func(t *testing.T) {
+		client, sent, closeServer := setupConfig("false")
+		defer closeServer()
+
+		config, err := LoadDNSConfig(strings.NewReader(fakeDNSConfigYAML), "yaml")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = client.DomainsDNS.ApplyConfig(context.TODO(), config)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []string{
+			"namecheap.domains.dns.getList",
+			"namecheap.domains.dns.setDefault",
+			"namecheap.domains.dns.getHosts",
+			"namecheap.domains.dns.setHosts",
+			"namecheap.domains.dns.setEmailForwarding",
+		}, commands(*sent))
+
+		setHosts := (*sent)[3]
+		assert.Equal(t, "FWD", setHosts.Get("EmailType"))
+		assert.Equal(t, "192.0.2.1", setHosts.Get("Address1"))
+		assert.Equal(t, "www", setHosts.Get("HostName2"))
+
+		setEmailForwarding := (*sent)[4]
+		assert.Equal(t, "info", setEmailForwarding.Get("MailBox1"))
+		assert.Equal(t, "info@example.com", setEmailForwarding.Get("ForwardTo1"))
+	})
+
+	t.Run("apply_custom", func(t *testing.T) {
+		client, sent, closeServer := setupConfig("true")
+		defer closeServer()
+
+		err := client.DomainsDNS.ApplyConfig(context.TODO(), &DNSConfig{
+			Version:     1,
+			Domain:      "domain.net",
+			Nameservers: DNSConfigNameservers{Mode: "custom", Servers: []string{"ns1.other.net", "ns2.other.net"}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []string{"namecheap.domains.dns.setCustom"}, commands(*sent))
+		assert.Equal(t, "ns1.other.net,ns2.other.net", (*sent)[0].Get("Nameservers"))
+	})
+}
@@ -0,0 +1,359 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPropagationInitialInterval = 2 * time.Second
+	defaultPropagationMaxInterval     = 30 * time.Second
+)
+
+// propagationWildcardLabel replaces the * label of the wildcard hosts in the queries, the resolvers refuse to look up
+// the * names, so a name without its own records is queried for the records the wildcard synthesizes
+const propagationWildcardLabel = "wildcard-propagation-check"
+
+// DNSResolver looks up the records of a name on the DNS server at the address, e.g. 192.0.2.53:53.
+// The records are returned in the form of DomainsDNSHostRecord with the HostName set to the queried name,
+// a name without records of the type is no records and no error.
+type DNSResolver interface {
+	LookupRecords(ctx context.Context, address string, name string, recordType string) ([]DomainsDNSHostRecord, error)
+}
+
+// PropagationCheckerConfig is the configuration of PropagationChecker, the zero values are replaced with the defaults
+type PropagationCheckerConfig struct {
+	// Address of the DNS server queried instead of the authoritative nameservers of the domain, e.g. 1.1.1.1:53.
+	// If empty, then every nameserver returned by DomainsDNSService.GetList is queried on port 53
+	ResolverAddress string
+	// Resolver making the queries. If nil, then the Go resolver of the net package is used
+	Resolver DNSResolver
+	// Interval before the second check, it's doubled after every check up to MaxInterval
+	// Default value: 2 seconds
+	InitialInterval time.Duration
+	// Default value: 30 seconds
+	MaxInterval time.Duration
+}
+
+// PropagationArgs struct is an input arguments for PropagationChecker.Check and PropagationChecker.Wait functions
+type PropagationArgs struct {
+	// Domain the records belong to. Required
+	Domain string
+	// Records expected, e.g. the ones written by SetHosts. The records of a name and type must include the expected ones,
+	// the others are allowed. TTLs aren't compared. Possible types are A, AAAA, CNAME, MX, NS and TXT.
+	// The wildcard hosts, e.g. * or *.shop, are checked through a name matching them
+	Records []DomainsDNSHostRecord
+	// Nameservers expected to serve the domain, e.g. the ones set by SetCustom. They're checked as the NS records of @
+	Nameservers []string
+}
+
+// PropagationStatus is the result of a single check of all the nameservers
+type PropagationStatus struct {
+	// Addresses of the DNS servers queried
+	Servers []string
+	// Expected records not visible yet, one message per server and record, e.g.
+	// dns1.registrar-servers.com:53: www A 192.0.2.1 not found, got 192.0.2.9
+	Pending []string
+}
+
+// Propagated reports whether all the servers answer with the expected records
+func (s PropagationStatus) Propagated() bool {
+	return len(s.Pending) == 0
+}
+
+func (s PropagationStatus) String() string {
+	if s.Propagated() {
+		return fmt.Sprintf("propagated to %s", strings.Join(s.Servers, ", "))
+	}
+	return strings.Join(s.Pending, "\n")
+}
+
+// PropagationChecker waits until the changes of the host records or the nameservers of a domain are visible on its
+// authoritative nameservers:
+//
+//	checker := namecheap.NewPropagationChecker(client, nil)
+//	status, err := checker.Wait(ctx, &namecheap.PropagationArgs{Domain: "domain.net", Records: records})
+type PropagationChecker struct {
+	client *Client
+	config PropagationCheckerConfig
+}
+
+// NewPropagationChecker returns a checker querying the nameservers of the domains, nil config means the defaults
+func NewPropagationChecker(client *Client, config *PropagationCheckerConfig) *PropagationChecker {
+	checker := &PropagationChecker{client: client}
+	if config != nil {
+		checker.config = *config
+	}
+	if checker.config.Resolver == nil {
+		checker.config.Resolver = netDNSResolver{}
+	}
+	if checker.config.InitialInterval <= 0 {
+		checker.config.InitialInterval = defaultPropagationInitialInterval
+	}
+	if checker.config.MaxInterval <= 0 {
+		checker.config.MaxInterval = defaultPropagationMaxInterval
+	}
+	if checker.config.MaxInterval < checker.config.InitialInterval {
+		checker.config.MaxInterval = checker.config.InitialInterval
+	}
+	return checker
+}
+
+// Check queries the servers for the expected records once. The failed queries are reported as pending,
+// an error is returned if the args are invalid or the nameservers of the domain can't be listed.
+func (c *PropagationChecker) Check(ctx context.Context, args *PropagationArgs) (*PropagationStatus, error) {
+	expected, err := propagationExpectedRecords(args)
+	if err != nil {
+		return nil, err
+	}
+
+	servers, err := c.servers(ctx, args.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.check(ctx, args.Domain, servers, expected), nil
+}
+
+// Wait checks the servers until they all answer with the expected records or the context is done, the interval
+// between the checks grows from InitialInterval to MaxInterval. On timeout the last status is returned with the error.
+func (c *PropagationChecker) Wait(ctx context.Context, args *PropagationArgs) (*PropagationStatus, error) {
+	expected, err := propagationExpectedRecords(args)
+	if err != nil {
+		return nil, err
+	}
+
+	servers, err := c.servers(ctx, args.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := c.config.InitialInterval
+	for {
+		status := c.check(ctx, args.Domain, servers, expected)
+		if status.Propagated() {
+			return status, nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return status, fmt.Errorf("%s records haven't propagated: %v", args.Domain, ctx.Err())
+		}
+
+		if interval *= 2; interval > c.config.MaxInterval {
+			interval = c.config.MaxInterval
+		}
+	}
+}
+
+// servers returns the addresses of the DNS servers to query: the configured resolver or the nameservers of the domain
+func (c *PropagationChecker) servers(ctx context.Context, domain string) ([]string, error) {
+	if c.config.ResolverAddress != "" {
+		return []string{c.config.ResolverAddress}, nil
+	}
+
+	list, err := c.client.DomainsDNS.GetList(ctx, domain)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the nameservers of %s: %v", domain, err)
+	}
+
+	var servers []string
+	for _, nameserver := range list.DomainDNSGetListResult.Nameservers {
+		servers = append(servers, net.JoinHostPort(strings.TrimSuffix(nameserver, "."), "53"))
+	}
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("%s has no nameservers", domain)
+	}
+	return servers, nil
+}
+
+func (c *PropagationChecker) check(ctx context.Context, domain string, servers []string, expected map[propagationQuery][]DomainsDNSHostRecord) *PropagationStatus {
+	queries := make([]propagationQuery, 0, len(expected))
+	for query := range expected {
+		queries = append(queries, query)
+	}
+	sort.Slice(queries, func(i, j int) bool {
+		return queries[i].hostName+" "+queries[i].recordType < queries[j].hostName+" "+queries[j].recordType
+	})
+
+	status := &PropagationStatus{Servers: servers}
+	for _, server := range servers {
+		for _, query := range queries {
+			name := propagationQueryName(query.hostName, domain)
+
+			answers, err := c.config.Resolver.LookupRecords(ctx, server, name, query.recordType)
+			if err != nil {
+				status.Pending = append(status.Pending, fmt.Sprintf("%s: %s %s lookup failed: %v", server, query.hostName, query.recordType, err))
+				continue
+			}
+
+			for _, record := range expected[query] {
+				if !containsPropagatedRecord(answers, record) {
+					status.Pending = append(status.Pending, fmt.Sprintf("%s: %s %s not found, got %s",
+						server, query.hostName, describePropagationRecord(record), describePropagationAnswers(answers)))
+				}
+			}
+		}
+	}
+	return status
+}
+
+type propagationQuery struct {
+	hostName   string
+	recordType string
+}
+
+// propagationExpectedRecords validates the args and groups the expected records by the query answering them
+func propagationExpectedRecords(args *PropagationArgs) (map[propagationQuery][]DomainsDNSHostRecord, error) {
+	if args == nil {
+		return nil, fmt.Errorf("args is required")
+	}
+	if args.Domain == "" {
+		return nil, fmt.Errorf("Domain is required")
+	}
+
+	records := append([]DomainsDNSHostRecord{}, args.Records...)
+	for _, nameserver := range args.Nameservers {
+		records = append(records, DomainsDNSHostRecord{HostName: "@", RecordType: RecordTypeNS, Address: nameserver})
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("Records or Nameservers are required")
+	}
+
+	expected := map[propagationQuery][]DomainsDNSHostRecord{}
+	for i, record := range records {
+		key := record.Key()
+		switch key.RecordType {
+		case RecordTypeA, RecordTypeAAAA, RecordTypeCNAME, RecordTypeMX, RecordTypeNS, RecordTypeTXT:
+		default:
+			return nil, fmt.Errorf("Records[%d] %s records can't be checked, possible types are A, AAAA, CNAME, MX, NS and TXT", i, record.RecordType)
+		}
+		query := propagationQuery{hostName: key.HostName, recordType: key.RecordType}
+		expected[query] = append(expected[query], record)
+	}
+	return expected, nil
+}
+
+// propagationQueryName returns the absolute name of the host, e.g. www.domain.net. for www,
+// and wildcard-propagation-check.shop.domain.net. for *.shop
+func propagationQueryName(hostName string, domain string) string {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	if hostName == "@" {
+		return domain + "."
+	}
+	if hostName == "*" || strings.HasPrefix(hostName, "*.") {
+		hostName = propagationWildcardLabel + hostName[1:]
+	}
+	return hostName + "." + domain + "."
+}
+
+func containsPropagatedRecord(answers []DomainsDNSHostRecord, expected DomainsDNSHostRecord) bool {
+	expectedKey := expected.Key()
+	for _, answer := range answers {
+		answer.HostName = expected.HostName
+		if answer.Key() != expectedKey {
+			continue
+		}
+		if expectedKey.RecordType == RecordTypeMX && expected.MXPref != nil && (answer.MXPref == nil || *answer.MXPref != *expected.MXPref) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func describePropagationRecord(record DomainsDNSHostRecord) string {
+	description := strings.ToUpper(record.RecordType) + " " + record.Address
+	if strings.EqualFold(record.RecordType, RecordTypeMX) && record.MXPref != nil {
+		description += fmt.Sprintf(" pref=%d", *record.MXPref)
+	}
+	return description
+}
+
+func describePropagationAnswers(answers []DomainsDNSHostRecord) string {
+	if len(answers) == 0 {
+		return "nothing"
+	}
+	descriptions := make([]string, 0, len(answers))
+	for _, answer := range answers {
+		description := answer.Address
+		if answer.MXPref != nil {
+			description += fmt.Sprintf(" pref=%d", *answer.MXPref)
+		}
+		descriptions = append(descriptions, description)
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+// netDNSResolver is the DNSResolver using the Go resolver of the net package, it doesn't support CAA
+type netDNSResolver struct{}
+
+func (netDNSResolver) LookupRecords(ctx context.Context, address string, name string, recordType string) ([]DomainsDNSHostRecord, error) {
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, address)
+		},
+	}
+
+	var records []DomainsDNSHostRecord
+	record := func(address string) DomainsDNSHostRecord {
+		return DomainsDNSHostRecord{HostName: name, RecordType: recordType, Address: address}
+	}
+
+	var err error
+	switch recordType {
+	case RecordTypeA, RecordTypeAAAA:
+		network := "ip4"
+		if recordType == RecordTypeAAAA {
+			network = "ip6"
+		}
+		var ips []net.IP
+		ips, err = resolver.LookupIP(ctx, network, name)
+		for _, ip := range ips {
+			records = append(records, record(ip.String()))
+		}
+	case RecordTypeCNAME:
+		var cname string
+		cname, err = resolver.LookupCNAME(ctx, name)
+		// the name itself is returned when it has no CNAME record
+		if err == nil && !strings.EqualFold(strings.TrimSuffix(cname, "."), strings.TrimSuffix(name, ".")) {
+			records = append(records, record(cname))
+		}
+	case RecordTypeMX:
+		var mxs []*net.MX
+		mxs, err = resolver.LookupMX(ctx, name)
+		for _, mx := range mxs {
+			mxRecord := record(mx.Host)
+			mxRecord.MXPref = UInt8(uint8(mx.Pref))
+			records = append(records, mxRecord)
+		}
+	case RecordTypeNS:
+		var nss []*net.NS
+		nss, err = resolver.LookupNS(ctx, name)
+		for _, ns := range nss {
+			records = append(records, record(ns.Host))
+		}
+	case RecordTypeTXT:
+		var txts []string
+		txts, err = resolver.LookupTXT(ctx, name)
+		for _, txt := range txts {
+			records = append(records, record(txt))
+		}
+	default:
+		return nil, fmt.Errorf("%s lookups aren't supported", recordType)
+	}
+
+	if dnsErr, ok := err.(*net.DNSError); ok && dnsErr.IsNotFound {
+		return nil, nil
+	}
+	return records, err
+}
@@ -0,0 +1,349 @@
+package namecheap
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// stubDNSServer is a local UDP DNS server answering with the records set by the test
+type stubDNSServer struct {
+	conn net.PacketConn
+
+	m       sync.Mutex
+	records []dnsmessage.Resource
+	queries int
+}
+
+func newStubDNSServer(t *testing.T, records []dnsmessage.Resource) *stubDNSServer {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("Unable to listen", err)
+	}
+
+	server := &stubDNSServer{conn: conn, records: records}
+	go server.serve()
+	return server
+}
+
+func (s *stubDNSServer) Address() string {
+	return s.conn.LocalAddr().String()
+}
+
+func (s *stubDNSServer) Close() {
+	_ = s.conn.Close()
+}
+
+func (s *stubDNSServer) SetRecords(records []dnsmessage.Resource) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.records = records
+}
+
+func (s *stubDNSServer) Queries() int {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.queries
+}
+
+func (s *stubDNSServer) serve() {
+	buffer := make([]byte, 4096)
+	for {
+		n, address, err := s.conn.ReadFrom(buffer)
+		if err != nil {
+			return
+		}
+
+		var request dnsmessage.Message
+		if err := request.Unpack(buffer[:n]); err != nil || len(request.Questions) == 0 {
+			continue
+		}
+		question := request.Questions[0]
+
+		response := dnsmessage.Message{
+			Header:    dnsmessage.Header{ID: request.ID, Response: true, Authoritative: true},
+			Questions: []dnsmessage.Question{question},
+		}
+
+		s.m.Lock()
+		s.queries++
+		known := false
+		for _, record := range s.records {
+			if !strings.EqualFold(record.Header.Name.String(), question.Name.String()) {
+				continue
+			}
+			known = true
+			if record.Header.Type == question.Type || record.Header.Type == dnsmessage.TypeCNAME {
+				response.Answers = append(response.Answers, record)
+			}
+		}
+		s.m.Unlock()
+
+		if !known {
+			response.RCode = dnsmessage.RCodeNameError
+		}
+
+		packed, err := response.Pack()
+		if err == nil {
+			_, _ = s.conn.WriteTo(packed, address)
+		}
+	}
+}
+
+func stubDNSRecord(name string, body dnsmessage.ResourceBody) dnsmessage.Resource {
+	var recordType dnsmessage.Type
+	switch body.(type) {
+	case *dnsmessage.AResource:
+		recordType = dnsmessage.TypeA
+	case *dnsmessage.AAAAResource:
+		recordType = dnsmessage.TypeAAAA
+	case *dnsmessage.CNAMEResource:
+		recordType = dnsmessage.TypeCNAME
+	case *dnsmessage.MXResource:
+		recordType = dnsmessage.TypeMX
+	case *dnsmessage.NSResource:
+		recordType = dnsmessage.TypeNS
+	case *dnsmessage.TXTResource:
+		recordType = dnsmessage.TypeTXT
+	}
+	return dnsmessage.Resource{
+		Header: dnsmessage.ResourceHeader{Name: dnsmessage.MustNewName(name), Type: recordType, Class: dnsmessage.ClassINET, TTL: 60},
+		Body:   body,
+	}
+}
+
+func fakePropagatedRecords() []dnsmessage.Resource {
+	return []dnsmessage.Resource{
+		stubDNSRecord("domain.net.", &dnsmessage.MXResource{Pref: 10, MX: dnsmessage.MustNewName("mx1.domain.net.")}),
+		stubDNSRecord("domain.net.", &dnsmessage.NSResource{NS: dnsmessage.MustNewName("ns1.domain.net.")}),
+		stubDNSRecord("domain.net.", &dnsmessage.TXTResource{TXT: []string{"v=spf1 mx -all"}}),
+		stubDNSRecord("domain.net.", &dnsmessage.TXTResource{TXT: []string{strings.Repeat("a", 255), "bbb"}}),
+		stubDNSRecord("www.domain.net.", &dnsmessage.AResource{A: [4]byte{192, 0, 2, 1}}),
+		stubDNSRecord("www.domain.net.", &dnsmessage.AAAAResource{AAAA: [16]byte{0x20, 0x01, 0x0d, 0xb8, 15: 1}}),
+		stubDNSRecord("api.domain.net.", &dnsmessage.CNAMEResource{CNAME: dnsmessage.MustNewName("www.domain.net.")}),
+	}
+}
+
+type recordingDNSResolver struct {
+	m         sync.Mutex
+	addresses []string
+}
+
+func (r *recordingDNSResolver) LookupRecords(_ context.Context, address string, name string, recordType string) ([]DomainsDNSHostRecord, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.addresses = append(r.addresses, address)
+	return []DomainsDNSHostRecord{{HostName: name, RecordType: recordType, Address: "192.0.2.1"}}, nil
+}
+
+func TestPropagationCheckerCheck(t *testing.T) {
+	server := newStubDNSServer(t, fakePropagatedRecords())
+	defer server.Close()
+
+	checker := NewPropagationChecker(setupClient(nil), &PropagationCheckerConfig{ResolverAddress: server.Address()})
+
+	t.Run("propagated", func(t *testing.T) {
+		status, err := checker.Check(context.TODO(), &PropagationArgs{
+			Domain: "domain.net",
+			Records: []DomainsDNSHostRecord{
+				{HostName: "@", RecordType: RecordTypeMX, Address: "MX1.domain.net.", MXPref: UInt8(10)},
+				{HostName: "@", RecordType: RecordTypeTXT, Address: "v=spf1 mx -all"},
+				{HostName: "@", RecordType: RecordTypeTXT, Address: strings.Repeat("a", 255) + "bbb"},
+				{HostName: "www", RecordType: RecordTypeA, Address: "192.0.2.1", TTL: 300},
+				{HostName: "www", RecordType: RecordTypeAAAA, Address: "2001:0db8::0001"},
+				{HostName: "api", RecordType: RecordTypeCNAME, Address: "www.domain.net"},
+			},
+			Nameservers: []string{"ns1.domain.net"},
+		})
+		if err != nil {
+			t.Fatal("Unable to check propagation", err)
+		}
+
+		assert.True(t, status.Propagated(), status.String())
+		assert.Equal(t, []string{server.Address()}, status.Servers)
+		assert.Equal(t, "propagated to "+server.Address(), status.String())
+	})
+
+	t.Run("wildcard", func(t *testing.T) {
+		server := newStubDNSServer(t, []dnsmessage.Resource{
+			stubDNSRecord("wildcard-propagation-check.domain.net.", &dnsmessage.AResource{A: [4]byte{192, 0, 2, 7}}),
+			stubDNSRecord("wildcard-propagation-check.shop.domain.net.", &dnsmessage.AResource{A: [4]byte{192, 0, 2, 8}}),
+		})
+		defer server.Close()
+
+		checker := NewPropagationChecker(setupClient(nil), &PropagationCheckerConfig{ResolverAddress: server.Address()})
+
+		status, err := checker.Check(context.TODO(), &PropagationArgs{
+			Domain: "domain.net",
+			Records: []DomainsDNSHostRecord{
+				{HostName: "*", RecordType: RecordTypeA, Address: "192.0.2.7"},
+				{HostName: "*.shop", RecordType: RecordTypeA, Address: "192.0.2.8"},
+			},
+		})
+		if err != nil {
+			t.Fatal("Unable to check propagation", err)
+		}
+
+		assert.True(t, status.Propagated(), status.String())
+		assert.Equal(t, 2, server.Queries())
+	})
+
+	t.Run("pending", func(t *testing.T) {
+		status, err := checker.Check(context.TODO(), &PropagationArgs{
+			Domain: "domain.net",
+			Records: []DomainsDNSHostRecord{
+				{HostName: "www", RecordType: RecordTypeA, Address: "192.0.2.2"},
+				{HostName: "@", RecordType: RecordTypeMX, Address: "mx1.domain.net.", MXPref: UInt8(20)},
+				{HostName: "ftp", RecordType: RecordTypeA, Address: "192.0.2.3"},
+			},
+		})
+		if err != nil {
+			t.Fatal("Unable to check propagation", err)
+		}
+
+		assert.False(t, status.Propagated())
+		assert.Equal(t, []string{
+			server.Address() + ": @ MX mx1.domain.net. pref=20 not found, got mx1.domain.net. pref=10",
+			server.Address() + ": ftp A 192.0.2.3 not found, got nothing",
+			server.Address() + ": www A 192.0.2.2 not found, got 192.0.2.1",
+		}, status.Pending)
+	})
+
+	var errorCases = map[string]struct {
+		Args          *PropagationArgs
+		ExpectedError string
+	}{
+		"nil_args": {
+			Args:          nil,
+			ExpectedError: "args is required",
+		},
+		"missing_domain": {
+			Args:          &PropagationArgs{Nameservers: []string{"ns1.domain.net"}},
+			ExpectedError: "Domain is required",
+		},
+		"nothing_expected": {
+			Args:          &PropagationArgs{Domain: "domain.net"},
+			ExpectedError: "Records or Nameservers are required",
+		},
+		"unsupported_type": {
+			Args:          &PropagationArgs{Domain: "domain.net", Records: []DomainsDNSHostRecord{{HostName: "@", RecordType: RecordTypeURL, Address: "https://domain.net"}}},
+			ExpectedError: "Records[0] URL records can't be checked, possible types are A, AAAA, CNAME, MX, NS and TXT",
+		},
+	}
+
+	for name, c := range errorCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := checker.Check(context.TODO(), c.Args)
+
+			assert.EqualError(t, err, c.ExpectedError)
+		})
+	}
+}
+
+func TestPropagationCheckerWait(t *testing.T) {
+	args := &PropagationArgs{
+		Domain:  "domain.net",
+		Records: []DomainsDNSHostRecord{{HostName: "www", RecordType: RecordTypeA, Address: "192.0.2.2"}},
+	}
+
+	t.Run("polls_until_propagated", func(t *testing.T) {
+		server := newStubDNSServer(t, fakePropagatedRecords())
+		defer server.Close()
+
+		checker := NewPropagationChecker(setupClient(nil), &PropagationCheckerConfig{
+			ResolverAddress: server.Address(),
+			InitialInterval: time.Millisecond,
+			MaxInterval:     5 * time.Millisecond,
+		})
+
+		go func() {
+			for server.Queries() < 3 {
+				time.Sleep(time.Millisecond)
+			}
+			server.SetRecords([]dnsmessage.Resource{
+				stubDNSRecord("www.domain.net.", &dnsmessage.AResource{A: [4]byte{192, 0, 2, 2}}),
+			})
+		}()
+
+		status, err := checker.Wait(context.TODO(), args)
+		if err != nil {
+			t.Fatal("Unable to wait for propagation", err)
+		}
+
+		assert.True(t, status.Propagated())
+		assert.True(t, server.Queries() >= 3)
+	})
+
+	t.Run("context_expired", func(t *testing.T) {
+		server := newStubDNSServer(t, fakePropagatedRecords())
+		defer server.Close()
+
+		checker := NewPropagationChecker(setupClient(nil), &PropagationCheckerConfig{
+			ResolverAddress: server.Address(),
+			InitialInterval: time.Millisecond,
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		status, err := checker.Wait(ctx, args)
+
+		assert.EqualError(t, err, "domain.net records haven't propagated: context deadline exceeded")
+		if assert.NotNil(t, status) {
+			assert.Equal(t, []string{server.Address() + ": www A 192.0.2.2 not found, got 192.0.2.1"}, status.Pending)
+		}
+	})
+}
+
+func TestPropagationCheckerNameservers(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		_, _ = writer.Write([]byte(`
+			<?xml version="1.0" encoding="utf-8"?>
+			<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+				<Errors />
+				<CommandResponse Type="namecheap.domains.dns.getList">
+					<DomainDNSGetListResult Domain="domain.net" IsUsingOurDNS="true" IsPremiumDNS="false" IsUsingFreeDNS="false">
+						<Nameserver>dns1.registrar-servers.com</Nameserver>
+						<Nameserver>dns2.registrar-servers.com.</Nameserver>
+					</DomainDNSGetListResult>
+				</CommandResponse>
+			</ApiResponse>
+		`))
+	}))
+	defer mockServer.Close()
+
+	client := setupClient(nil)
+	client.BaseURL = mockServer.URL
+
+	resolver := &recordingDNSResolver{}
+	checker := NewPropagationChecker(client, &PropagationCheckerConfig{Resolver: resolver})
+
+	status, err := checker.Check(context.TODO(), &PropagationArgs{
+		Domain:  "domain.net",
+		Records: []DomainsDNSHostRecord{{HostName: "www", RecordType: RecordTypeA, Address: "192.0.2.1"}},
+	})
+	if err != nil {
+		t.Fatal("Unable to check propagation", err)
+	}
+
+	expected := []string{"dns1.registrar-servers.com:53", "dns2.registrar-servers.com:53"}
+	assert.True(t, status.Propagated())
+	assert.Equal(t, expected, status.Servers)
+	assert.Equal(t, expected, resolver.addresses)
+}
+
+func TestNewPropagationChecker(t *testing.T) {
+	checker := NewPropagationChecker(setupClient(nil), nil)
+
+	assert.Equal(t, 2*time.Second, checker.config.InitialInterval)
+	assert.Equal(t, 30*time.Second, checker.config.MaxInterval)
+	assert.Equal(t, netDNSResolver{}, checker.config.Resolver)
+}
@@ -22,15 +22,14 @@ type DomainCheckCommandResponse struct {
 
 type DomainCheckResult struct {
 	Domain                   string `xml:"Domain,attr"`                   // Domain name for which you wish to check availability
-	Available                string `xml:"Available,attr"`                // Indicates whether the domain name is available for registration
-	IsPremiumName            string `xml:"IsPremiumName,attr"`            // Indicates whether the domain name is premium
-	PremiumRegistrationPrice string `xml:"PremiumRegistrationPrice,attr"` // Registration Price for the premium domain
-	PremiumRenewalPrice      string `xml:"PremiumRenewalPrice,attr"`      // Renewal price for the premium domain
-	PremiumRestorePrice      string `xml:"PremiumRestorePrice,attr"`      // Restore price for the premium domain
-	PremiumTransferPrice     string `xml:"PremiumTransferPrice,attr"`     // Transfer price for the premium domain
-	IcannFee                 string `xml:"IcannFee,attr"`                 // Fee charged by ICANN
-	EapFee                   string `xml:"EapFee,attr"`                   // Purchase fee for the premium domain during Early Access Program (EAP)*
-
+	Available                bool   `xml:"Available,attr"`                // Indicates whether the domain name is available for registration
+	IsPremiumName            bool   `xml:"IsPremiumName,attr"`            // Indicates whether the domain name is premium
+	PremiumRegistrationPrice Money  `xml:"PremiumRegistrationPrice,attr"` // Registration Price for the premium domain
+	PremiumRenewalPrice      Money  `xml:"PremiumRenewalPrice,attr"`      // Renewal price for the premium domain
+	PremiumRestorePrice      Money  `xml:"PremiumRestorePrice,attr"`      // Restore price for the premium domain
+	PremiumTransferPrice     Money  `xml:"PremiumTransferPrice,attr"`     // Transfer price for the premium domain
+	IcannFee                 Money  `xml:"IcannFee,attr"`                 // Fee charged by ICANN
+	EapFee                   Money  `xml:"EapFee,attr"`                   // Purchase fee for the premium domain during Early Access Program (EAP)*
 }
 
 // Checks the availability of domains
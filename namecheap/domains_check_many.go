@@ -0,0 +1,205 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DomainsCheckMaxPerCall is the maximum number of domains namecheap.domains.check accepts per call
+	DomainsCheckMaxPerCall = 50
+
+	defaultCheckManyConcurrency = 2
+	// defaultCheckManyRequestsPerMinute is the per-minute limit of the Namecheap API
+	defaultCheckManyRequestsPerMinute = 20
+)
+
+// DomainsCheckManyArgs struct is an input arguments for DomainsService.CheckMany function
+type DomainsCheckManyArgs struct {
+	// Domains to check. Names are trimmed, lower-cased and de-duplicated.
+	DomainList []string
+	// Number of domains to be sent per API call. Maximum value is DomainsCheckMaxPerCall.
+	// Default value: DomainsCheckMaxPerCall
+	ChunkSize int
+	// Number of API calls to be run simultaneously
+	// Default value: 2
+	Concurrency int
+	// Maximum number of API calls to be started per minute, the calls are spread evenly over the minute
+	// Default value: 20
+	RequestsPerMinute int
+}
+
+// CheckMany checks the availability of any number of domains.
+// The names are validated first, then split into chunks of the per-call limit which are checked concurrently,
+// starting at most RequestsPerMinute calls per minute.
+// Results are returned in the order of the de-duplicated DomainList.
+//
+// If some of the chunks fail, the results of the successful ones are returned along with the error.
+func (ds *DomainsService) CheckMany(ctx context.Context, args *DomainsCheckManyArgs) ([]DomainCheckResult, error) {
+	if args == nil {
+		return nil, fmt.Errorf("args are required")
+	}
+
+	domains, err := normalizeDomainsCheckList(args.DomainList)
+	if err != nil {
+		return nil, err
+	}
+
+	chunkSize := args.ChunkSize
+	if chunkSize == 0 {
+		chunkSize = DomainsCheckMaxPerCall
+	}
+	if chunkSize < 1 || chunkSize > DomainsCheckMaxPerCall {
+		return nil, fmt.Errorf("invalid ChunkSize value: %d, minimum value is 1, and maximum value is %d", chunkSize, DomainsCheckMaxPerCall)
+	}
+
+	concurrency := args.Concurrency
+	if concurrency == 0 {
+		concurrency = defaultCheckManyConcurrency
+	}
+	if concurrency < 1 {
+		return nil, fmt.Errorf("invalid Concurrency value: %d, minimum value is 1", concurrency)
+	}
+
+	requestsPerMinute := args.RequestsPerMinute
+	if requestsPerMinute == 0 {
+		requestsPerMinute = defaultCheckManyRequestsPerMinute
+	}
+	if requestsPerMinute < 1 {
+		return nil, fmt.Errorf("invalid RequestsPerMinute value: %d, minimum value is 1", requestsPerMinute)
+	}
+	interval := time.Minute / time.Duration(requestsPerMinute)
+
+	var chunks [][]string
+	for start := 0; start < len(domains); start += chunkSize {
+		end := start + chunkSize
+		if end > len(domains) {
+			end = len(domains)
+		}
+		chunks = append(chunks, domains[start:end])
+	}
+
+	chunkResults := make([][]DomainCheckResult, len(chunks))
+	chunkErrors := make([]error, len(chunks))
+
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var nextCall time.Time
+
+	for i, chunk := range chunks {
+		if err := waitUntil(ctx, nextCall); err != nil {
+			chunkErrors[i] = err
+			continue
+		}
+
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			chunkErrors[i] = ctx.Err()
+			continue
+		}
+		nextCall = time.Now().Add(interval)
+
+		wg.Add(1)
+		go func(i int, chunk []string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			chunkResults[i], chunkErrors[i] = ds.Check(ctx, chunk)
+		}(i, chunk)
+	}
+
+	wg.Wait()
+
+	byDomain := map[string]DomainCheckResult{}
+	errMessages := []string{}
+	for i := range chunks {
+		for _, result := range chunkResults[i] {
+			byDomain[strings.ToLower(result.Domain)] = result
+		}
+		if chunkErrors[i] != nil {
+			errMessages = append(errMessages, fmt.Sprintf("chunk %d (%s): %s", i+1, strings.Join(chunks[i], ","), chunkErrors[i]))
+			continue
+		}
+
+		var missing []string
+		for _, domain := range chunks[i] {
+			if _, ok := byDomain[domain]; !ok {
+				missing = append(missing, domain)
+			}
+		}
+		if len(missing) > 0 {
+			errMessages = append(errMessages, fmt.Sprintf("chunk %d: no results for %s", i+1, strings.Join(missing, ",")))
+		}
+	}
+
+	results := make([]DomainCheckResult, 0, len(domains))
+	for _, domain := range domains {
+		if result, ok := byDomain[domain]; ok {
+			results = append(results, result)
+		}
+	}
+
+	if len(errMessages) > 0 {
+		return results, fmt.Errorf("%s", strings.Join(errMessages, "; "))
+	}
+
+	return results, nil
+}
+
+// waitUntil waits until the time or the context is done, a zero time doesn't wait
+func waitUntil(ctx context.Context, t time.Time) error {
+	delay := time.Until(t)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func normalizeDomainsCheckList(domainList []string) ([]string, error) {
+	if len(domainList) == 0 {
+		return nil, fmt.Errorf("DomainList is required")
+	}
+
+	seen := map[string]bool{}
+	domains := make([]string, 0, len(domainList))
+	errMessages := []string{}
+
+	for i, domain := range domainList {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+
+		parsedDomain, err := ParseDomain(domain)
+		if err != nil {
+			errMessages = append(errMessages, fmt.Sprintf("DomainList[%d] %q: %s", i, domain, err))
+			continue
+		}
+		if parsedDomain.TRD != "" {
+			errMessages = append(errMessages, fmt.Sprintf("DomainList[%d] %q: subdomains can't be checked", i, domain))
+			continue
+		}
+
+		if seen[domain] {
+			continue
+		}
+		seen[domain] = true
+		domains = append(domains, domain)
+	}
+
+	if len(errMessages) > 0 {
+		return nil, fmt.Errorf("invalid domains: %s", strings.Join(errMessages, "; "))
+	}
+
+	return domains, nil
+}
@@ -0,0 +1,238 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainsCheck(t *testing.T) {
+	fakeResponse := `
+		<?xml version="1.0" encoding="utf-8"?>
+		<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+			<Errors />
+			<Warnings />
+			<RequestedCommand>namecheap.domains.check</RequestedCommand>
+			<CommandResponse Type="namecheap.domains.check">
+				<DomainCheckResult Domain="domain.com" Available="false" ErrorNo="0" Description="" IsPremiumName="false" PremiumRegistrationPrice="0" PremiumRenewalPrice="0" PremiumRestorePrice="0" PremiumTransferPrice="0" IcannFee="0" EapFee="0" />
+				<DomainCheckResult Domain="premium.net" Available="true" ErrorNo="0" Description="" IsPremiumName="true" PremiumRegistrationPrice="13000.0000" PremiumRenewalPrice="13.48" PremiumRestorePrice="65.00" PremiumTransferPrice="13.48" IcannFee="0.18" EapFee="0.0" />
+			</CommandResponse>
+			<Server>PHX01SBAPIEXT05</Server>
+			<GMTTimeDifference>--4:00</GMTTimeDifference>
+			<ExecutionTime>0.011</ExecutionTime>
+		</ApiResponse>
+	`
+
+	t.Run("request_data_passing", func(t *testing.T) {
+		var sentBody url.Values
+
+		mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			body, _ := ioutil.ReadAll(request.Body)
+			query, _ := url.ParseQuery(string(body))
+			sentBody = query
+			_, _ = writer.Write([]byte(fakeResponse))
+		}))
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		_, err := client.Domains.Check(context.TODO(), []string{"domain.com", "premium.net"})
+		if err != nil {
+			t.Fatal("Unable to check domains", err)
+		}
+
+		assert.Equal(t, "namecheap.domains.check", sentBody.Get("Command"))
+		assert.Equal(t, "domain.com,premium.net", sentBody.Get("DomainList"))
+	})
+
+	t.Run("typed_response_parsing", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			_, _ = writer.Write([]byte(fakeResponse))
+		}))
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		results, err := client.Domains.Check(context.TODO(), []string{"domain.com", "premium.net"})
+		if err != nil {
+			t.Fatal("Unable to check domains", err)
+		}
+
+		if assert.Len(t, results, 2) {
+			assert.False(t, results[0].Available)
+			assert.False(t, results[0].IsPremiumName)
+			assert.True(t, results[0].PremiumRegistrationPrice.IsZero())
+
+			assert.True(t, results[1].Available)
+			assert.True(t, results[1].IsPremiumName)
+			assert.Equal(t, MustParseMoney("13000", "USD"), results[1].PremiumRegistrationPrice)
+			assert.Equal(t, "13.48 USD", results[1].PremiumRenewalPrice.String())
+			assert.Equal(t, "0.18", results[1].IcannFee.Amount.String())
+		}
+	})
+}
+
+func TestDomainsCheckMany(t *testing.T) {
+	// the mock server responds with every requested domain being available
+	newMockServer := func(calls *[]string, m *sync.Mutex) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			body, _ := ioutil.ReadAll(request.Body)
+			query, _ := url.ParseQuery(string(body))
+
+			m.Lock()
+			*calls = append(*calls, query.Get("DomainList"))
+			m.Unlock()
+
+			var results strings.Builder
+			for _, domain := range strings.Split(query.Get("DomainList"), ",") {
+				results.WriteString(fmt.Sprintf(`<DomainCheckResult Domain="%s" Available="true" IsPremiumName="false" />`, domain))
+			}
+
+			_, _ = writer.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+				<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+					<Errors />
+					<CommandResponse Type="namecheap.domains.check">` + results.String() + `</CommandResponse>
+				</ApiResponse>`))
+		}))
+	}
+
+	t.Run("chunking_and_ordering", func(t *testing.T) {
+		var calls []string
+		var m sync.Mutex
+		mockServer := newMockServer(&calls, &m)
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		var domains []string
+		for i := 0; i < 120; i++ {
+			domains = append(domains, fmt.Sprintf("name-%03d.com", i))
+		}
+		domains = append(domains, " NAME-000.COM ")
+
+		results, err := client.Domains.CheckMany(context.TODO(), &DomainsCheckManyArgs{
+			DomainList:        domains,
+			Concurrency:       3,
+			RequestsPerMinute: 60000,
+		})
+		if err != nil {
+			t.Fatal("Unable to check domains", err)
+		}
+
+		assert.Len(t, calls, 3)
+		if assert.Len(t, results, 120) {
+			for i, result := range results {
+				assert.Equal(t, fmt.Sprintf("name-%03d.com", i), result.Domain)
+				assert.True(t, result.Available)
+			}
+		}
+	})
+
+	t.Run("custom_chunk_size", func(t *testing.T) {
+		var calls []string
+		var m sync.Mutex
+		mockServer := newMockServer(&calls, &m)
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		_, err := client.Domains.CheckMany(context.TODO(), &DomainsCheckManyArgs{
+			DomainList:        []string{"a.com", "b.com", "c.com", "d.com", "e.com"},
+			ChunkSize:         2,
+			RequestsPerMinute: 60000,
+		})
+		if err != nil {
+			t.Fatal("Unable to check domains", err)
+		}
+
+		assert.Len(t, calls, 3)
+	})
+
+	t.Run("rate_limit", func(t *testing.T) {
+		var calls []string
+		var m sync.Mutex
+		mockServer := newMockServer(&calls, &m)
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		start := time.Now()
+		_, err := client.Domains.CheckMany(context.TODO(), &DomainsCheckManyArgs{
+			DomainList:        []string{"a.com", "b.com", "c.com"},
+			ChunkSize:         1,
+			Concurrency:       3,
+			RequestsPerMinute: 600,
+		})
+		if err != nil {
+			t.Fatal("Unable to check domains", err)
+		}
+
+		assert.Len(t, calls, 3)
+		assert.True(t, time.Since(start) >= 200*time.Millisecond, "3 calls at 600 per minute take at least 200ms")
+	})
+
+	t.Run("missing_results", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			_, _ = writer.Write([]byte(`<?xml version="1.0" encoding="utf-8"?>
+				<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+					<Errors />
+					<CommandResponse Type="namecheap.domains.check">
+						<DomainCheckResult Domain="a.com" Available="true" IsPremiumName="false" />
+					</CommandResponse>
+				</ApiResponse>`))
+		}))
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		results, err := client.Domains.CheckMany(context.TODO(), &DomainsCheckManyArgs{
+			DomainList: []string{"a.com", "b.com", "c.com"},
+		})
+
+		assert.EqualError(t, err, "chunk 1: no results for b.com,c.com")
+		if assert.Len(t, results, 1) {
+			assert.Equal(t, "a.com", results[0].Domain)
+		}
+	})
+
+	t.Run("invalid_domains", func(t *testing.T) {
+		client := setupClient(nil)
+
+		_, err := client.Domains.CheckMany(context.TODO(), &DomainsCheckManyArgs{
+			DomainList: []string{"good.com", "bad_name.com", "www.sub.com"},
+		})
+
+		if assert.NotNil(t, err) {
+			assert.Contains(t, err.Error(), `DomainList[1] "bad_name.com"`)
+			assert.Contains(t, err.Error(), `DomainList[2] "www.sub.com": subdomains can't be checked`)
+		}
+	})
+
+	t.Run("invalid_args", func(t *testing.T) {
+		client := setupClient(nil)
+
+		_, err := client.Domains.CheckMany(context.TODO(), &DomainsCheckManyArgs{})
+		assert.EqualError(t, err, "DomainList is required")
+
+		_, err = client.Domains.CheckMany(context.TODO(), &DomainsCheckManyArgs{DomainList: []string{"a.com"}, ChunkSize: 51})
+		assert.EqualError(t, err, "invalid ChunkSize value: 51, minimum value is 1, and maximum value is 50")
+
+		_, err = client.Domains.CheckMany(context.TODO(), &DomainsCheckManyArgs{DomainList: []string{"a.com"}, RequestsPerMinute: -1})
+		assert.EqualError(t, err, "invalid RequestsPerMinute value: -1, minimum value is 1")
+	})
+}
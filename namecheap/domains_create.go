@@ -32,14 +32,14 @@ WhoisguardEnable	Possible responses: True, False. Indicates whether domain priva
 NonRealTimeDomain	Possible responses: True, False. Indicates whether the domain registration is instant (real-time) or not.
 */
 type DomainCreateResult struct {
-	Domain        string `xml:"Domain"`
-	Registered    string `xml:"Registered"`
-	ChargedAmount string `xml:"ChargedAmount"`
-	DomainID      string `xml:"DomainID"`
-	OrderID       string `xml:"OrderID"`
-	TransactionID string `xml:"TransactionID"`
-	Whoisguard    string `xml:"Whoisguard"`
-	NonRealTime   string `xml:"NonRealTime"`
+	Domain        string `xml:"Domain,attr"`
+	Registered    string `xml:"Registered,attr"`
+	ChargedAmount Money  `xml:"ChargedAmount,attr"`
+	DomainID      string `xml:"DomainID,attr"`
+	OrderID       string `xml:"OrderID,attr"`
+	TransactionID string `xml:"TransactionID,attr"`
+	Whoisguard    string `xml:"WhoisguardEnable,attr"`
+	NonRealTime   string `xml:"NonRealTimeDomain,attr"`
 }
 
 /*
@@ -126,7 +126,7 @@ BillingFax	String	50	No	Fax number in the format +NNN.NNNNNNNNNN
 BillingEmailAddress	String	255	No	Email address of the Billing user
 
 IdnCode	String	100	No	Code of Internationalized Domain Name (please refer to the note below)
-Extended attributes	String n/a Yes	Required for .us, .eu, .ca, .co.uk, .org.uk, .me.uk, .nu , .com.au, .net.au, .org.au, .es, .nom.es, .com.es, .org.es, .de, .fr TLDs only
+ExtendedAttributes	ExtendedAttributes n/a Yes	Required for .us, .eu, .ca, .co.uk, .org.uk, .me.uk, .nu , .com.au, .net.au, .org.au, .es, .nom.es, .com.es, .org.es, .de, .fr TLDs only
 Nameservers	String n/a No Comma-separated list of custom nameservers to be associated with the domain name
 AddFreeWhoisguard	String	10	No	Adds free domain privacy for the domain -- Default Value: no
 WGEnabled	String	10	No	Enables free domain privacy for the domain -- Default Value: no
@@ -217,20 +217,52 @@ type DomainCreateArgs struct {
 	BillingFax                 string // Optional
 	BillingEmailAddress        string // Optional
 
-	IdnCode            string // Optional
-	ExtendedAttributes string // Required -- Check docs!
-	Nameservers        string // Optional
-	AddFreeWhoisguard  string // Optional
-	WGEnabled          string // Optional
-	IsPremiumDomain    bool   // Optional
-	PremiumPrice       string // Optional
-	EapFee             string // Optional
+	IdnCode            string             // Optional
+	ExtendedAttributes ExtendedAttributes // Required for some TLDs, see IsExtendedAttributesRequired
+	Nameservers        string             // Optional
+	AddFreeWhoisguard  string             // Optional
+	WGEnabled          string             // Optional
+	IsPremiumDomain    bool               // Optional
+	PremiumPrice       Money              // Optional
+	EapFee             Money              // Optional
 }
 
 // Registers a given domain name
+// The arguments are validated first, see DomainCreateArgs.Validate. The registration is checked
+// against the client SpendingPolicy, if any.
 //
-// Namecheap doc: https://www.namecheap.com/support/api/methods/domains/check/
+// Namecheap doc: https://www.namecheap.com/support/api/methods/domains/create/
 func (ds *DomainsService) Create(ctx context.Context, args DomainCreateArgs) (*DomainCreateResult, error) {
+	result, _, err := ds.createGuarded(ctx, args)
+	return result, err
+}
+
+// createGuarded validates the arguments and sends the registration under the spending policy.
+// It reports whether the outcome is unknown, i.e. the request was sent but no response has been received.
+func (ds *DomainsService) createGuarded(ctx context.Context, args DomainCreateArgs) (*DomainCreateResult, bool, error) {
+	err := args.Validate()
+	if err != nil {
+		return nil, false, err
+	}
+
+	var result *DomainCreateResult
+	var unknownOutcome bool
+
+	err = ds.client.spendGuard.spend(ctx, spendOperationForCreate(args), args.PromotionCode, func() (*Money, bool, error) {
+		var err error
+		result, err = ds.create(ctx, args)
+		if result != nil && strings.EqualFold(result.Registered, "true") {
+			return &result.ChargedAmount, true, err
+		}
+		// API errors are definitive, the transport ones leave the outcome unknown
+		unknownOutcome = result == nil && err != nil
+		return nil, result != nil, err
+	})
+
+	return result, unknownOutcome, err
+}
+
+func (ds *DomainsService) create(ctx context.Context, args DomainCreateArgs) (*DomainCreateResult, error) {
 	var resp DomainCreateResponse
 
 	params := domainCreateArgsToParams(args)
@@ -250,165 +282,267 @@ func (ds *DomainsService) Create(ctx context.Context, args DomainCreateArgs) (*D
 		apiErr = fmt.Errorf("%s", strings.Join(errMessages, "; "))
 	}
 
-	return &resp.CommandResponse.DomainCreateResult, apiErr
-}
-
-func domainCreateArgsToParams(args DomainCreateArgs) map[string]string {
-	params := map[string]string{
-		"DomainName": args.DomainName,
-		"Years":      strconv.Itoa(args.Years),
-
-		"RegistrantFirstName":     args.RegistrantFirstName,
-		"RegistrantLastName":      args.RegistrantLastName,
-		"RegistrantAddress1":      args.RegistrantAddress1,
-		"RegistrantCity":          args.RegistrantCity,
-		"RegistrantStateProvince": args.RegistrantStateProvince,
-		"RegistrantPostalCode":    args.RegistrantPostalCode,
-		"RegistrantCountry":       args.RegistrantCountry,
-		"RegistrantPhone":         args.RegistrantPhone,
-		"RegistrantEmailAddress":  args.RegistrantEmailAddress,
-
-		"TechFirstName":     args.TechFirstName,
-		"TechLastName":      args.TechLastName,
-		"TechAddress1":      args.TechAddress1,
-		"TechCity":          args.TechCity,
-		"TechStateProvince": args.TechStateProvince,
-		"TechPostalCode":    args.TechPostalCode,
-		"TechCountry":       args.TechCountry,
-		"TechPhone":         args.TechPhone,
-		"TechEmailAddress":  args.TechEmailAddress,
-
-		"AdminFirstName":     args.AdminFirstName,
-		"AdminLastName":      args.AdminLastName,
-		"AdminAddress1":      args.AdminAddress1,
-		"AdminCity":          args.AdminCity,
-		"AdminStateProvince": args.AdminStateProvince,
-		"AdminPostalCode":    args.AdminPostalCode,
-		"AdminCountry":       args.AdminCountry,
-		"AdminPhone":         args.AdminPhone,
-		"AdminEmailAddress":  args.AdminEmailAddress,
-
-		"AuxBillingFirstName":     args.AuxBillingFirstName,
-		"AuxBillingLastName":      args.AuxBillingLastName,
-		"AuxBillingAddress1":      args.AuxBillingAddress1,
-		"AuxBillingCity":          args.AuxBillingCity,
-		"AuxBillingStateProvince": args.AuxBillingStateProvince,
-		"AuxBillingPostalCode":    args.AuxBillingPostalCode,
-		"AuxBillingCountry":       args.AuxBillingCountry,
-		"AuxBillingPhone":         args.AuxBillingPhone,
-		"AuxBillingEmailAddress":  args.AuxBillingEmailAddress,
-
-		"IsPremiumDomain": strconv.FormatBool(args.IsPremiumDomain),
-	}
-
-	if args.PromotionCode != "" {
-		params["PromotionCode"] = args.PromotionCode
-	}
-
-	if args.RegistrantOrganizationName != "" {
-		params["RegistrantOrganizationName"] = args.RegistrantOrganizationName
-	}
-
-	if args.RegistrantJobTitle != "" {
-		params["RegistrantJobTitle"] = args.RegistrantJobTitle
-	}
-
-	if args.RegistrantAddress2 != "" {
-		params["RegistrantAddress2"] = args.RegistrantAddress2
-	}
-
-	if args.RegistrantStateProvinceChoice != "" {
-		params["RegistrantStateProvinceChoice"] = args.RegistrantStateProvinceChoice
+	if resp.CommandResponse == nil {
+		return &DomainCreateResult{}, apiErr
 	}
 
-	if args.RegistrantPhoneExt != "" {
-		params["RegistrantPhoneExt"] = args.RegistrantPhoneExt
-	}
-
-	if args.RegistrantFax != "" {
-		params["RegistrantFax"] = args.RegistrantFax
-	}
-
-	if args.TechOrganizationName != "" {
-		params["TechOrganizationName"] = args.TechOrganizationName
-	}
-
-	if args.TechJobTitle != "" {
-		params["TechJobTitle"] = args.TechJobTitle
-	}
-
-	if args.TechAddress2 != "" {
-		params["TechAddress2"] = args.TechAddress2
-	}
+	return &resp.CommandResponse.DomainCreateResult, apiErr
+}
 
-	if args.TechStateProvinceChoice != "" {
-		params["TechStateProvinceChoice"] = args.TechStateProvinceChoice
+// NewDomainCreateArgs returns DomainCreateArgs with the same contact used for the Registrant, Tech, Admin and AuxBilling roles
+func NewDomainCreateArgs(domainName string, years int, contact Contact) DomainCreateArgs {
+	args := DomainCreateArgs{
+		DomainName: domainName,
+		Years:      years,
 	}
-
-	if args.TechPhoneExt != "" {
-		params["TechPhoneExt"] = args.TechPhoneExt
+	for _, role := range RequiredContactRoles {
+		args.SetContact(role, contact)
 	}
+	return args
+}
 
-	if args.TechFax != "" {
-		params["TechFax"] = args.TechFax
+// GetContact returns the contact of the role: Registrant, Tech, Admin, AuxBilling or Billing.
+// The Billing contact has no OrganizationName and JobTitle.
+func (args DomainCreateArgs) GetContact(role string) Contact {
+	switch role {
+	case ContactRoleRegistrant:
+		return Contact{
+			OrganizationName:    args.RegistrantOrganizationName,
+			JobTitle:            args.RegistrantJobTitle,
+			FirstName:           args.RegistrantFirstName,
+			LastName:            args.RegistrantLastName,
+			Address1:            args.RegistrantAddress1,
+			Address2:            args.RegistrantAddress2,
+			City:                args.RegistrantCity,
+			StateProvince:       args.RegistrantStateProvince,
+			StateProvinceChoice: args.RegistrantStateProvinceChoice,
+			PostalCode:          args.RegistrantPostalCode,
+			Country:             args.RegistrantCountry,
+			Phone:               args.RegistrantPhone,
+			PhoneExt:            args.RegistrantPhoneExt,
+			Fax:                 args.RegistrantFax,
+			EmailAddress:        args.RegistrantEmailAddress,
+		}
+	case ContactRoleTech:
+		return Contact{
+			OrganizationName:    args.TechOrganizationName,
+			JobTitle:            args.TechJobTitle,
+			FirstName:           args.TechFirstName,
+			LastName:            args.TechLastName,
+			Address1:            args.TechAddress1,
+			Address2:            args.TechAddress2,
+			City:                args.TechCity,
+			StateProvince:       args.TechStateProvince,
+			StateProvinceChoice: args.TechStateProvinceChoice,
+			PostalCode:          args.TechPostalCode,
+			Country:             args.TechCountry,
+			Phone:               args.TechPhone,
+			PhoneExt:            args.TechPhoneExt,
+			Fax:                 args.TechFax,
+			EmailAddress:        args.TechEmailAddress,
+		}
+	case ContactRoleAdmin:
+		return Contact{
+			OrganizationName:    args.AdminOrganizationName,
+			JobTitle:            args.AdminJobTitle,
+			FirstName:           args.AdminFirstName,
+			LastName:            args.AdminLastName,
+			Address1:            args.AdminAddress1,
+			Address2:            args.AdminAddress2,
+			City:                args.AdminCity,
+			StateProvince:       args.AdminStateProvince,
+			StateProvinceChoice: args.AdminStateProvinceChoice,
+			PostalCode:          args.AdminPostalCode,
+			Country:             args.AdminCountry,
+			Phone:               args.AdminPhone,
+			PhoneExt:            args.AdminPhoneExt,
+			Fax:                 args.AdminFax,
+			EmailAddress:        args.AdminEmailAddress,
+		}
+	case ContactRoleAuxBilling:
+		return Contact{
+			OrganizationName:    args.AuxBillingOrganizationName,
+			JobTitle:            args.AuxBillingJobTitle,
+			FirstName:           args.AuxBillingFirstName,
+			LastName:            args.AuxBillingLastName,
+			Address1:            args.AuxBillingAddress1,
+			Address2:            args.AuxBillingAddress2,
+			City:                args.AuxBillingCity,
+			StateProvince:       args.AuxBillingStateProvince,
+			StateProvinceChoice: args.AuxBillingStateProvinceChoice,
+			PostalCode:          args.AuxBillingPostalCode,
+			Country:             args.AuxBillingCountry,
+			Phone:               args.AuxBillingPhone,
+			PhoneExt:            args.AuxBillingPhoneExt,
+			Fax:                 args.AuxBillingFax,
+			EmailAddress:        args.AuxBillingEmailAddress,
+		}
+	case ContactRoleBilling:
+		return Contact{
+			FirstName:           args.BillingFirstName,
+			LastName:            args.BillingLastName,
+			Address1:            args.BillingAddress1,
+			Address2:            args.BillingAddress2,
+			City:                args.BillingCity,
+			StateProvince:       args.BillingStateProvince,
+			StateProvinceChoice: args.BillingStateProvinceChoice,
+			PostalCode:          args.BillingPostalCode,
+			Country:             args.BillingCountry,
+			Phone:               args.BillingPhone,
+			PhoneExt:            args.BillingPhoneExt,
+			Fax:                 args.BillingFax,
+			EmailAddress:        args.BillingEmailAddress,
+		}
 	}
+	return Contact{}
+}
 
-	if args.AdminOrganizationName != "" {
-		params["AdminOrganizationName"] = args.AdminOrganizationName
+// SetContact sets the contact of the role: Registrant, Tech, Admin, AuxBilling or Billing.
+// OrganizationName and JobTitle are ignored for the Billing contact.
+func (args *DomainCreateArgs) SetContact(role string, c Contact) {
+	switch role {
+	case ContactRoleRegistrant:
+		args.RegistrantOrganizationName = c.OrganizationName
+		args.RegistrantJobTitle = c.JobTitle
+		args.RegistrantFirstName = c.FirstName
+		args.RegistrantLastName = c.LastName
+		args.RegistrantAddress1 = c.Address1
+		args.RegistrantAddress2 = c.Address2
+		args.RegistrantCity = c.City
+		args.RegistrantStateProvince = c.StateProvince
+		args.RegistrantStateProvinceChoice = c.StateProvinceChoice
+		args.RegistrantPostalCode = c.PostalCode
+		args.RegistrantCountry = c.Country
+		args.RegistrantPhone = c.Phone
+		args.RegistrantPhoneExt = c.PhoneExt
+		args.RegistrantFax = c.Fax
+		args.RegistrantEmailAddress = c.EmailAddress
+	case ContactRoleTech:
+		args.TechOrganizationName = c.OrganizationName
+		args.TechJobTitle = c.JobTitle
+		args.TechFirstName = c.FirstName
+		args.TechLastName = c.LastName
+		args.TechAddress1 = c.Address1
+		args.TechAddress2 = c.Address2
+		args.TechCity = c.City
+		args.TechStateProvince = c.StateProvince
+		args.TechStateProvinceChoice = c.StateProvinceChoice
+		args.TechPostalCode = c.PostalCode
+		args.TechCountry = c.Country
+		args.TechPhone = c.Phone
+		args.TechPhoneExt = c.PhoneExt
+		args.TechFax = c.Fax
+		args.TechEmailAddress = c.EmailAddress
+	case ContactRoleAdmin:
+		args.AdminOrganizationName = c.OrganizationName
+		args.AdminJobTitle = c.JobTitle
+		args.AdminFirstName = c.FirstName
+		args.AdminLastName = c.LastName
+		args.AdminAddress1 = c.Address1
+		args.AdminAddress2 = c.Address2
+		args.AdminCity = c.City
+		args.AdminStateProvince = c.StateProvince
+		args.AdminStateProvinceChoice = c.StateProvinceChoice
+		args.AdminPostalCode = c.PostalCode
+		args.AdminCountry = c.Country
+		args.AdminPhone = c.Phone
+		args.AdminPhoneExt = c.PhoneExt
+		args.AdminFax = c.Fax
+		args.AdminEmailAddress = c.EmailAddress
+	case ContactRoleAuxBilling:
+		args.AuxBillingOrganizationName = c.OrganizationName
+		args.AuxBillingJobTitle = c.JobTitle
+		args.AuxBillingFirstName = c.FirstName
+		args.AuxBillingLastName = c.LastName
+		args.AuxBillingAddress1 = c.Address1
+		args.AuxBillingAddress2 = c.Address2
+		args.AuxBillingCity = c.City
+		args.AuxBillingStateProvince = c.StateProvince
+		args.AuxBillingStateProvinceChoice = c.StateProvinceChoice
+		args.AuxBillingPostalCode = c.PostalCode
+		args.AuxBillingCountry = c.Country
+		args.AuxBillingPhone = c.Phone
+		args.AuxBillingPhoneExt = c.PhoneExt
+		args.AuxBillingFax = c.Fax
+		args.AuxBillingEmailAddress = c.EmailAddress
+	case ContactRoleBilling:
+		args.BillingFirstName = c.FirstName
+		args.BillingLastName = c.LastName
+		args.BillingAddress1 = c.Address1
+		args.BillingAddress2 = c.Address2
+		args.BillingCity = c.City
+		args.BillingStateProvince = c.StateProvince
+		args.BillingStateProvinceChoice = c.StateProvinceChoice
+		args.BillingPostalCode = c.PostalCode
+		args.BillingCountry = c.Country
+		args.BillingPhone = c.Phone
+		args.BillingPhoneExt = c.PhoneExt
+		args.BillingFax = c.Fax
+		args.BillingEmailAddress = c.EmailAddress
 	}
+}
 
-	if args.AdminJobTitle != "" {
-		params["AdminJobTitle"] = args.AdminJobTitle
-	}
+// Validate checks the arguments before they're sent to the API.
+// The returned *ValidationError names every offending field, e.g. RegistrantPhone.
+func (args DomainCreateArgs) Validate() error {
+	issues := &ValidationError{}
 
-	if args.AdminAddress2 != "" {
-		params["AdminAddress2"] = args.AdminAddress2
+	if args.DomainName == "" {
+		issues.add("DomainName", "DomainName is required")
+	} else if len(args.DomainName) > 70 {
+		issues.add("DomainName", "invalid DomainName value: maximum length is 70 characters")
+	} else if parsedDomain, err := ParseDomain(args.DomainName); err != nil {
+		issues.add("DomainName", fmt.Sprintf("invalid DomainName value: %s, %v", args.DomainName, err))
+	} else {
+		validateExtendedAttributes(parsedDomain.TLD, args.ExtendedAttributes, issues)
 	}
 
-	if args.AdminStateProvinceChoice != "" {
-		params["AdminStateProvinceChoice"] = args.AdminStateProvinceChoice
+	if args.Years < 1 || args.Years > 10 {
+		issues.add("Years", fmt.Sprintf("invalid Years value: %d, minimum value is 1, and maximum value is 10", args.Years))
 	}
 
-	if args.AdminPhoneExt != "" {
-		params["AdminPhoneExt"] = args.AdminPhoneExt
+	if len(args.PromotionCode) > 20 {
+		issues.add("PromotionCode", "invalid PromotionCode value: maximum length is 20 characters")
 	}
 
-	if args.AdminFax != "" {
-		params["AdminFax"] = args.AdminFax
+	if len(args.IdnCode) > 100 {
+		issues.add("IdnCode", "invalid IdnCode value: maximum length is 100 characters")
 	}
 
-	if args.AuxBillingOrganizationName != "" {
-		params["AuxBillingOrganizationName"] = args.AuxBillingOrganizationName
+	for _, role := range RequiredContactRoles {
+		args.GetContact(role).validate(role, issues)
 	}
 
-	if args.AuxBillingJobTitle != "" {
-		params["AuxBillingJobTitle"] = args.AuxBillingJobTitle
+	if billing := args.GetContact(ContactRoleBilling); !billing.IsEmpty() {
+		billing.validate(ContactRoleBilling, issues)
 	}
 
-	if args.AuxBillingAddress2 != "" {
-		params["AuxBillingAddress2"] = args.AuxBillingAddress2
-	}
+	return issues.errorOrNil()
+}
 
-	if args.AuxBillingStateProvinceChoice != "" {
-		params["AuxBillingStateProvinceChoice"] = args.AuxBillingStateProvinceChoice
-	}
+func domainCreateArgsToParams(args DomainCreateArgs) map[string]string {
+	params := map[string]string{
+		"DomainName": args.DomainName,
+		"Years":      strconv.Itoa(args.Years),
 
-	if args.AuxBillingPhoneExt != "" {
-		params["AuxBillingPhoneExt"] = args.AuxBillingPhoneExt
+		"IsPremiumDomain": strconv.FormatBool(args.IsPremiumDomain),
 	}
 
-	if args.AuxBillingFax != "" {
-		params["AuxBillingFax"] = args.AuxBillingFax
+	for _, role := range RequiredContactRoles {
+		args.GetContact(role).toParams(role, params)
 	}
+	args.GetContact(ContactRoleBilling).toParams(ContactRoleBilling, params)
 
-	if args.AuxBillingEmailAddress != "" {
-		params["AuxBillingEmailAddress"] = args.AuxBillingEmailAddress
+	if args.PromotionCode != "" {
+		params["PromotionCode"] = args.PromotionCode
 	}
 
 	if args.IdnCode != "" {
 		params["IdnCode"] = args.IdnCode
 	}
 
+	extendedAttributesToParams(args.ExtendedAttributes, params)
+
 	if args.Nameservers != "" {
 		params["Nameservers"] = args.Nameservers
 	}
@@ -421,12 +555,12 @@ func domainCreateArgsToParams(args DomainCreateArgs) map[string]string {
 		params["WGEnabled"] = args.WGEnabled
 	}
 
-	if args.PremiumPrice != "" {
-		params["PremiumPrice"] = args.PremiumPrice
+	if !args.PremiumPrice.IsZero() {
+		params["PremiumPrice"] = args.PremiumPrice.Amount.String()
 	}
 
-	if args.EapFee != "" {
-		params["EapFee"] = args.EapFee
+	if !args.EapFee.IsZero() {
+		params["EapFee"] = args.EapFee.Amount.String()
 	}
 
 	return params
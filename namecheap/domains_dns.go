@@ -1,11 +1,15 @@
 package namecheap
 
 // DomainsDNSService includes the following methods:
+// DomainsDNSService.GetEmailForwarding - gets email forwarding settings for the requested domain
 // DomainsDNSService.GetHosts - retrieves DNS host record settings for the requested domain
 // DomainsDNSService.GetList - gets a list of DNS servers associated with the requested domain
 // DomainsDNSService.SetCustom - sets domain to use custom DNS servers
 // DomainsDNSService.SetDefault - sets domain to use our default DNS servers
+// DomainsDNSService.SetEmailForwarding - sets email forwarding for the requested domain
 // DomainsDNSService.SetHosts - sets DNS host records settings for the requested domain
+// DomainsDNSService.AddRecord, UpdateRecord, DeleteRecord, UpsertRecord - change a single host record keeping the others
+// DomainsDNSService.ApplyEmailPreset - sets up the email type and the host records of an email provider
 //
 // Namecheap doc: https://www.namecheap.com/support/api/methods/domains-dns/
 type DomainsDNSService service
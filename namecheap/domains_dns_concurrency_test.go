@@ -0,0 +1,200 @@
+package namecheap
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainDNSGetHostsResultFingerprint(t *testing.T) {
+	result := DomainDNSGetHostsResult{
+		EmailType:     "MX",
+		IsUsingOurDNS: true,
+		Hosts: []DomainsDNSHostRecordDetailed{
+			{HostId: 1, Name: "@", Type: "MX", Address: "mx.domain.net.", MXPref: 10, TTL: 1800},
+			{HostId: 2, Name: "www", Type: "A", Address: "192.0.2.1", MXPref: 10, TTL: 300},
+		},
+	}
+
+	t.Run("ignores_ids_and_order", func(t *testing.T) {
+		rewritten := DomainDNSGetHostsResult{
+			EmailType:     "MX",
+			IsUsingOurDNS: true,
+			Hosts: []DomainsDNSHostRecordDetailed{
+				{HostId: 8, Name: "www", Type: "A", Address: "192.0.2.1", MXPref: 20, TTL: 300},
+				{HostId: 7, Name: "@", Type: "MX", Address: "mx.domain.net.", MXPref: 10, TTL: 1800},
+			},
+		}
+
+		assert.Equal(t, result.Fingerprint(), rewritten.Fingerprint())
+	})
+
+	t.Run("changes_with_content", func(t *testing.T) {
+		changed := result
+		changed.Hosts = []DomainsDNSHostRecordDetailed{result.Hosts[0], {HostId: 2, Name: "www", Type: "A", Address: "192.0.2.1", TTL: 60}}
+		assert.NotEqual(t, result.Fingerprint(), changed.Fingerprint())
+
+		changed = result
+		changed.EmailType = "FWD"
+		assert.NotEqual(t, result.Fingerprint(), changed.Fingerprint())
+
+		changed = result
+		changed.Hosts = result.Hosts[:1]
+		assert.NotEqual(t, result.Fingerprint(), changed.Fingerprint())
+	})
+}
+
+func TestDomainsDNSRecordsOptimisticConcurrency(t *testing.T) {
+	changedGetHostsResponse := strings.Replace(fakeRecordsGetHostsResponse, "192.0.2.1", "192.0.2.9", 1)
+
+	newServer := func(responses []string, getHostsCalls *int, sentSetHosts *url.Values) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			body, _ := ioutil.ReadAll(request.Body)
+			query, _ := url.ParseQuery(string(body))
+
+			switch query.Get("Command") {
+			case "namecheap.domains.dns.getHosts":
+				_, _ = writer.Write([]byte(responses[*getHostsCalls]))
+				*getHostsCalls++
+			case "namecheap.domains.dns.setHosts":
+				*sentSetHosts = query
+				_, _ = writer.Write([]byte(fakeRecordsSetHostsResponse))
+			}
+		}))
+	}
+
+	t.Run("conflict", func(t *testing.T) {
+		var getHostsCalls int
+		sentSetHosts := url.Values{}
+		mockServer := newServer([]string{fakeRecordsGetHostsResponse, changedGetHostsResponse}, &getHostsCalls, &sentSetHosts)
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+		client.SetOptimisticConcurrency(true)
+
+		_, err := client.DomainsDNS.UpsertRecord(context.TODO(), "domain.net", DomainsDNSHostRecord{HostName: "www", RecordType: RecordTypeA, Address: "192.0.2.2"})
+
+		var conflictErr *HostsConflictError
+		if assert.True(t, errors.As(err, &conflictErr)) {
+			assert.Equal(t, "domain.net", conflictErr.Domain)
+			assert.NotEqual(t, conflictErr.ReadFingerprint, conflictErr.CurrentFingerprint)
+		}
+		assert.Equal(t, 2, getHostsCalls)
+		assert.Empty(t, sentSetHosts)
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		var getHostsCalls int
+		sentSetHosts := url.Values{}
+		mockServer := newServer([]string{fakeRecordsGetHostsResponse, fakeRecordsGetHostsResponse}, &getHostsCalls, &sentSetHosts)
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+		client.SetOptimisticConcurrency(true)
+
+		_, err := client.DomainsDNS.UpsertRecord(context.TODO(), "domain.net", DomainsDNSHostRecord{HostName: "www", RecordType: RecordTypeA, Address: "192.0.2.2"})
+		if err != nil {
+			t.Fatal("Unable to upsert record", err)
+		}
+
+		assert.Equal(t, 2, getHostsCalls)
+		assert.Equal(t, "192.0.2.2", sentSetHosts.Get("Address4"))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		var getHostsCalls int
+		sentSetHosts := url.Values{}
+		mockServer := newServer([]string{fakeRecordsGetHostsResponse, changedGetHostsResponse}, &getHostsCalls, &sentSetHosts)
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		_, err := client.DomainsDNS.UpsertRecord(context.TODO(), "domain.net", DomainsDNSHostRecord{HostName: "www", RecordType: RecordTypeA, Address: "192.0.2.2"})
+		if err != nil {
+			t.Fatal("Unable to upsert record", err)
+		}
+
+		assert.Equal(t, 1, getHostsCalls)
+		assert.Equal(t, "192.0.2.2", sentSetHosts.Get("Address4"))
+	})
+}
+
+func TestDomainsDNSRecordsSerialization(t *testing.T) {
+	t.Run("same_domain", func(t *testing.T) {
+		var m sync.Mutex
+		var active, maxActive int
+
+		mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			body, _ := ioutil.ReadAll(request.Body)
+			query, _ := url.ParseQuery(string(body))
+
+			m.Lock()
+			switch query.Get("Command") {
+			case "namecheap.domains.dns.getHosts":
+				active++
+				if active > maxActive {
+					maxActive = active
+				}
+			case "namecheap.domains.dns.setHosts":
+				active--
+			}
+			m.Unlock()
+
+			// give the other modifications a chance to interleave
+			time.Sleep(5 * time.Millisecond)
+
+			if query.Get("Command") == "namecheap.domains.dns.getHosts" {
+				_, _ = writer.Write([]byte(fakeRecordsGetHostsResponse))
+			} else {
+				_, _ = writer.Write([]byte(fakeRecordsSetHostsResponse))
+			}
+		}))
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := client.DomainsDNS.UpsertRecord(context.TODO(), "Domain.net", DomainsDNSHostRecord{HostName: "api", RecordType: RecordTypeA, Address: "192.0.2.2"})
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 1, maxActive)
+		assert.Empty(t, client.hostsGuard.locks)
+	})
+
+	t.Run("waiting_honors_context", func(t *testing.T) {
+		client := setupClient(nil)
+
+		unlock, err := client.hostsGuard.lock(context.TODO(), "domain.net")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err = client.DomainsDNS.DeleteRecord(ctx, "domain.net", DomainsDNSRecordMatch{HostName: "www", RecordType: RecordTypeA})
+
+		assert.Equal(t, context.DeadlineExceeded, err)
+	})
+}
@@ -0,0 +1,117 @@
+package namecheap
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type DomainsDNSGetEmailForwardingResponse struct {
+	XMLName xml.Name `xml:"ApiResponse"`
+	Errors  []struct {
+		Message string `xml:",chardata"`
+		Number  string `xml:"Number,attr"`
+	} `xml:"Errors>Error"`
+	CommandResponse DomainsDNSGetEmailForwardingCommandResponse `xml:"CommandResponse"`
+}
+
+type DomainsDNSGetEmailForwardingCommandResponse struct {
+	DomainDNSGetEmailForwardingResult DomainDNSGetEmailForwardingResult `xml:"DomainDNSGetEmailForwardingResult"`
+}
+
+type DomainDNSGetEmailForwardingResult struct {
+	Domain   string                   `xml:"Domain,attr"`
+	Forwards []DomainsDNSEmailForward `xml:"Forward"`
+}
+
+// DomainsDNSEmailForward forwards the emails of a mailbox of the domain to another address
+type DomainsDNSEmailForward struct {
+	// Mailbox name, e.g. info for info@domain.net
+	MailBox string `xml:"mailbox,attr"`
+	// Email address to forward to
+	ForwardTo string `xml:",chardata"`
+}
+
+func (f DomainsDNSEmailForward) String() string {
+	return fmt.Sprintf("{MailBox: %s, ForwardTo: %s}", f.MailBox, f.ForwardTo)
+}
+
+type DomainsDNSSetEmailForwardingResponse struct {
+	XMLName xml.Name `xml:"ApiResponse"`
+	Errors  []struct {
+		Message string `xml:",chardata"`
+		Number  string `xml:"Number,attr"`
+	} `xml:"Errors>Error"`
+	CommandResponse DomainsDNSSetEmailForwardingCommandResponse `xml:"CommandResponse"`
+}
+
+type DomainsDNSSetEmailForwardingCommandResponse struct {
+	DomainDNSSetEmailForwardingResult DomainDNSSetEmailForwardingResult `xml:"DomainDNSSetEmailForwardingResult"`
+}
+
+type DomainDNSSetEmailForwardingResult struct {
+	Domain    string `xml:"Domain,attr"`
+	IsSuccess bool   `xml:"IsSuccess,attr"`
+}
+
+// GetEmailForwarding gets email forwarding settings for the requested domain
+//
+// Namecheap doc: https://www.namecheap.com/support/api/methods/domains-dns/get-email-forwarding/
+func (dds *DomainsDNSService) GetEmailForwarding(ctx context.Context, domain string) (*DomainsDNSGetEmailForwardingCommandResponse, error) {
+	var response DomainsDNSGetEmailForwardingResponse
+
+	params := map[string]string{
+		"Command":    "namecheap.domains.dns.getEmailForwarding",
+		"DomainName": domain,
+	}
+
+	_, err := dds.client.DoXML(ctx, params, &response)
+	if err != nil {
+		return nil, err
+	}
+	if response.Errors != nil && len(response.Errors) > 0 {
+		apiErr := response.Errors[0]
+		return nil, fmt.Errorf("%s (%s)", apiErr.Message, apiErr.Number)
+	}
+
+	return &response.CommandResponse, nil
+}
+
+// SetEmailForwarding replaces the email forwarding settings of the requested domain.
+// The forwarding only works with the FWD EmailType, see SetHosts.
+//
+// Namecheap doc: https://www.namecheap.com/support/api/methods/domains-dns/set-email-forwarding/
+func (dds *DomainsDNSService) SetEmailForwarding(ctx context.Context, domain string, forwards []DomainsDNSEmailForward) (*DomainsDNSSetEmailForwardingCommandResponse, error) {
+	var response DomainsDNSSetEmailForwardingResponse
+
+	params := map[string]string{
+		"Command":    "namecheap.domains.dns.setEmailForwarding",
+		"DomainName": domain,
+	}
+
+	for i, forward := range forwards {
+		if forward.MailBox == "" {
+			return nil, fmt.Errorf("forwards[%d].MailBox is required", i)
+		}
+		if !strings.Contains(forward.ForwardTo, "@") {
+			return nil, fmt.Errorf("invalid forwards[%d].ForwardTo value: %s", i, forward.ForwardTo)
+		}
+
+		index := strconv.Itoa(i + 1)
+		params["MailBox"+index] = forward.MailBox
+		params["ForwardTo"+index] = forward.ForwardTo
+	}
+
+	_, err := dds.client.DoXML(ctx, params, &response)
+	if err != nil {
+		return nil, err
+	}
+	if response.Errors != nil && len(response.Errors) > 0 {
+		apiErr := response.Errors[0]
+		return nil, fmt.Errorf("%s (%s)", apiErr.Message, apiErr.Number)
+	}
+
+	return &response.CommandResponse, nil
+}
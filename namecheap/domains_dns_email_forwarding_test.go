@@ -0,0 +1,106 @@
+package namecheap
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainsDNSGetEmailForwarding(t *testing.T) {
+	fakeResponse := `
+		<?xml version="1.0" encoding="utf-8"?>
+		<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+			<Errors />
+			<CommandResponse Type="namecheap.domains.dns.getEmailForwarding">
+				<DomainDNSGetEmailForwardingResult Domain="domain.net">
+					<Forward mailbox="info">info@example.com</Forward>
+					<Forward mailbox="sales">sales@example.com</Forward>
+				</DomainDNSGetEmailForwardingResult>
+			</CommandResponse>
+		</ApiResponse>
+	`
+
+	t.Run("request_and_parsing", func(t *testing.T) {
+		var sentBody url.Values
+
+		mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			body, _ := ioutil.ReadAll(request.Body)
+			query, _ := url.ParseQuery(string(body))
+			sentBody = query
+			_, _ = writer.Write([]byte(fakeResponse))
+		}))
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		response, err := client.DomainsDNS.GetEmailForwarding(context.TODO(), "domain.net")
+		if err != nil {
+			t.Fatal("Unable to get email forwarding", err)
+		}
+
+		assert.Equal(t, "namecheap.domains.dns.getEmailForwarding", sentBody.Get("Command"))
+		assert.Equal(t, "domain.net", sentBody.Get("DomainName"))
+		assert.Equal(t, "domain.net", response.DomainDNSGetEmailForwardingResult.Domain)
+		assert.Equal(t, []DomainsDNSEmailForward{
+			{MailBox: "info", ForwardTo: "info@example.com"},
+			{MailBox: "sales", ForwardTo: "sales@example.com"},
+		}, response.DomainDNSGetEmailForwardingResult.Forwards)
+	})
+}
+
+func TestDomainsDNSSetEmailForwarding(t *testing.T) {
+	fakeResponse := `
+		<?xml version="1.0" encoding="utf-8"?>
+		<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+			<Errors />
+			<CommandResponse Type="namecheap.domains.dns.setEmailForwarding">
+				<DomainDNSSetEmailForwardingResult Domain="domain.net" IsSuccess="true" />
+			</CommandResponse>
+		</ApiResponse>
+	`
+
+	t.Run("request_data", func(t *testing.T) {
+		var sentBody url.Values
+
+		mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			body, _ := ioutil.ReadAll(request.Body)
+			query, _ := url.ParseQuery(string(body))
+			sentBody = query
+			_, _ = writer.Write([]byte(fakeResponse))
+		}))
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		response, err := client.DomainsDNS.SetEmailForwarding(context.TODO(), "domain.net", []DomainsDNSEmailForward{
+			{MailBox: "info", ForwardTo: "info@example.com"},
+			{MailBox: "sales", ForwardTo: "sales@example.com"},
+		})
+		if err != nil {
+			t.Fatal("Unable to set email forwarding", err)
+		}
+
+		assert.Equal(t, "namecheap.domains.dns.setEmailForwarding", sentBody.Get("Command"))
+		assert.Equal(t, "domain.net", sentBody.Get("DomainName"))
+		assert.Equal(t, "info", sentBody.Get("MailBox1"))
+		assert.Equal(t, "info@example.com", sentBody.Get("ForwardTo1"))
+		assert.Equal(t, "sales", sentBody.Get("MailBox2"))
+		assert.Equal(t, "sales@example.com", sentBody.Get("ForwardTo2"))
+		assert.True(t, response.DomainDNSSetEmailForwardingResult.IsSuccess)
+	})
+
+	t.Run("invalid_forward", func(t *testing.T) {
+		client := setupClient(nil)
+
+		_, err := client.DomainsDNS.SetEmailForwarding(context.TODO(), "domain.net", []DomainsDNSEmailForward{{MailBox: "info", ForwardTo: "example.com"}})
+
+		assert.EqualError(t, err, "invalid forwards[0].ForwardTo value: example.com")
+	})
+}
@@ -0,0 +1,291 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	PlanActionAdd    = "ADD"
+	PlanActionChange = "CHANGE"
+	PlanActionRemove = "REMOVE"
+)
+
+// defaultHostRecordTTL is the TTL Namecheap sets when a record is written without one
+const defaultHostRecordTTL = 1800
+
+// DomainsDNSPlanArgs struct is an input arguments for DomainsDNSService.Plan and DomainsDNSService.Sync functions
+type DomainsDNSPlanArgs struct {
+	// Domain to plan the host records for
+	Domain string
+	// Desired host records. A zero TTL means the Namecheap default of 1800.
+	Records []DomainsDNSHostRecord
+	// Desired email type, e.g. MX. If empty, then the current one is kept
+	EmailType string
+	// Keep the records of the host name and type pairs absent from Records.
+	// By default the Records are the full desired state and all the other records are removed.
+	IgnoreUnmanaged bool
+	// Compute the plan without applying it, used by DomainsDNSService.Sync only
+	DryRun bool
+}
+
+// DomainsDNSPlanChange is a single change of the plan, Before is nil for the added records and After for the removed ones
+type DomainsDNSPlanChange struct {
+	// One of PlanActionAdd, PlanActionChange, PlanActionRemove
+	Action string
+	Before *DomainsDNSHostRecord
+	After  *DomainsDNSHostRecord
+}
+
+func (c DomainsDNSPlanChange) String() string {
+	switch c.Action {
+	case PlanActionAdd:
+		return "+ " + describeHostRecord(*c.After)
+	case PlanActionRemove:
+		return "- " + describeHostRecord(*c.Before)
+	}
+	return fmt.Sprintf("~ %s -> %s", describeHostRecord(*c.Before), describeHostRecordValue(*c.After))
+}
+
+// DomainsDNSPlan is the difference between the current host records of a domain and the desired ones
+type DomainsDNSPlan struct {
+	Domain  string
+	Changes []DomainsDNSPlanChange
+	// Current and planned email type
+	EmailTypeBefore string
+	EmailTypeAfter  string
+	// Records kept because of DomainsDNSPlanArgs.IgnoreUnmanaged
+	Unmanaged []DomainsDNSHostRecord
+	// Full list of the host records to be written
+	Records []DomainsDNSHostRecord
+
+	// fingerprint of the host records the plan was computed against
+	fingerprint string
+}
+
+// HasChanges reports whether applying the plan changes anything
+func (p *DomainsDNSPlan) HasChanges() bool {
+	return len(p.Changes) > 0 || !strings.EqualFold(p.EmailTypeBefore, p.EmailTypeAfter)
+}
+
+// Count returns the number of the changes of the action, e.g. PlanActionAdd
+func (p *DomainsDNSPlan) Count(action string) int {
+	count := 0
+	for _, change := range p.Changes {
+		if change.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// String renders the plan human-readably, one change per line:
+//
+//	domain.net: 1 to add, 1 to change, 1 to remove
+//	+ api CNAME www.domain.net. ttl=1800
+//	~ www A 192.0.2.1 ttl=300 -> 192.0.2.2 ttl=300
+//	- ftp A 192.0.2.3 ttl=1800
+func (p *DomainsDNSPlan) String() string {
+	if !p.HasChanges() {
+		return fmt.Sprintf("%s: no changes", p.Domain)
+	}
+
+	lines := []string{fmt.Sprintf("%s: %d to add, %d to change, %d to remove",
+		p.Domain, p.Count(PlanActionAdd), p.Count(PlanActionChange), p.Count(PlanActionRemove))}
+	for _, change := range p.Changes {
+		lines = append(lines, change.String())
+	}
+	if !strings.EqualFold(p.EmailTypeBefore, p.EmailTypeAfter) {
+		lines = append(lines, fmt.Sprintf("~ email type %s -> %s", p.EmailTypeBefore, p.EmailTypeAfter))
+	}
+	if len(p.Unmanaged) > 0 {
+		lines = append(lines, fmt.Sprintf("(%d unmanaged records kept)", len(p.Unmanaged)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Plan computes the changes turning the current host records of the domain into the desired ones.
+// The plan is validated the same way SetHosts validates its args, nothing is written.
+func (dds DomainsDNSService) Plan(ctx context.Context, args *DomainsDNSPlanArgs) (*DomainsDNSPlan, error) {
+	if args == nil {
+		return nil, fmt.Errorf("args is required")
+	}
+
+	hosts, err := dds.GetHosts(ctx, args.Domain)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the host records of %s: %v", args.Domain, err)
+	}
+
+	result := hosts.DomainDNSGetHostsResult
+	if !result.IsUsingOurDNS {
+		return nil, fmt.Errorf("%s isn't using the Namecheap DNS, its host records can't be managed", args.Domain)
+	}
+
+	plan := planHosts(result, args)
+
+	err = validateDomainsDNSSetHostsArgs(&DomainsDNSSetHostsArgs{
+		Domain:    plan.Domain,
+		Records:   plan.Records,
+		EmailType: plan.EmailTypeAfter,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid plan for %s: %v", plan.Domain, err)
+	}
+
+	return plan, nil
+}
+
+// ApplyPlan writes the planned host records with a single SetHosts call.
+// It fails with HostsConflictError if the host records were changed since the plan was computed,
+// and doesn't write anything if the plan has no changes.
+func (dds DomainsDNSService) ApplyPlan(ctx context.Context, plan *DomainsDNSPlan) (*DomainsDNSSetHostsCommandResponse, error) {
+	if !plan.HasChanges() {
+		return nil, nil
+	}
+
+	return dds.updateHosts(ctx, plan.Domain, func(result DomainDNSGetHostsResult) (*DomainsDNSSetHostsArgs, error) {
+		if fingerprint := result.Fingerprint(); fingerprint != plan.fingerprint {
+			return nil, &HostsConflictError{Domain: plan.Domain, ReadFingerprint: plan.fingerprint, CurrentFingerprint: fingerprint}
+		}
+		return &DomainsDNSSetHostsArgs{
+			Domain:    plan.Domain,
+			Records:   plan.Records,
+			EmailType: plan.EmailTypeAfter,
+		}, nil
+	})
+}
+
+// Sync plans the desired host records and applies the plan unless DomainsDNSPlanArgs.DryRun is set.
+// The plan is returned either way, e.g. to be printed.
+func (dds DomainsDNSService) Sync(ctx context.Context, args *DomainsDNSPlanArgs) (*DomainsDNSPlan, error) {
+	plan, err := dds.Plan(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if args.DryRun {
+		return plan, nil
+	}
+
+	if _, err := dds.ApplyPlan(ctx, plan); err != nil {
+		return plan, err
+	}
+	return plan, nil
+}
+
+// planHosts diffs the current host records with the desired ones. The records are identified by their
+// DomainsDNSRecordKey, a different TTL or MX preference is a change. The leftover records of the same host name and
+// type are paired up as changes of the address, the rest are added or removed.
+// The kept records stay in their current order, the added ones are appended.
+func planHosts(result DomainDNSGetHostsResult, args *DomainsDNSPlanArgs) *DomainsDNSPlan {
+	plan := &DomainsDNSPlan{
+		Domain:          args.Domain,
+		EmailTypeBefore: result.EmailType,
+		EmailTypeAfter:  result.EmailType,
+		fingerprint:     result.Fingerprint(),
+	}
+	if args.EmailType != "" {
+		plan.EmailTypeAfter = args.EmailType
+	}
+
+	current := HostRecordsFromDetailed(result.Hosts)
+
+	desired := make([]DomainsDNSHostRecord, len(args.Records))
+	managed := map[string]bool{}
+	for i, record := range args.Records {
+		desired[i] = record.Normalize()
+		managed[hostRecordGroup(record)] = true
+	}
+
+	slots := make([]*DomainsDNSHostRecord, len(current))
+	desiredMatched := make([]bool, len(desired))
+
+	// the same records, possibly with another TTL or MX preference
+	for i := range current {
+		for j := range desired {
+			if desiredMatched[j] || current[i].Key() != desired[j].Key() {
+				continue
+			}
+			desiredMatched[j] = true
+			slots[i] = &desired[j]
+			if !current[i].Equal(desired[j]) {
+				plan.Changes = append(plan.Changes, DomainsDNSPlanChange{Action: PlanActionChange, Before: &current[i], After: &desired[j]})
+			}
+			break
+		}
+	}
+
+	// another address of the same host name and type, or a removal
+	for i := range current {
+		if slots[i] != nil {
+			continue
+		}
+
+		group := hostRecordGroup(current[i])
+		if args.IgnoreUnmanaged && !managed[group] {
+			slots[i] = &current[i]
+			plan.Unmanaged = append(plan.Unmanaged, current[i])
+			continue
+		}
+
+		for j := range desired {
+			if desiredMatched[j] || hostRecordGroup(desired[j]) != group {
+				continue
+			}
+			desiredMatched[j] = true
+			slots[i] = &desired[j]
+			plan.Changes = append(plan.Changes, DomainsDNSPlanChange{Action: PlanActionChange, Before: &current[i], After: &desired[j]})
+			break
+		}
+
+		if slots[i] == nil {
+			plan.Changes = append(plan.Changes, DomainsDNSPlanChange{Action: PlanActionRemove, Before: &current[i]})
+		}
+	}
+
+	plan.Records = []DomainsDNSHostRecord{}
+	for _, record := range slots {
+		if record != nil {
+			plan.Records = append(plan.Records, *record)
+		}
+	}
+	for j := range desired {
+		if !desiredMatched[j] {
+			plan.Records = append(plan.Records, desired[j])
+			plan.Changes = append(plan.Changes, DomainsDNSPlanChange{Action: PlanActionAdd, After: &desired[j]})
+		}
+	}
+
+	sort.SliceStable(plan.Changes, func(i, j int) bool {
+		return hostRecordGroup(planChangeRecord(plan.Changes[i])) < hostRecordGroup(planChangeRecord(plan.Changes[j]))
+	})
+
+	return plan
+}
+
+func planChangeRecord(change DomainsDNSPlanChange) DomainsDNSHostRecord {
+	if change.Before != nil {
+		return *change.Before
+	}
+	return *change.After
+}
+
+// hostRecordGroup returns the normalized host name and type of the record
+func hostRecordGroup(record DomainsDNSHostRecord) string {
+	key := record.Key()
+	return key.HostName + " " + key.RecordType
+}
+
+func describeHostRecord(record DomainsDNSHostRecord) string {
+	return fmt.Sprintf("%s %s %s", record.HostName, record.RecordType, describeHostRecordValue(record))
+}
+
+func describeHostRecordValue(record DomainsDNSHostRecord) string {
+	value := record.Address
+	if record.MXPref != nil {
+		value += fmt.Sprintf(" pref=%d", *record.MXPref)
+	}
+	return value + fmt.Sprintf(" ttl=%d", record.TTL)
+}
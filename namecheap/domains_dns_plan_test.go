@@ -0,0 +1,194 @@
+package namecheap
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainsDNSPlan(t *testing.T) {
+	setupPlan := func(getHostsResponse *string) (*Client, *url.Values, func()) {
+		sentSetHosts := url.Values{}
+		mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			body, _ := ioutil.ReadAll(request.Body)
+			query, _ := url.ParseQuery(string(body))
+
+			switch query.Get("Command") {
+			case "namecheap.domains.dns.getHosts":
+				_, _ = writer.Write([]byte(*getHostsResponse))
+			case "namecheap.domains.dns.setHosts":
+				sentSetHosts = query
+				_, _ = writer.Write([]byte(fakeRecordsSetHostsResponse))
+			}
+		}))
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		return client, &sentSetHosts, mockServer.Close
+	}
+
+	desiredRecords := func() []DomainsDNSHostRecord {
+		return []DomainsDNSHostRecord{
+			{HostName: "@", RecordType: RecordTypeMX, Address: "mx1.domain.net", MXPref: UInt8(5)},
+			{HostName: "@", RecordType: RecordTypeMX, Address: "mx2.domain.net.", MXPref: UInt8(30)},
+			{HostName: "www", RecordType: RecordTypeA, Address: "192.0.2.2", TTL: 300},
+			{HostName: "api", RecordType: RecordTypeCNAME, Address: "www.domain.net."},
+		}
+	}
+
+	t.Run("full_ownership", func(t *testing.T) {
+		getHostsResponse := fakeRecordsGetHostsResponse
+		client, _, closeServer := setupPlan(&getHostsResponse)
+		defer closeServer()
+
+		plan, err := client.DomainsDNS.Plan(context.TODO(), &DomainsDNSPlanArgs{Domain: "domain.net", Records: desiredRecords()})
+		if err != nil {
+			t.Fatal("Unable to plan", err)
+		}
+
+		assert.True(t, plan.HasChanges())
+		assert.Equal(t, 1, plan.Count(PlanActionAdd))
+		assert.Equal(t, 2, plan.Count(PlanActionChange))
+		assert.Equal(t, 1, plan.Count(PlanActionRemove))
+		assert.Equal(t, strings.Join([]string{
+			"domain.net: 1 to add, 2 to change, 1 to remove",
+			"- @ CAA 0 issue \"letsencrypt.org\" ttl=3600",
+			"~ @ MX mx2.domain.net. pref=20 ttl=1800 -> mx2.domain.net. pref=30 ttl=1800",
+			"+ api CNAME www.domain.net. ttl=1800",
+			"~ www A 192.0.2.1 ttl=300 -> 192.0.2.2 ttl=300",
+		}, "\n"), plan.String())
+	})
+
+	t.Run("same_records", func(t *testing.T) {
+		getHostsResponse := fakeRecordsGetHostsResponse
+		client, sent, closeServer := setupPlan(&getHostsResponse)
+		defer closeServer()
+
+		plan, err := client.DomainsDNS.Plan(context.TODO(), &DomainsDNSPlanArgs{
+			Domain: "domain.net",
+			Records: []DomainsDNSHostRecord{
+				{HostName: "www", RecordType: RecordTypeA, Address: "192.0.2.1", TTL: 300},
+				{HostName: "@", RecordType: RecordTypeMX, Address: "MX1.domain.net.", MXPref: UInt8(5)},
+				{HostName: "@", RecordType: RecordTypeCAA, Address: `0 issue "letsencrypt.org"`, TTL: 3600},
+				{HostName: "@", RecordType: RecordTypeMX, Address: "mx2.domain.net.", MXPref: UInt8(20)},
+			},
+		})
+		if err != nil {
+			t.Fatal("Unable to plan", err)
+		}
+
+		assert.False(t, plan.HasChanges())
+		assert.Equal(t, "domain.net: no changes", plan.String())
+
+		response, err := client.DomainsDNS.ApplyPlan(context.TODO(), plan)
+		assert.NoError(t, err)
+		assert.Nil(t, response)
+		assert.Empty(t, *sent)
+	})
+
+	t.Run("ignore_unmanaged", func(t *testing.T) {
+		getHostsResponse := fakeRecordsGetHostsResponse
+		client, _, closeServer := setupPlan(&getHostsResponse)
+		defer closeServer()
+
+		plan, err := client.DomainsDNS.Plan(context.TODO(), &DomainsDNSPlanArgs{
+			Domain:          "domain.net",
+			Records:         []DomainsDNSHostRecord{{HostName: "www", RecordType: RecordTypeA, Address: "192.0.2.2", TTL: 300}},
+			IgnoreUnmanaged: true,
+		})
+		if err != nil {
+			t.Fatal("Unable to plan", err)
+		}
+
+		assert.Equal(t, "domain.net: 0 to add, 1 to change, 0 to remove\n~ www A 192.0.2.1 ttl=300 -> 192.0.2.2 ttl=300\n(3 unmanaged records kept)", plan.String())
+		assert.Len(t, plan.Records, 4)
+		assert.Equal(t, "192.0.2.2", plan.Records[3].Address)
+	})
+
+	t.Run("email_type", func(t *testing.T) {
+		getHostsResponse := fakeRecordsGetHostsResponse
+		client, _, closeServer := setupPlan(&getHostsResponse)
+		defer closeServer()
+
+		plan, err := client.DomainsDNS.Plan(context.TODO(), &DomainsDNSPlanArgs{
+			Domain:    "domain.net",
+			Records:   []DomainsDNSHostRecord{{HostName: "www", RecordType: RecordTypeA, Address: "192.0.2.1", TTL: 300}},
+			EmailType: EmailTypeForward,
+		})
+		if err != nil {
+			t.Fatal("Unable to plan", err)
+		}
+
+		assert.Equal(t, 3, plan.Count(PlanActionRemove))
+		assert.Contains(t, plan.String(), "~ email type MX -> FWD")
+	})
+
+	t.Run("invalid_plan", func(t *testing.T) {
+		getHostsResponse := fakeRecordsGetHostsResponse
+		client, _, closeServer := setupPlan(&getHostsResponse)
+		defer closeServer()
+
+		_, err := client.DomainsDNS.Plan(context.TODO(), &DomainsDNSPlanArgs{
+			Domain:  "domain.net",
+			Records: []DomainsDNSHostRecord{{HostName: "www", RecordType: RecordTypeA, Address: "192.0.2.1"}},
+		})
+
+		assert.EqualError(t, err, "invalid plan for domain.net: minimum 1 MX record required for MX EmailType")
+	})
+
+	t.Run("sync", func(t *testing.T) {
+		getHostsResponse := fakeRecordsGetHostsResponse
+		client, sent, closeServer := setupPlan(&getHostsResponse)
+		defer closeServer()
+
+		args := &DomainsDNSPlanArgs{Domain: "domain.net", Records: desiredRecords(), DryRun: true}
+
+		plan, err := client.DomainsDNS.Sync(context.TODO(), args)
+		if err != nil {
+			t.Fatal("Unable to sync", err)
+		}
+		assert.True(t, plan.HasChanges())
+		assert.Empty(t, *sent)
+
+		args.DryRun = false
+		_, err = client.DomainsDNS.Sync(context.TODO(), args)
+		if err != nil {
+			t.Fatal("Unable to sync", err)
+		}
+
+		assert.Equal(t, "MX", sent.Get("EmailType"))
+		assert.Equal(t, "mx1.domain.net", sent.Get("Address1"))
+		assert.Equal(t, "30", sent.Get("MXPref2"))
+		assert.Equal(t, "192.0.2.2", sent.Get("Address3"))
+		assert.Equal(t, "www.domain.net.", sent.Get("Address4"))
+		assert.Equal(t, "1800", sent.Get("TTL4"))
+		assert.NotContains(t, *sent, "RecordType5")
+	})
+
+	t.Run("stale_plan", func(t *testing.T) {
+		getHostsResponse := fakeRecordsGetHostsResponse
+		client, sent, closeServer := setupPlan(&getHostsResponse)
+		defer closeServer()
+
+		plan, err := client.DomainsDNS.Plan(context.TODO(), &DomainsDNSPlanArgs{Domain: "domain.net", Records: desiredRecords()})
+		if err != nil {
+			t.Fatal("Unable to plan", err)
+		}
+
+		getHostsResponse = strings.Replace(fakeRecordsGetHostsResponse, `TTL="300"`, `TTL="600"`, 1)
+
+		_, err = client.DomainsDNS.ApplyPlan(context.TODO(), plan)
+
+		var conflictErr *HostsConflictError
+		assert.True(t, errors.As(err, &conflictErr))
+		assert.Empty(t, *sent)
+	})
+}
@@ -0,0 +1,145 @@
+package namecheap
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// defaultMXPref is the MX preference GetHosts reports for the records of the other types
+const defaultMXPref = 10
+
+// DomainsDNSRecordKey identifies a host record regardless of the formatting differences between GetHosts and SetHosts
+type DomainsDNSRecordKey struct {
+	// Lower case host name, @ for the domain itself
+	HostName string
+	// Upper case record type
+	RecordType string
+	// Normalized address: the host names are lower case without the trailing dot, the IP addresses are in
+	// the canonical form, and the CAA addresses are written as: flag tag "value"
+	Address string
+}
+
+func (k DomainsDNSRecordKey) String() string {
+	return fmt.Sprintf("%s %s %s", k.HostName, k.RecordType, k.Address)
+}
+
+// HostRecord converts the record returned by GetHosts to the one accepted by SetHosts.
+// Everything SetHosts accepts is kept: the MX preference is only kept for MX records, since GetHosts reports it
+// for every record, and the CAA flag and tag are kept as part of the address, the way GetHosts returns them.
+func (d DomainsDNSHostRecordDetailed) HostRecord() DomainsDNSHostRecord {
+	record := DomainsDNSHostRecord{
+		HostName:   d.Name,
+		RecordType: d.Type,
+		Address:    d.Address,
+		TTL:        d.TTL,
+	}
+	if strings.EqualFold(d.Type, RecordTypeMX) {
+		record.MXPref = UInt8(uint8(d.MXPref))
+	}
+	return record
+}
+
+// Key returns the normalized key of the record
+func (d DomainsDNSHostRecordDetailed) Key() DomainsDNSRecordKey {
+	return d.HostRecord().Key()
+}
+
+// HostRecordsFromDetailed converts the records returned by GetHosts to the ones accepted by SetHosts, see HostRecord
+func HostRecordsFromDetailed(hosts []DomainsDNSHostRecordDetailed) []DomainsDNSHostRecord {
+	records := make([]DomainsDNSHostRecord, 0, len(hosts))
+	for _, host := range hosts {
+		records = append(records, host.HostRecord())
+	}
+	return records
+}
+
+// Detailed converts the record to the form GetHosts returns it after it's written: the TTL defaults to 1800,
+// and the MX preference to 10. The fields set by Namecheap, e.g. HostId, are left empty.
+func (r DomainsDNSHostRecord) Detailed() DomainsDNSHostRecordDetailed {
+	normalized := r.Normalize()
+
+	detailed := DomainsDNSHostRecordDetailed{
+		Name:     normalized.HostName,
+		Type:     normalized.RecordType,
+		Address:  normalized.Address,
+		MXPref:   defaultMXPref,
+		TTL:      normalized.TTL,
+		IsActive: true,
+	}
+	if normalized.MXPref != nil {
+		detailed.MXPref = int(*normalized.MXPref)
+	}
+	return detailed
+}
+
+// Normalize returns the record with the defaults Namecheap applies on write: the TTL of 1800 for zero,
+// the upper case record type, and no MX preference for the types other than MX. The address is kept as is.
+func (r DomainsDNSHostRecord) Normalize() DomainsDNSHostRecord {
+	r.RecordType = strings.ToUpper(r.RecordType)
+	if r.TTL == 0 {
+		r.TTL = defaultHostRecordTTL
+	}
+	if r.RecordType != RecordTypeMX {
+		r.MXPref = nil
+	}
+	return r
+}
+
+// Key returns the normalized key of the record
+func (r DomainsDNSHostRecord) Key() DomainsDNSRecordKey {
+	recordType := strings.ToUpper(r.RecordType)
+	return DomainsDNSRecordKey{
+		HostName:   normalizeRecordHostName(r.HostName),
+		RecordType: recordType,
+		Address:    normalizeRecordAddress(recordType, r.Address),
+	}
+}
+
+// Equal reports whether writing either record gives the same result: the keys, the TTLs
+// and the MX preferences are compared after Normalize
+func (r DomainsDNSHostRecord) Equal(other DomainsDNSHostRecord) bool {
+	if r.Key() != other.Key() {
+		return false
+	}
+
+	a, b := r.Normalize(), other.Normalize()
+	if a.TTL != b.TTL {
+		return false
+	}
+	if (a.MXPref == nil) != (b.MXPref == nil) {
+		return false
+	}
+	return a.MXPref == nil || *a.MXPref == *b.MXPref
+}
+
+func normalizeRecordHostName(hostName string) string {
+	hostName = strings.ToLower(strings.TrimSuffix(hostName, "."))
+	if hostName == "" {
+		return "@"
+	}
+	return hostName
+}
+
+func normalizeRecordAddress(recordType string, address string) string {
+	switch recordType {
+	case RecordTypeCNAME, RecordTypeMX, RecordTypeNS, RecordTypeAlias:
+		return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(address), "."))
+	case RecordTypeA, RecordTypeAAAA, RecordTypeMXE:
+		if ip := net.ParseIP(strings.TrimSpace(address)); ip != nil {
+			return ip.String()
+		}
+	case RecordTypeCAA:
+		return normalizeCAAAddress(address)
+	}
+	return address
+}
+
+// normalizeCAAAddress rewrites the CAA address as: flag tag "value", the unparsable addresses are returned as is
+func normalizeCAAAddress(address string) string {
+	caa, err := ParseCAAAddress(address)
+	if err != nil {
+		return address
+	}
+	return caa.Address()
+}
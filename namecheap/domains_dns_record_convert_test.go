@@ -0,0 +1,107 @@
+package namecheap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainsDNSHostRecordDetailedHostRecord(t *testing.T) {
+	var cases = map[string]struct {
+		Detailed DomainsDNSHostRecordDetailed
+		Expected DomainsDNSHostRecord
+	}{
+		"a": {
+			Detailed: DomainsDNSHostRecordDetailed{HostId: 1, Name: "www", Type: "A", Address: "192.0.2.1", MXPref: 10, TTL: 300, IsActive: true},
+			Expected: DomainsDNSHostRecord{HostName: "www", RecordType: "A", Address: "192.0.2.1", TTL: 300},
+		},
+		"mx": {
+			Detailed: DomainsDNSHostRecordDetailed{HostId: 2, Name: "@", Type: "MX", Address: "mx.domain.net.", MXPref: 20, TTL: 1800, IsActive: true},
+			Expected: DomainsDNSHostRecord{HostName: "@", RecordType: "MX", Address: "mx.domain.net.", MXPref: UInt8(20), TTL: 1800},
+		},
+		"caa": {
+			Detailed: DomainsDNSHostRecordDetailed{HostId: 3, Name: "@", Type: "CAA", Address: `128 issuewild "letsencrypt.org"`, MXPref: 10, TTL: 3600, IsActive: true},
+			Expected: DomainsDNSHostRecord{HostName: "@", RecordType: "CAA", Address: `128 issuewild "letsencrypt.org"`, TTL: 3600},
+		},
+	}
+
+	for name, testCase := range cases {
+		t.Run(name, func(t *testing.T) {
+			record := testCase.Detailed.HostRecord()
+			assert.Equal(t, testCase.Expected, record)
+
+			// what's read is written back unchanged
+			detailed := record.Detailed()
+			assert.Equal(t, testCase.Detailed.Name, detailed.Name)
+			assert.Equal(t, testCase.Detailed.Type, detailed.Type)
+			assert.Equal(t, testCase.Detailed.Address, detailed.Address)
+			assert.Equal(t, testCase.Detailed.MXPref, detailed.MXPref)
+			assert.Equal(t, testCase.Detailed.TTL, detailed.TTL)
+			assert.Equal(t, testCase.Detailed.Key(), record.Key())
+		})
+	}
+}
+
+func TestDomainsDNSHostRecordDetailed(t *testing.T) {
+	detailed := DomainsDNSHostRecord{HostName: "www", RecordType: "cname", Address: "domain.net."}.Detailed()
+
+	assert.Equal(t, DomainsDNSHostRecordDetailed{Name: "www", Type: "CNAME", Address: "domain.net.", MXPref: 10, TTL: 1800, IsActive: true}, detailed)
+}
+
+func TestDomainsDNSHostRecordKey(t *testing.T) {
+	var cases = map[string]struct {
+		A, B DomainsDNSHostRecord
+	}{
+		"host_name_case": {
+			A: DomainsDNSHostRecord{HostName: "WWW", RecordType: "A", Address: "192.0.2.1"},
+			B: DomainsDNSHostRecord{HostName: "www", RecordType: "a", Address: "192.0.2.1"},
+		},
+		"empty_host_name": {
+			A: DomainsDNSHostRecord{HostName: "", RecordType: "A", Address: "192.0.2.1"},
+			B: DomainsDNSHostRecord{HostName: "@", RecordType: "A", Address: "192.0.2.1"},
+		},
+		"trailing_dot": {
+			A: DomainsDNSHostRecord{HostName: "www", RecordType: "CNAME", Address: "Domain.net"},
+			B: DomainsDNSHostRecord{HostName: "www", RecordType: "CNAME", Address: "domain.net."},
+		},
+		"ipv6_form": {
+			A: DomainsDNSHostRecord{HostName: "@", RecordType: "AAAA", Address: "2001:0db8:0000:0000:0000:0000:0000:0001"},
+			B: DomainsDNSHostRecord{HostName: "@", RecordType: "AAAA", Address: "2001:db8::1"},
+		},
+		"caa_quotes_and_tag_case": {
+			A: DomainsDNSHostRecord{HostName: "@", RecordType: "CAA", Address: "0 ISSUE letsencrypt.org"},
+			B: DomainsDNSHostRecord{HostName: "@", RecordType: "CAA", Address: `0  issue "letsencrypt.org"`},
+		},
+	}
+
+	for name, testCase := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, testCase.A.Key(), testCase.B.Key())
+			assert.True(t, testCase.A.Equal(testCase.B))
+		})
+	}
+
+	t.Run("caa_value_with_spaces", func(t *testing.T) {
+		key := DomainsDNSHostRecord{HostName: "@", RecordType: "CAA", Address: `0 iodef "mailto:security@domain.net"`}.Key()
+		assert.Equal(t, `0 iodef "mailto:security@domain.net"`, key.Address)
+	})
+
+	t.Run("txt_is_exact", func(t *testing.T) {
+		a := DomainsDNSHostRecord{HostName: "@", RecordType: "TXT", Address: "v=spf1 -all"}
+		b := DomainsDNSHostRecord{HostName: "@", RecordType: "TXT", Address: "V=spf1 -all"}
+		assert.NotEqual(t, a.Key(), b.Key())
+	})
+}
+
+func TestDomainsDNSHostRecordEqual(t *testing.T) {
+	record := DomainsDNSHostRecord{HostName: "@", RecordType: "MX", Address: "mx.domain.net.", MXPref: UInt8(10)}
+
+	assert.True(t, record.Equal(DomainsDNSHostRecord{HostName: "@", RecordType: "MX", Address: "mx.domain.net", MXPref: UInt8(10), TTL: 1800}))
+	assert.False(t, record.Equal(DomainsDNSHostRecord{HostName: "@", RecordType: "MX", Address: "mx.domain.net.", MXPref: UInt8(20)}))
+	assert.False(t, record.Equal(DomainsDNSHostRecord{HostName: "@", RecordType: "MX", Address: "mx.domain.net.", MXPref: UInt8(10), TTL: 300}))
+
+	// the MX preference of the other types is ignored by SetHosts
+	a := DomainsDNSHostRecord{HostName: "www", RecordType: "A", Address: "192.0.2.1", MXPref: UInt8(10)}
+	b := DomainsDNSHostRecord{HostName: "www", RecordType: "A", Address: "192.0.2.1"}
+	assert.True(t, a.Equal(b))
+}
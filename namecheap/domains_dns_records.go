@@ -0,0 +1,188 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+)
+
+// DomainsDNSRecordMatch selects the host records of a domain
+type DomainsDNSRecordMatch struct {
+	// Sub-domain/hostname of the records, e.g. @ or www. Required.
+	HostName string
+	// Type of the records, e.g. A or TXT. Required.
+	RecordType string
+	// Address of the records, empty matches any address
+	Address string
+}
+
+func (m DomainsDNSRecordMatch) matches(record DomainsDNSHostRecord) bool {
+	key := record.Key()
+	matchKey := DomainsDNSHostRecord{HostName: m.HostName, RecordType: m.RecordType, Address: m.Address}.Key()
+	return key.HostName == matchKey.HostName &&
+		key.RecordType == matchKey.RecordType &&
+		(m.Address == "" || key.Address == matchKey.Address)
+}
+
+func (m DomainsDNSRecordMatch) String() string {
+	if m.Address == "" {
+		return fmt.Sprintf("%s %s", m.HostName, m.RecordType)
+	}
+	return fmt.Sprintf("%s %s %s", m.HostName, m.RecordType, m.Address)
+}
+
+func (m DomainsDNSRecordMatch) validate() error {
+	if m.HostName == "" {
+		return fmt.Errorf("HostName is required")
+	}
+	if m.RecordType == "" {
+		return fmt.Errorf("RecordType is required")
+	}
+	return nil
+}
+
+// AddRecord adds a host record to the domain keeping the existing ones.
+// It fails if the same record (host name, type and address) exists already.
+// See modifyHosts for what's preserved of the existing records.
+func (dds DomainsDNSService) AddRecord(ctx context.Context, domain string, record DomainsDNSHostRecord) (*DomainsDNSSetHostsCommandResponse, error) {
+	return dds.modifyHosts(ctx, domain, func(records []DomainsDNSHostRecord) ([]DomainsDNSHostRecord, error) {
+		match := DomainsDNSRecordMatch{HostName: record.HostName, RecordType: record.RecordType, Address: record.Address}
+		if len(findRecords(records, match)) > 0 {
+			return nil, fmt.Errorf("record %s already exists", match)
+		}
+		return append(records, record), nil
+	})
+}
+
+// UpdateRecord replaces the host record selected by the match with the record.
+// Exactly one record must match, set DomainsDNSRecordMatch.Address to tell apart the records of the same host and type.
+func (dds DomainsDNSService) UpdateRecord(ctx context.Context, domain string, match DomainsDNSRecordMatch, record DomainsDNSHostRecord) (*DomainsDNSSetHostsCommandResponse, error) {
+	if err := match.validate(); err != nil {
+		return nil, err
+	}
+
+	return dds.modifyHosts(ctx, domain, func(records []DomainsDNSHostRecord) ([]DomainsDNSHostRecord, error) {
+		indexes := findRecords(records, match)
+		switch len(indexes) {
+		case 0:
+			return nil, fmt.Errorf("record %s not found", match)
+		case 1:
+			records[indexes[0]] = record
+			return records, nil
+		}
+		return nil, fmt.Errorf("%d records match %s, the address is required to select one", len(indexes), match)
+	})
+}
+
+// DeleteRecord removes the host records selected by the match, all the addresses of the host and type
+// are removed when DomainsDNSRecordMatch.Address is empty. It fails if no record matches.
+func (dds DomainsDNSService) DeleteRecord(ctx context.Context, domain string, match DomainsDNSRecordMatch) (*DomainsDNSSetHostsCommandResponse, error) {
+	if err := match.validate(); err != nil {
+		return nil, err
+	}
+
+	return dds.modifyHosts(ctx, domain, func(records []DomainsDNSHostRecord) ([]DomainsDNSHostRecord, error) {
+		kept := make([]DomainsDNSHostRecord, 0, len(records))
+		for _, record := range records {
+			if !match.matches(record) {
+				kept = append(kept, record)
+			}
+		}
+		if len(kept) == len(records) {
+			return nil, fmt.Errorf("record %s not found", match)
+		}
+		return kept, nil
+	})
+}
+
+// UpsertRecord replaces the host record of the same host name and type, or adds it if there's none.
+// It fails if the host has several records of the type, use UpdateRecord with an address instead.
+func (dds DomainsDNSService) UpsertRecord(ctx context.Context, domain string, record DomainsDNSHostRecord) (*DomainsDNSSetHostsCommandResponse, error) {
+	return dds.modifyHosts(ctx, domain, func(records []DomainsDNSHostRecord) ([]DomainsDNSHostRecord, error) {
+		match := DomainsDNSRecordMatch{HostName: record.HostName, RecordType: record.RecordType}
+		indexes := findRecords(records, match)
+		switch len(indexes) {
+		case 0:
+			return append(records, record), nil
+		case 1:
+			records[indexes[0]] = record
+			return records, nil
+		}
+		return nil, fmt.Errorf("%d records match %s, use UpdateRecord to select one", len(indexes), match)
+	})
+}
+
+// modifyHosts reads the host records of the domain, applies the modification and writes the full list back.
+// The email type, the MX preferences and the TTLs of the existing records are preserved, and the CAA records
+// are written back with their flag and tag as part of the address, the way GetHosts returns them.
+// Nothing is written if reading or the modification fails, or on a conflict in the optimistic concurrency mode.
+func (dds DomainsDNSService) modifyHosts(ctx context.Context, domain string, modify func(records []DomainsDNSHostRecord) ([]DomainsDNSHostRecord, error)) (*DomainsDNSSetHostsCommandResponse, error) {
+	return dds.updateHosts(ctx, domain, func(result DomainDNSGetHostsResult) (*DomainsDNSSetHostsArgs, error) {
+		records, err := modify(HostRecordsFromDetailed(result.Hosts))
+		if err != nil {
+			return nil, err
+		}
+		return &DomainsDNSSetHostsArgs{
+			Domain:    domain,
+			Records:   records,
+			EmailType: result.EmailType,
+		}, nil
+	})
+}
+
+// updateHosts runs the read-modify-write of the host records of the domain, serialized with the other
+// modifications of the domain made through the Client. The update func builds the SetHosts args from the current
+// records, nil args means there's nothing to write. In the optimistic concurrency mode the records are read again
+// and compared right before the write.
+func (dds DomainsDNSService) updateHosts(ctx context.Context, domain string, update func(result DomainDNSGetHostsResult) (*DomainsDNSSetHostsArgs, error)) (*DomainsDNSSetHostsCommandResponse, error) {
+	unlock, err := dds.client.hostsGuard.lock(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	hosts, err := dds.GetHosts(ctx, domain)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the host records of %s: %v", domain, err)
+	}
+
+	result := hosts.DomainDNSGetHostsResult
+	if !result.IsUsingOurDNS {
+		return nil, fmt.Errorf("%s isn't using the Namecheap DNS, its host records can't be managed", domain)
+	}
+
+	args, err := update(result)
+	if err != nil || args == nil {
+		return nil, err
+	}
+
+	if dds.client.hostsGuard.isOptimistic() {
+		if err := dds.checkHostsUnchanged(ctx, domain, result.Fingerprint()); err != nil {
+			return nil, err
+		}
+	}
+
+	return dds.SetHosts(ctx, args)
+}
+
+// checkHostsUnchanged reads the host records of the domain and returns HostsConflictError if their fingerprint differs
+func (dds DomainsDNSService) checkHostsUnchanged(ctx context.Context, domain string, fingerprint string) error {
+	current, err := dds.GetHosts(ctx, domain)
+	if err != nil {
+		return fmt.Errorf("unable to get the host records of %s: %v", domain, err)
+	}
+	currentFingerprint := current.DomainDNSGetHostsResult.Fingerprint()
+	if currentFingerprint != fingerprint {
+		return &HostsConflictError{Domain: domain, ReadFingerprint: fingerprint, CurrentFingerprint: currentFingerprint}
+	}
+	return nil
+}
+
+func findRecords(records []DomainsDNSHostRecord, match DomainsDNSRecordMatch) []int {
+	var indexes []int
+	for i, record := range records {
+		if match.matches(record) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
@@ -0,0 +1,198 @@
+package namecheap
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const fakeRecordsGetHostsResponse = `
+	<?xml version="1.0" encoding="utf-8"?>
+	<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+		<Errors />
+		<CommandResponse Type="namecheap.domains.dns.getHosts">
+			<DomainDNSGetHostsResult Domain="domain.net" EmailType="MX" IsUsingOurDNS="true">
+				<host HostId="1" Name="@" Type="MX" Address="mx1.domain.net." MXPref="5" TTL="1800" IsActive="true" IsDDNSEnabled="false" />
+				<host HostId="2" Name="@" Type="MX" Address="mx2.domain.net." MXPref="20" TTL="1800" IsActive="true" IsDDNSEnabled="false" />
+				<host HostId="3" Name="@" Type="CAA" Address="0 issue &quot;letsencrypt.org&quot;" MXPref="10" TTL="3600" IsActive="true" IsDDNSEnabled="false" />
+				<host HostId="4" Name="www" Type="A" Address="192.0.2.1" MXPref="10" TTL="300" IsActive="true" IsDDNSEnabled="false" />
+			</DomainDNSGetHostsResult>
+		</CommandResponse>
+	</ApiResponse>
+`
+
+const fakeRecordsSetHostsResponse = `
+	<?xml version="1.0" encoding="utf-8"?>
+	<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+		<Errors />
+		<CommandResponse Type="namecheap.domains.dns.setHosts">
+			<DomainDNSSetHostsResult Domain="domain.net" IsSuccess="true" />
+		</CommandResponse>
+	</ApiResponse>
+`
+
+func newRecordsMockServer(getHostsResponse string, sentSetHosts *url.Values) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := ioutil.ReadAll(request.Body)
+		query, _ := url.ParseQuery(string(body))
+
+		switch query.Get("Command") {
+		case "namecheap.domains.dns.getHosts":
+			_, _ = writer.Write([]byte(getHostsResponse))
+		case "namecheap.domains.dns.setHosts":
+			*sentSetHosts = query
+			_, _ = writer.Write([]byte(fakeRecordsSetHostsResponse))
+		}
+	}))
+}
+
+func TestDomainsDNSRecords(t *testing.T) {
+	setupRecords := func() (*Client, *url.Values, func()) {
+		sentSetHosts := url.Values{}
+		mockServer := newRecordsMockServer(fakeRecordsGetHostsResponse, &sentSetHosts)
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		return client, &sentSetHosts, mockServer.Close
+	}
+
+	t.Run("add_preserves_existing", func(t *testing.T) {
+		client, sent, closeServer := setupRecords()
+		defer closeServer()
+
+		_, err := client.DomainsDNS.AddRecord(context.TODO(), "domain.net", DomainsDNSHostRecord{
+			HostName:   "api",
+			RecordType: RecordTypeCNAME,
+			Address:    "www.domain.net.",
+		})
+		if err != nil {
+			t.Fatal("Unable to add record", err)
+		}
+
+		assert.Equal(t, "MX", sent.Get("EmailType"))
+		assert.Equal(t, "5", sent.Get("MXPref1"))
+		assert.Equal(t, "20", sent.Get("MXPref2"))
+		assert.Equal(t, `0 issue "letsencrypt.org"`, sent.Get("Address3"))
+		assert.Equal(t, "3600", sent.Get("TTL3"))
+		assert.Empty(t, sent.Get("MXPref3"))
+		assert.Equal(t, "300", sent.Get("TTL4"))
+		assert.Equal(t, "api", sent.Get("HostName5"))
+		assert.Equal(t, "CNAME", sent.Get("RecordType5"))
+	})
+
+	t.Run("add_duplicate", func(t *testing.T) {
+		client, sent, closeServer := setupRecords()
+		defer closeServer()
+
+		_, err := client.DomainsDNS.AddRecord(context.TODO(), "domain.net", DomainsDNSHostRecord{HostName: "WWW", RecordType: RecordTypeA, Address: "192.0.2.1"})
+
+		assert.EqualError(t, err, "record WWW A 192.0.2.1 already exists")
+		assert.Empty(t, *sent)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		client, sent, closeServer := setupRecords()
+		defer closeServer()
+
+		_, err := client.DomainsDNS.UpdateRecord(context.TODO(), "domain.net",
+			DomainsDNSRecordMatch{HostName: "@", RecordType: RecordTypeMX, Address: "mx2.domain.net."},
+			DomainsDNSHostRecord{HostName: "@", RecordType: RecordTypeMX, Address: "mx3.domain.net.", MXPref: UInt8(30)},
+		)
+		if err != nil {
+			t.Fatal("Unable to update record", err)
+		}
+
+		assert.Equal(t, "mx1.domain.net.", sent.Get("Address1"))
+		assert.Equal(t, "mx3.domain.net.", sent.Get("Address2"))
+		assert.Equal(t, "30", sent.Get("MXPref2"))
+		assert.Equal(t, "www", sent.Get("HostName4"))
+	})
+
+	t.Run("update_ambiguous", func(t *testing.T) {
+		client, sent, closeServer := setupRecords()
+		defer closeServer()
+
+		_, err := client.DomainsDNS.UpdateRecord(context.TODO(), "domain.net",
+			DomainsDNSRecordMatch{HostName: "@", RecordType: RecordTypeMX},
+			DomainsDNSHostRecord{HostName: "@", RecordType: RecordTypeMX, Address: "mx3.domain.net.", MXPref: UInt8(30)},
+		)
+
+		assert.EqualError(t, err, "2 records match @ MX, the address is required to select one")
+		assert.Empty(t, *sent)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		client, sent, closeServer := setupRecords()
+		defer closeServer()
+
+		_, err := client.DomainsDNS.DeleteRecord(context.TODO(), "domain.net", DomainsDNSRecordMatch{HostName: "www", RecordType: RecordTypeA})
+		if err != nil {
+			t.Fatal("Unable to delete record", err)
+		}
+
+		assert.Equal(t, "CAA", sent.Get("RecordType3"))
+		assert.NotContains(t, *sent, "RecordType4")
+
+		_, err = client.DomainsDNS.DeleteRecord(context.TODO(), "domain.net", DomainsDNSRecordMatch{HostName: "ftp", RecordType: RecordTypeA})
+		assert.EqualError(t, err, "record ftp A not found")
+	})
+
+	t.Run("delete_last_mx_rejected", func(t *testing.T) {
+		client, sent, closeServer := setupRecords()
+		defer closeServer()
+
+		_, err := client.DomainsDNS.DeleteRecord(context.TODO(), "domain.net", DomainsDNSRecordMatch{HostName: "@", RecordType: RecordTypeMX})
+
+		assert.EqualError(t, err, "minimum 1 MX record required for MX EmailType")
+		assert.Empty(t, *sent)
+	})
+
+	t.Run("upsert", func(t *testing.T) {
+		client, sent, closeServer := setupRecords()
+		defer closeServer()
+
+		_, err := client.DomainsDNS.UpsertRecord(context.TODO(), "domain.net", DomainsDNSHostRecord{HostName: "www", RecordType: RecordTypeA, Address: "192.0.2.2", TTL: 60})
+		if err != nil {
+			t.Fatal("Unable to upsert record", err)
+		}
+
+		assert.Equal(t, "192.0.2.2", sent.Get("Address4"))
+		assert.Equal(t, "60", sent.Get("TTL4"))
+		assert.NotContains(t, *sent, "RecordType5")
+
+		_, err = client.DomainsDNS.UpsertRecord(context.TODO(), "domain.net", DomainsDNSHostRecord{HostName: "blog", RecordType: RecordTypeA, Address: "192.0.2.3"})
+		if err != nil {
+			t.Fatal("Unable to upsert record", err)
+		}
+
+		assert.Equal(t, "blog", sent.Get("HostName5"))
+	})
+
+	t.Run("not_our_dns", func(t *testing.T) {
+		sentSetHosts := url.Values{}
+		mockServer := newRecordsMockServer(`
+			<?xml version="1.0" encoding="utf-8"?>
+			<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+				<Errors />
+				<CommandResponse Type="namecheap.domains.dns.getHosts">
+					<DomainDNSGetHostsResult Domain="domain.net" EmailType="" IsUsingOurDNS="false" />
+				</CommandResponse>
+			</ApiResponse>
+		`, &sentSetHosts)
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		_, err := client.DomainsDNS.AddRecord(context.TODO(), "domain.net", DomainsDNSHostRecord{HostName: "www", RecordType: RecordTypeA, Address: "192.0.2.1"})
+
+		assert.EqualError(t, err, "domain.net isn't using the Namecheap DNS, its host records can't be managed")
+		assert.Empty(t, sentSetHosts)
+	})
+}
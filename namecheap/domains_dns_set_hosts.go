@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/xml"
 	"fmt"
+	"net"
 	"regexp"
 	"strconv"
 	"strings"
@@ -39,6 +40,7 @@ var AllowedEmailTypeValues = []string{EmailTypeNone, EmailTypeMXE, EmailTypeMX,
 
 var allowedTagValues = []string{"issue", "issuewild", "iodef"}
 var validURLProtocolPrefix = regexp.MustCompile("[a-z]+://")
+var validDNSLabel = regexp.MustCompile("^[a-zA-Z0-9_]([a-zA-Z0-9_-]{0,61}[a-zA-Z0-9_])?$")
 
 type DomainsDNSHostRecord struct {
 	// Sub-domain/hostname to create the record for
@@ -67,6 +69,7 @@ type DomainsDNSSetHostsArgs struct {
 	// The flag value is an 8-bit number, the most significant bit of which indicates the criticality of understanding of a record by a CA.
 	// It's recommended to use '0'
 	// If nil provided, then this field is ignored
+	// Flag and Tag apply to the whole request, use CAARecord to set them per record in the Address instead
 	Flag *uint8
 	// A non-zero sequence of US-ASCII letters and numbers in lower case. The tag value can be one of the following values:
 	// "issue" — specifies the certification authority that is authorized to issue a certificate for the domain name or subdomain record used in the title.
@@ -136,79 +139,211 @@ func (dds DomainsDNSService) SetHosts(ctx context.Context, args *DomainsDNSSetHo
 	return &response.CommandResponse, nil
 }
 
+// validateDomainsDNSSetHostsArgs checks the args and the content of the records, the returned error is
+// a *ValidationError listing all the issues
 func validateDomainsDNSSetHostsArgs(args *DomainsDNSSetHostsArgs) error {
+	issues := &ValidationError{}
+
 	if args.EmailType != "" && !isValidEmailType(args.EmailType) {
-		return fmt.Errorf("invalid EmailType value: %s", args.EmailType)
+		issues.add("EmailType", fmt.Sprintf("invalid EmailType value: %s", args.EmailType))
 	}
 
 	if args.Tag != "" && !isValidTagValue(args.Tag) {
-		return fmt.Errorf("invalid Tag value: %s", args.Tag)
+		issues.add("Tag", fmt.Sprintf("invalid Tag value: %s", args.Tag))
 	}
 
 	mxRecordsCount := 0
 	mxeRecordsCount := 0
 
-	if args.Records != nil {
-		for i, record := range args.Records {
-			if record.RecordType == "" {
-				return fmt.Errorf("Records[%d].RecordType is required", i)
-			}
-			if !isValidRecordType(record.RecordType) {
-				return fmt.Errorf("invalid Records[%d].RecordType value: %s", i, record.RecordType)
-			}
+	for i, record := range args.Records {
+		validateHostRecord(i, record, args, issues)
 
-			if record.HostName == "" {
-				return fmt.Errorf("Records[%d].HostName is required", i)
-			}
+		if record.RecordType == RecordTypeMX {
+			mxRecordsCount++
+		} else if record.RecordType == RecordTypeMXE {
+			mxeRecordsCount++
+		}
+	}
 
-			if record.Address == "" {
-				return fmt.Errorf("Records[%d].Address is required", i)
-			}
+	validateHostRecordsConsistency(args.Records, issues)
 
-			if record.TTL != 0 && (record.TTL < MinTTL || record.TTL > MaxTTL) {
-				return fmt.Errorf("invalid Records[%d].TTL value: %d", i, record.TTL)
-			}
+	if args.EmailType == EmailTypeMXE && mxeRecordsCount != 1 {
+		issues.add("EmailType", "one MXE record required for MXE EmailType")
+	}
+
+	if args.EmailType == EmailTypeMX && mxRecordsCount == 0 {
+		issues.add("EmailType", "minimum 1 MX record required for MX EmailType")
+	}
+
+	return issues.errorOrNil()
+}
+
+// validateHostRecord checks the fields and the content of a single record, the request-level EmailType, Flag
+// and Tag of the args apply to it
+func validateHostRecord(i int, record DomainsDNSHostRecord, args *DomainsDNSSetHostsArgs, issues *ValidationError) {
+	field := fmt.Sprintf("Records[%d]", i)
+	emailType := args.EmailType
+
+	validType := false
+	if record.RecordType == "" {
+		issues.add(field+".RecordType", fmt.Sprintf("%s.RecordType is required", field))
+	} else if !isValidRecordType(record.RecordType) {
+		issues.add(field+".RecordType", fmt.Sprintf("invalid %s.RecordType value: %s", field, record.RecordType))
+	} else {
+		validType = true
+	}
+
+	if record.HostName == "" {
+		issues.add(field+".HostName", fmt.Sprintf("%s.HostName is required", field))
+	} else if !isValidRecordHostName(record.HostName) {
+		issues.add(field+".HostName", fmt.Sprintf("invalid %s.HostName value: %s", field, record.HostName))
+	}
+
+	if record.Address == "" {
+		issues.add(field+".Address", fmt.Sprintf("%s.Address is required", field))
+	}
+
+	if record.TTL != 0 && (record.TTL < MinTTL || record.TTL > MaxTTL) {
+		issues.add(field+".TTL", fmt.Sprintf("invalid %s.TTL value: %d", field, record.TTL))
+	}
+
+	if !validType {
+		return
+	}
+
+	switch record.RecordType {
+	case RecordTypeMX:
+		if record.MXPref == nil {
+			issues.add(field+".MXPref", fmt.Sprintf("%s.MXPref is nil but required for MX record type", field))
+		}
+		if emailType == "" {
+			issues.add(field+".RecordType", fmt.Sprintf("%s.RecordType MX is not allowed for blank EmailType", field))
+		} else if emailType != EmailTypeMX {
+			issues.add(field+".RecordType", fmt.Sprintf("%s.RecordType MX is not allowed for EmailType=%s", field, emailType))
+		}
+	case RecordTypeMXE:
+		if emailType == "" {
+			issues.add(field+".RecordType", fmt.Sprintf("%s.RecordType MXE is not allowed for blank EmailType", field))
+		} else if emailType != EmailTypeMXE {
+			issues.add(field+".RecordType", fmt.Sprintf("%s.RecordType MXE is not allowed for EmailType=%s", field, emailType))
+		}
+	case RecordTypeCNAME:
+		if record.HostName == "@" {
+			issues.add(field+".HostName", fmt.Sprintf("%s CNAME record isn't allowed at the apex (@), use ALIAS instead", field))
+		}
+	}
+
+	if record.Address == "" {
+		return
+	}
 
-			if record.RecordType == "MX" {
-				if record.MXPref == nil {
-					return fmt.Errorf("Records[%d].MXPref is nil but required for MX record type", i)
-				}
-				if args.EmailType == "" {
-					return fmt.Errorf("Records[%d].RecordType MX is not allowed for blank EmailType", i)
-				} else if args.EmailType != "MX" {
-					return fmt.Errorf("Records[%d].RecordType MX is not allowed for EmailType=%s", i, args.EmailType)
-				}
-				mxRecordsCount++
-			} else if record.RecordType == "MXE" {
-				if args.EmailType == "" {
-					return fmt.Errorf("Records[%d].RecordType MXE is not allowed for blank EmailType", i)
-				} else if args.EmailType != "MXE" {
-					return fmt.Errorf("Records[%d].RecordType MXE is not allowed for EmailType=%s", i, args.EmailType)
-				}
-				mxeRecordsCount++
-			} else if record.RecordType == "URL" || record.RecordType == "URL301" || record.RecordType == "FRAME" {
-				if !validURLProtocolPrefix.MatchString(record.Address) {
-					return fmt.Errorf(`Records[%d].Address "%s" must contain a protocol prefix for %s record`, i, record.Address, record.RecordType)
-				}
-			} else if record.RecordType == "CAA" {
-				if strings.Contains(record.Address, "iodef") && !validURLProtocolPrefix.MatchString(record.Address) {
-					return fmt.Errorf(`Records[%d].Address "%s" must contain a protocol prefix for %s iodef record`, i, record.Address, record.RecordType)
-				}
+	switch record.RecordType {
+	case RecordTypeA, RecordTypeMXE:
+		if ip := net.ParseIP(record.Address); ip == nil || strings.Contains(record.Address, ":") {
+			issues.add(field+".Address", fmt.Sprintf(`%s.Address "%s" must be an IPv4 address for %s record`, field, record.Address, record.RecordType))
+		}
+	case RecordTypeAAAA:
+		if ip := net.ParseIP(record.Address); ip == nil || !strings.Contains(record.Address, ":") {
+			issues.add(field+".Address", fmt.Sprintf(`%s.Address "%s" must be an IPv6 address for %s record`, field, record.Address, record.RecordType))
+		}
+	case RecordTypeCNAME, RecordTypeAlias, RecordTypeNS, RecordTypeMX:
+		if !isValidRecordTarget(record.Address) {
+			issues.add(field+".Address", fmt.Sprintf(`%s.Address "%s" must be a valid hostname for %s record`, field, record.Address, record.RecordType))
+		}
+	case RecordTypeURL, RecordTypeURL301, RecordTypeFrame:
+		if !validURLProtocolPrefix.MatchString(record.Address) {
+			issues.add(field+".Address", fmt.Sprintf(`%s.Address "%s" must contain a protocol prefix for %s record`, field, record.Address, record.RecordType))
+		}
+	case RecordTypeCAA:
+		switch {
+		case strings.Contains(record.Address, "iodef") && !validURLProtocolPrefix.MatchString(record.Address) && !strings.Contains(record.Address, "mailto:"):
+			issues.add(field+".Address", fmt.Sprintf(`%s.Address "%s" must contain a protocol prefix for %s iodef record`, field, record.Address, record.RecordType))
+		case args.Tag != "":
+			// the Address is the bare value of the request-level Tag
+			if err := (CAARecord{Tag: args.Tag, Value: record.Address}).Validate(); err != nil {
+				issues.add(field+".Address", fmt.Sprintf(`%s.Address "%s" is invalid: %v`, field, record.Address, err))
+			}
+		case args.Flag != nil:
+			// the Address is a bare value too, but its tag isn't known, so it's left to the API
+		default:
+			if caa, err := ParseCAAAddress(record.Address); err != nil {
+				issues.add(field+".Address", fmt.Sprintf(`%s.Address "%s" is invalid: %v`, field, record.Address, err))
+			} else if err := caa.Validate(); err != nil {
+				issues.add(field+".Address", fmt.Sprintf(`%s.Address "%s" is invalid: %v`, field, record.Address, err))
 			}
 		}
 	}
+}
 
-	if args.EmailType != "" {
-		if args.EmailType == "MXE" && mxeRecordsCount != 1 {
-			return fmt.Errorf("one MXE record required for MXE EmailType")
+// validateHostRecordsConsistency checks the records against each other: the duplicates, and the CNAME records
+// sharing the host name with other records
+func validateHostRecordsConsistency(records []DomainsDNSHostRecord, issues *ValidationError) {
+	firstIndexes := map[DomainsDNSRecordKey]int{}
+
+	for i, record := range records {
+		if record.HostName == "" || record.RecordType == "" || record.Address == "" {
+			continue
+		}
+
+		key := record.Key()
+		if first, ok := firstIndexes[key]; ok {
+			issues.add(fmt.Sprintf("Records[%d]", i), fmt.Sprintf("Records[%d] duplicates Records[%d]: %s", i, first, key))
+			continue
+		}
+		firstIndexes[key] = i
+	}
+
+	for i, record := range records {
+		if record.HostName == "" || !strings.EqualFold(record.RecordType, RecordTypeCNAME) {
+			continue
+		}
+
+		key := record.Key()
+		if first := firstIndexes[key]; first != i {
+			continue
 		}
 
-		if args.EmailType == "MX" && mxRecordsCount == 0 {
-			return fmt.Errorf("minimum 1 MX record required for MX EmailType")
+		count := 0
+		for j, other := range records {
+			if j != i && other.HostName != "" && other.RecordType != "" && normalizeRecordHostName(other.HostName) == key.HostName {
+				count++
+			}
+		}
+		if count > 0 {
+			issues.add(fmt.Sprintf("Records[%d].HostName", i), fmt.Sprintf("Records[%d] CNAME record of host %s can't coexist with other records of the host", i, record.HostName))
 		}
 	}
+}
 
-	return nil
+// isValidRecordHostName checks the host name of a record: @ for the domain itself, or dot separated labels
+// of letters, digits, hyphens and underscores. The first label may be the * wildcard.
+func isValidRecordHostName(hostName string) bool {
+	if hostName == "@" {
+		return true
+	}
+	if strings.HasPrefix(hostName, "*.") {
+		hostName = strings.TrimPrefix(hostName, "*.")
+	} else if hostName == "*" {
+		return true
+	}
+	return isValidDNSName(hostName)
+}
+
+// isValidRecordTarget checks the host name a record points to, the trailing dot is optional
+func isValidRecordTarget(target string) bool {
+	return isValidDNSName(strings.TrimSuffix(target, "."))
+}
+
+func isValidDNSName(name string) bool {
+	if name == "" || len(name) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(name, ".") {
+		if !validDNSLabel.MatchString(label) {
+			return false
+		}
+	}
+	return true
 }
 
 func parseDomainsDNSSetHostsArgs(args *DomainsDNSSetHostsArgs) (*map[string]string, error) {
@@ -303,6 +303,82 @@ func TestDomainsDNSSetHosts(t *testing.T) {
 		assert.Equal(t, "0 iodef http://domain.com", sentBody.Get("Address1"))
 	})
 
+	t.Run("request_data_correct_CAA_request_level_tag", func(t *testing.T) {
+		var sentBody url.Values
+
+		mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			body, _ := ioutil.ReadAll(request.Body)
+			query, _ := url.ParseQuery(string(body))
+			sentBody = query
+			_, _ = writer.Write([]byte(fakeResponse))
+		}))
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		_, err := client.DomainsDNS.SetHosts(context.TODO(), &DomainsDNSSetHostsArgs{
+			Domain: "domain.net",
+			Flag:   UInt8(0),
+			Tag:    "issue",
+			Records: []DomainsDNSHostRecord{
+				{
+					RecordType: RecordTypeCAA,
+					HostName:   "@",
+					Address:    "letsencrypt.org",
+				},
+			},
+		})
+		if err != nil {
+			t.Fatal("Unable to set hosts", err)
+		}
+
+		assert.Equal(t, "0", sentBody.Get("Flag"))
+		assert.Equal(t, "issue", sentBody.Get("Tag"))
+		assert.Equal(t, "letsencrypt.org", sentBody.Get("Address1"))
+	})
+
+	t.Run("request_data_valid_content", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			_, _ = writer.Write([]byte(fakeResponse))
+		}))
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		_, err := client.DomainsDNS.SetHosts(context.TODO(), &DomainsDNSSetHostsArgs{
+			Domain: "domain.net",
+			Records: []DomainsDNSHostRecord{
+				{RecordType: RecordTypeA, HostName: "*", Address: "10.11.12.13"},
+				{RecordType: RecordTypeAAAA, HostName: "*.dev", Address: "2001:db8::1"},
+				{RecordType: RecordTypeTXT, HostName: "_acme-challenge", Address: "token"},
+				{RecordType: RecordTypeCNAME, HostName: "s1._domainkey", Address: "s1.domainkey.u1.wl.sendgrid.net."},
+				{RecordType: RecordTypeAlias, HostName: "@", Address: "app.host.net"},
+				{RecordType: RecordTypeNS, HostName: "sub", Address: "ns1.other.net."},
+			},
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("request_data_error_lists_fields", func(t *testing.T) {
+		client := setupClient(nil)
+
+		_, err := client.DomainsDNS.SetHosts(context.TODO(), &DomainsDNSSetHostsArgs{
+			Domain: "domain.net",
+			Records: []DomainsDNSHostRecord{
+				{RecordType: RecordTypeCNAME, HostName: "@", Address: "not a host"},
+				{RecordType: RecordTypeA, HostName: "www"},
+			},
+		})
+
+		validationErr, ok := err.(*ValidationError)
+		if assert.True(t, ok) {
+			assert.Equal(t, []string{"Records[0].HostName", "Records[0].Address", "Records[1].Address"}, validationErr.Fields())
+		}
+	})
+
 	var errorCases = []struct {
 		Name          string
 		Args          *DomainsDNSSetHostsArgs
@@ -366,7 +442,7 @@ func TestDomainsDNSSetHosts(t *testing.T) {
 			Args: &DomainsDNSSetHostsArgs{
 				Domain: "domain.net",
 				Records: []DomainsDNSHostRecord{
-					{RecordType: RecordTypeCNAME, HostName: "@", Address: "domain.com", TTL: 59},
+					{RecordType: RecordTypeCNAME, HostName: "www", Address: "domain.com", TTL: 59},
 				},
 			},
 			ExpectedError: "invalid Records[0].TTL value: 59",
@@ -376,7 +452,7 @@ func TestDomainsDNSSetHosts(t *testing.T) {
 			Args: &DomainsDNSSetHostsArgs{
 				Domain: "domain.net",
 				Records: []DomainsDNSHostRecord{
-					{RecordType: RecordTypeCNAME, HostName: "@", Address: "domain.com", TTL: 60_001},
+					{RecordType: RecordTypeCNAME, HostName: "www", Address: "domain.com", TTL: 60_001},
 				},
 			},
 			ExpectedError: "invalid Records[0].TTL value: 60001",
@@ -386,7 +462,7 @@ func TestDomainsDNSSetHosts(t *testing.T) {
 			Args: &DomainsDNSSetHostsArgs{
 				Domain: "domain.net",
 				Records: []DomainsDNSHostRecord{
-					{RecordType: RecordTypeCNAME, HostName: "@"},
+					{RecordType: RecordTypeCNAME, HostName: "www"},
 				},
 			},
 			ExpectedError: "Records[0].Address is required",
@@ -397,7 +473,7 @@ func TestDomainsDNSSetHosts(t *testing.T) {
 				EmailType: EmailTypeMX,
 				Domain:    "domain.net",
 				Records: []DomainsDNSHostRecord{
-					{RecordType: RecordTypeCNAME, HostName: "@", Address: "domain.com", TTL: 1800},
+					{RecordType: RecordTypeCNAME, HostName: "www", Address: "domain.com", TTL: 1800},
 				},
 			},
 			ExpectedError: "minimum 1 MX record required for MX EmailType",
@@ -408,7 +484,7 @@ func TestDomainsDNSSetHosts(t *testing.T) {
 				EmailType: EmailTypeMXE,
 				Domain:    "domain.net",
 				Records: []DomainsDNSHostRecord{
-					{RecordType: RecordTypeCNAME, HostName: "@", Address: "domain.com", TTL: 1800},
+					{RecordType: RecordTypeCNAME, HostName: "www", Address: "domain.com", TTL: 1800},
 				},
 			},
 			ExpectedError: "one MXE record required for MXE EmailType",
@@ -518,6 +594,101 @@ func TestDomainsDNSSetHosts(t *testing.T) {
 			},
 			ExpectedError: `Records[0].Address "0 iodef domain.com" must contain a protocol prefix for CAA iodef record`,
 		},
+		{
+			Name: "request_data_error_invalid_caa_value_for_request_level_tag",
+			Args: &DomainsDNSSetHostsArgs{
+				Domain: "domain.net",
+				Tag:    "issue",
+				Records: []DomainsDNSHostRecord{
+					{RecordType: RecordTypeCAA, HostName: "@", Address: "lets encrypt"},
+				},
+			},
+			ExpectedError: `Records[0].Address "lets encrypt" is invalid: invalid Value value: lets encrypt, issuer domain is invalid`,
+		},
+		{
+			Name: "request_data_error_a_record_ipv6",
+			Args: &DomainsDNSSetHostsArgs{
+				Domain: "domain.net",
+				Records: []DomainsDNSHostRecord{
+					{RecordType: RecordTypeA, HostName: "@", Address: "2001:db8::1"},
+				},
+			},
+			ExpectedError: `Records[0].Address "2001:db8::1" must be an IPv4 address for A record`,
+		},
+		{
+			Name: "request_data_error_aaaa_record_ipv4",
+			Args: &DomainsDNSSetHostsArgs{
+				Domain: "domain.net",
+				Records: []DomainsDNSHostRecord{
+					{RecordType: RecordTypeAAAA, HostName: "@", Address: "10.11.12.13"},
+				},
+			},
+			ExpectedError: `Records[0].Address "10.11.12.13" must be an IPv6 address for AAAA record`,
+		},
+		{
+			Name: "request_data_error_invalid_target",
+			Args: &DomainsDNSSetHostsArgs{
+				Domain: "domain.net",
+				Records: []DomainsDNSHostRecord{
+					{RecordType: RecordTypeNS, HostName: "sub", Address: "ns1 domain.com"},
+				},
+			},
+			ExpectedError: `Records[0].Address "ns1 domain.com" must be a valid hostname for NS record`,
+		},
+		{
+			Name: "request_data_error_invalid_hostname",
+			Args: &DomainsDNSSetHostsArgs{
+				Domain: "domain.net",
+				Records: []DomainsDNSHostRecord{
+					{RecordType: RecordTypeA, HostName: "-www", Address: "10.11.12.13"},
+				},
+			},
+			ExpectedError: "invalid Records[0].HostName value: -www",
+		},
+		{
+			Name: "request_data_error_cname_at_apex",
+			Args: &DomainsDNSSetHostsArgs{
+				Domain: "domain.net",
+				Records: []DomainsDNSHostRecord{
+					{RecordType: RecordTypeCNAME, HostName: "@", Address: "domain.com"},
+				},
+			},
+			ExpectedError: "Records[0] CNAME record isn't allowed at the apex (@), use ALIAS instead",
+		},
+		{
+			Name: "request_data_error_cname_with_other_records",
+			Args: &DomainsDNSSetHostsArgs{
+				Domain: "domain.net",
+				Records: []DomainsDNSHostRecord{
+					{RecordType: RecordTypeCNAME, HostName: "www", Address: "domain.com"},
+					{RecordType: RecordTypeTXT, HostName: "WWW", Address: "hello"},
+				},
+			},
+			ExpectedError: "Records[0] CNAME record of host www can't coexist with other records of the host",
+		},
+		{
+			Name: "request_data_error_duplicate",
+			Args: &DomainsDNSSetHostsArgs{
+				Domain: "domain.net",
+				Records: []DomainsDNSHostRecord{
+					{RecordType: RecordTypeA, HostName: "www", Address: "10.11.12.13"},
+					{RecordType: RecordTypeA, HostName: "www", Address: "10.11.12.13", TTL: 300},
+				},
+			},
+			ExpectedError: "Records[1] duplicates Records[0]: www A 10.11.12.13",
+		},
+		{
+			Name: "request_data_error_all_issues",
+			Args: &DomainsDNSSetHostsArgs{
+				Domain:    "domain.net",
+				EmailType: "BAD_TYPE",
+				Records: []DomainsDNSHostRecord{
+					{RecordType: RecordTypeA, HostName: "www", Address: "10.11.12"},
+					{RecordType: RecordTypeCNAME, HostName: "api", Address: "domain.com", TTL: 30},
+				},
+			},
+			ExpectedError: `invalid EmailType value: BAD_TYPE; Records[0].Address "10.11.12" must be an IPv4 address for A record; invalid Records[1].TTL value: 30`,
+		},
 	}
 
 	for _, errorCase := range errorCases {
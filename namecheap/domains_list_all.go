@@ -0,0 +1,176 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const defaultListAllPageSize = 100
+
+// DomainsListAllArgs struct is an input arguments for DomainsService.ListAll and DomainsService.Iterate functions
+type DomainsListAllArgs struct {
+	// Possible values are ALL, EXPIRING, or EXPIRED
+	// Default Value: ALL
+	ListType string
+	// Keyword to look for in the domain list
+	SearchTerm string
+	// Possible values are NAME, NAME_DESC, EXPIREDATE, EXPIREDATE_DESC, CREATEDATE, CREATEDATE_DESC
+	SortBy string
+	// Number of domains to be requested per page. Minimum value is 10, and maximum value is 100.
+	// Default value: 100
+	PageSize int
+
+	// The filters below are applied on the client side, zero values disable them
+
+	// Only the domains expiring within the number of days, the expired ones are excluded
+	ExpiringWithinDays int
+	// Only the domains with auto-renew turned off
+	AutoRenewOff bool
+	// Only the domains not using the Namecheap DNS
+	NotOurDNS bool
+}
+
+// DomainsIterator walks all the pages of the domain list:
+//
+//	it := client.Domains.Iterate(&DomainsListAllArgs{AutoRenewOff: true})
+//	for it.Next(ctx) {
+//		domain := it.Domain()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type DomainsIterator struct {
+	ds   *DomainsService
+	args DomainsListAllArgs
+	now  func() time.Time
+
+	page    int
+	buffer  []Domain
+	current Domain
+	done    bool
+	err     error
+}
+
+// Iterate returns an iterator over the domains of all the pages. The pages are requested lazily.
+// DomainsListAllArgs is the input arguments, nil means all the domains.
+func (ds *DomainsService) Iterate(args *DomainsListAllArgs) *DomainsIterator {
+	it := &DomainsIterator{ds: ds, now: time.Now}
+	if args != nil {
+		it.args = *args
+	}
+	if it.args.PageSize == 0 {
+		it.args.PageSize = defaultListAllPageSize
+	}
+
+	if it.args.PageSize < 10 || it.args.PageSize > 100 {
+		it.err = fmt.Errorf("invalid PageSize value: %d, minimum value is 10, and maximum value is 100", it.args.PageSize)
+	} else if it.args.ExpiringWithinDays < 0 {
+		it.err = fmt.Errorf("invalid ExpiringWithinDays value: %d, minimum value is 0", it.args.ExpiringWithinDays)
+	}
+	it.done = it.err != nil
+
+	return it
+}
+
+// ListAll returns the domains of all the pages matching the filters
+func (ds *DomainsService) ListAll(ctx context.Context, args *DomainsListAllArgs) ([]Domain, error) {
+	var domains []Domain
+
+	it := ds.Iterate(args)
+	for it.Next(ctx) {
+		domains = append(domains, it.Domain())
+	}
+
+	return domains, it.Err()
+}
+
+// Next advances to the next domain matching the filters, requesting the next page when needed.
+// It returns false when there are no more domains or an error occurred, check Err.
+func (it *DomainsIterator) Next(ctx context.Context) bool {
+	for {
+		for len(it.buffer) > 0 {
+			domain := it.buffer[0]
+			it.buffer = it.buffer[1:]
+			if it.matches(domain) {
+				it.current = domain
+				return true
+			}
+		}
+
+		if it.done {
+			return false
+		}
+
+		if err := ctx.Err(); err != nil {
+			it.err = err
+			it.done = true
+			return false
+		}
+
+		it.fetchPage(ctx)
+	}
+}
+
+// Domain returns the current domain
+func (it *DomainsIterator) Domain() Domain {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *DomainsIterator) Err() error {
+	return it.err
+}
+
+func (it *DomainsIterator) fetchPage(ctx context.Context) {
+	it.page++
+
+	response, err := it.ds.GetList(ctx, &DomainsGetListArgs{
+		ListType:   it.args.ListType,
+		SearchTerm: it.args.SearchTerm,
+		SortBy:     it.args.SortBy,
+		Page:       it.page,
+		PageSize:   it.args.PageSize,
+	})
+	if err != nil {
+		it.err = fmt.Errorf("unable to get page %d of the domain list: %v", it.page, err)
+		it.done = true
+		return
+	}
+
+	if response == nil || len(response.Domains) == 0 {
+		it.done = true
+		return
+	}
+
+	it.buffer = response.Domains
+
+	pageSize := response.Paging.PageSize
+	if pageSize == 0 {
+		pageSize = it.args.PageSize
+	}
+	if it.page*pageSize >= response.Paging.TotalItems || len(response.Domains) < pageSize {
+		it.done = true
+	}
+}
+
+func (it *DomainsIterator) matches(domain Domain) bool {
+	if it.args.AutoRenewOff && domain.AutoRenew {
+		return false
+	}
+
+	if it.args.NotOurDNS && domain.IsOurDNS {
+		return false
+	}
+
+	if it.args.ExpiringWithinDays > 0 {
+		// the expiration dates have no time, so compare them with the start of the day
+		now := it.now().UTC()
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		if domain.IsExpired || domain.Expires.Before(today) || domain.Expires.After(today.AddDate(0, 0, it.args.ExpiringWithinDays)) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,148 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newDomainsListMockServer serves 25 domains: domain-N.com expires in N days, the even ones
+// have auto-renew turned on and the ones divisible by 3 use our DNS
+func newDomainsListMockServer(requestedPages *[]string) *httptest.Server {
+	const total = 25
+	now := time.Now().UTC()
+
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := ioutil.ReadAll(request.Body)
+		query, _ := url.ParseQuery(string(body))
+		*requestedPages = append(*requestedPages, query.Get("Page")+"/"+query.Get("PageSize"))
+
+		page, _ := strconv.Atoi(query.Get("Page"))
+		pageSize, _ := strconv.Atoi(query.Get("PageSize"))
+
+		var domains strings.Builder
+		for i := (page-1)*pageSize + 1; i <= page*pageSize && i <= total; i++ {
+			fmt.Fprintf(&domains, `<Domain ID="%d" Name="domain-%d.com" User="user" Created="06/02/2021" Expires="%s" IsExpired="false" IsLocked="false" AutoRenew="%t" WhoisGuard="ENABLED" IsPremium="false" IsOurDNS="%t" />`,
+				i, i, now.AddDate(0, 0, i).Format("01/02/2006"), i%2 == 0, i%3 == 0)
+		}
+
+		_, _ = fmt.Fprintf(writer, `
+			<?xml version="1.0" encoding="utf-8"?>
+			<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+				<Errors />
+				<CommandResponse Type="namecheap.domains.getList">
+					<DomainGetListResult>%s</DomainGetListResult>
+					<Paging>
+						<TotalItems>%d</TotalItems>
+						<CurrentPage>%d</CurrentPage>
+						<PageSize>%d</PageSize>
+					</Paging>
+				</CommandResponse>
+			</ApiResponse>
+		`, domains.String(), total, page, pageSize)
+	}))
+}
+
+func TestDomainsListAll(t *testing.T) {
+	t.Run("all_pages", func(t *testing.T) {
+		var requestedPages []string
+		mockServer := newDomainsListMockServer(&requestedPages)
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		domains, err := client.Domains.ListAll(context.TODO(), &DomainsListAllArgs{PageSize: 10})
+		if err != nil {
+			t.Fatal("Unable to list domains", err)
+		}
+
+		assert.Len(t, domains, 25)
+		assert.Equal(t, "domain-25.com", domains[24].Name)
+		assert.Equal(t, []string{"1/10", "2/10", "3/10"}, requestedPages)
+	})
+
+	t.Run("default_page_size", func(t *testing.T) {
+		var requestedPages []string
+		mockServer := newDomainsListMockServer(&requestedPages)
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		domains, err := client.Domains.ListAll(context.TODO(), nil)
+		if err != nil {
+			t.Fatal("Unable to list domains", err)
+		}
+
+		assert.Len(t, domains, 25)
+		assert.Equal(t, []string{"1/100"}, requestedPages)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		var requestedPages []string
+		mockServer := newDomainsListMockServer(&requestedPages)
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		domains, err := client.Domains.ListAll(context.TODO(), &DomainsListAllArgs{
+			PageSize:           10,
+			ExpiringWithinDays: 12,
+			AutoRenewOff:       true,
+			NotOurDNS:          true,
+		})
+		if err != nil {
+			t.Fatal("Unable to list domains", err)
+		}
+
+		var names []string
+		for _, domain := range domains {
+			names = append(names, domain.Name)
+		}
+		assert.Equal(t, []string{"domain-1.com", "domain-5.com", "domain-7.com", "domain-11.com"}, names)
+	})
+
+	t.Run("cancellation", func(t *testing.T) {
+		var requestedPages []string
+		mockServer := newDomainsListMockServer(&requestedPages)
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		it := client.Domains.Iterate(&DomainsListAllArgs{PageSize: 10})
+		count := 0
+		for it.Next(ctx) {
+			count++
+			if count == 10 {
+				cancel()
+			}
+		}
+
+		assert.Equal(t, 10, count)
+		assert.Equal(t, context.Canceled, it.Err())
+		assert.Equal(t, []string{"1/10"}, requestedPages)
+	})
+
+	t.Run("invalid_page_size", func(t *testing.T) {
+		client := setupClient(nil)
+
+		_, err := client.Domains.ListAll(context.TODO(), &DomainsListAllArgs{PageSize: 101})
+
+		assert.EqualError(t, err, "invalid PageSize value: 101, minimum value is 10, and maximum value is 100")
+	})
+}
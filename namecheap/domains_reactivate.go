@@ -0,0 +1,120 @@
+package namecheap
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+type DomainReactivateResponse struct {
+	XMLName xml.Name `xml:"ApiResponse"`
+	Errors  []struct {
+		Message string `xml:",chardata"`
+		Number  string `xml:"Number,attr"`
+	} `xml:"Errors>Error"`
+	CommandResponse *DomainReactivateCommandResponse `xml:"CommandResponse"`
+}
+
+type DomainReactivateCommandResponse struct {
+	DomainReactivateResult DomainReactivateResult `xml:"DomainReactivateResult"`
+}
+
+/*
+Domain	Domain name that you are trying to reactivate.
+IsSuccess	Possible responses: True, False. Indicates whether the domain was reactivated.
+ChargedAmount	Total amount charged for reactivation.
+OrderID	Unique integer value that represents the order.
+TransactionID	Unique integer value that represents the transaction.
+*/
+type DomainReactivateResult struct {
+	Domain        string `xml:"Domain,attr"`
+	IsSuccess     bool   `xml:"IsSuccess,attr"`
+	ChargedAmount Money  `xml:"ChargedAmount,attr"`
+	OrderID       string `xml:"OrderID,attr"`
+	TransactionID string `xml:"TransactionID,attr"`
+}
+
+/*
+DomainName	String	70	Yes	Domain name to reactivate
+PromotionCode	String	20	No	Promotional (coupon) code for reactivating the domain
+IsPremiumDomain	Boolean	10	No	Indication if the domain name is premium
+PremiumPrice	Currency	20	No	Reactivation price for the premium domain
+*/
+type DomainReactivateArgs struct {
+	DomainName      string // Required
+	PromotionCode   string // Optional
+	IsPremiumDomain bool   // Optional
+	PremiumPrice    Money  // Required for the premium domains
+}
+
+// Reactivate reactivates an expired domain for a single year
+// The reactivation is checked against the client SpendingPolicy, if any.
+//
+// Namecheap doc: https://www.namecheap.com/support/api/methods/domains/reactivate/
+func (ds *DomainsService) Reactivate(ctx context.Context, args DomainReactivateArgs) (*DomainReactivateResult, error) {
+	if _, err := ParseDomain(args.DomainName); err != nil {
+		return nil, fmt.Errorf("invalid DomainName value: %s, %v", args.DomainName, err)
+	}
+	if args.IsPremiumDomain && args.PremiumPrice.IsZero() {
+		return nil, fmt.Errorf("PremiumPrice is required for the premium domains")
+	}
+
+	var result *DomainReactivateResult
+
+	operation := PlannedOperation{
+		Action: ActionNameReactivate,
+		Domain: strings.ToLower(args.DomainName),
+		Years:  1,
+	}
+
+	err := ds.client.spendGuard.spend(ctx, operation, args.PromotionCode, func() (*Money, bool, error) {
+		var err error
+		result, err = ds.reactivate(ctx, args)
+		if result != nil && result.IsSuccess {
+			return &result.ChargedAmount, true, err
+		}
+		// API errors are definitive, the transport ones leave the outcome unknown
+		return nil, result != nil, err
+	})
+
+	return result, err
+}
+
+func (ds *DomainsService) reactivate(ctx context.Context, args DomainReactivateArgs) (*DomainReactivateResult, error) {
+	var resp DomainReactivateResponse
+
+	params := map[string]string{
+		"Command":    "namecheap.domains.reactivate",
+		"DomainName": args.DomainName,
+	}
+
+	if args.PromotionCode != "" {
+		params["PromotionCode"] = args.PromotionCode
+	}
+
+	if args.IsPremiumDomain {
+		params["IsPremiumDomain"] = "true"
+		params["PremiumPrice"] = args.PremiumPrice.Amount.String()
+	}
+
+	_, err := ds.client.DoXML(ctx, params, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiErr error
+	if resp.Errors != nil && len(resp.Errors) > 0 {
+		errMessages := []string{}
+		for _, e := range resp.Errors {
+			errMessages = append(errMessages, fmt.Sprintf("%s (%s)", e.Message, e.Number))
+		}
+		apiErr = fmt.Errorf("%s", strings.Join(errMessages, "; "))
+	}
+
+	if resp.CommandResponse == nil {
+		return &DomainReactivateResult{}, apiErr
+	}
+
+	return &resp.CommandResponse.DomainReactivateResult, apiErr
+}
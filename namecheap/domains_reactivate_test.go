@@ -0,0 +1,65 @@
+package namecheap
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainsReactivate(t *testing.T) {
+	fakeResponse := `
+		<?xml version="1.0" encoding="utf-8"?>
+		<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+			<Errors />
+			<RequestedCommand>namecheap.domains.reactivate</RequestedCommand>
+			<CommandResponse Type="namecheap.domains.reactivate">
+				<DomainReactivateResult Domain="domain.com" IsSuccess="true" ChargedAmount="650.0000" OrderID="23569" TransactionID="25080" />
+			</CommandResponse>
+		</ApiResponse>
+	`
+
+	t.Run("request_data_passing", func(t *testing.T) {
+		var sentQuery url.Values
+		mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			body, _ := ioutil.ReadAll(request.Body)
+			sentQuery, _ = url.ParseQuery(string(body))
+			_, _ = writer.Write([]byte(fakeResponse))
+		}))
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		result, err := client.Domains.Reactivate(context.TODO(), DomainReactivateArgs{
+			DomainName:      "domain.com",
+			IsPremiumDomain: true,
+			PremiumPrice:    MustParseMoney("650", DefaultCurrency),
+		})
+		if err != nil {
+			t.Fatal("Unable to reactivate domain", err)
+		}
+
+		assert.Equal(t, "namecheap.domains.reactivate", sentQuery.Get("Command"))
+		assert.Equal(t, "domain.com", sentQuery.Get("DomainName"))
+		assert.Equal(t, "true", sentQuery.Get("IsPremiumDomain"))
+		assert.Equal(t, "650.00", sentQuery.Get("PremiumPrice"))
+		assert.NotContains(t, sentQuery, "PromotionCode")
+
+		assert.True(t, result.IsSuccess)
+		assert.Equal(t, "23569", result.OrderID)
+		assert.Equal(t, MustParseMoney("650", DefaultCurrency), result.ChargedAmount)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		client := setupClient(nil)
+
+		_, err := client.Domains.Reactivate(context.TODO(), DomainReactivateArgs{DomainName: "domain.com", IsPremiumDomain: true})
+
+		assert.EqualError(t, err, "PremiumPrice is required for the premium domains")
+	})
+}
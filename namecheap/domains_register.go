@@ -0,0 +1,273 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRegisterPollInterval       = 15 * time.Second
+	defaultRegisterPollTimeout        = 10 * time.Minute
+	defaultRegisterAvailabilityGrace  = 2 * time.Minute
+	defaultRegisterAvailabilityChecks = 3
+)
+
+const (
+	// DomainRegisterStatusRegistered means the domain has been registered by this call
+	DomainRegisterStatusRegistered = "REGISTERED"
+	// DomainRegisterStatusAlreadyOwned means the domain was in the account already, e.g. registered by a previous attempt
+	DomainRegisterStatusAlreadyOwned = "ALREADY_OWNED"
+	// DomainRegisterStatusUnavailable means the domain is registered by someone else
+	DomainRegisterStatusUnavailable = "UNAVAILABLE"
+	// DomainRegisterStatusNotRegistered means the registration has definitely failed, it's safe to retry
+	DomainRegisterStatusNotRegistered = "NOT_REGISTERED"
+	// DomainRegisterStatusPending means the outcome wasn't known yet when the poll timed out, the domain must be
+	// looked up in the account later. Don't retry, the registration may still complete and be charged.
+	DomainRegisterStatusPending = "PENDING"
+)
+
+// DomainRegisterArgs struct is an input arguments for DomainsService.Register function
+type DomainRegisterArgs struct {
+	DomainCreateArgs
+
+	// Maximum estimated charge including fees, the registration is refused above it. Zero means no limit.
+	MaxCharge Money
+	// Optional estimator used to price the registration
+	// Default value: NewCostEstimator(client, nil)
+	Estimator *CostEstimator
+	// Interval between the lookups of the domain in the account
+	// Default value: 15s
+	PollInterval time.Duration
+	// How long to wait for a non real-time registration or for the outcome of a failed request to show up
+	// Default value: 10m
+	PollTimeout time.Duration
+	// How long after a create request with unknown outcome the availability check starts being trusted.
+	// The check lags the registry, so a domain just registered may still be reported as available.
+	// Default value: 2m
+	AvailabilityGracePeriod time.Duration
+	// Number of consecutive available answers, after the AvailabilityGracePeriod, proving a create request with
+	// unknown outcome has failed
+	// Default value: 3
+	AvailabilityChecks int
+}
+
+// DomainRegisterResult is the outcome of DomainsService.Register
+type DomainRegisterResult struct {
+	// One of the DomainRegisterStatus constants
+	Status string
+	Domain string
+	// Estimated price of the registration, nil when the domain hasn't been priced
+	Estimate *CostEstimateItem
+	// Response of the create command, nil when the outcome has been reconciled from the account
+	CreateResult *DomainCreateResult
+	// Whether the outcome has been determined by looking the domain up in the account
+	Reconciled bool
+}
+
+// Register registers a domain name and is safe to retry.
+// It checks the domain isn't in the account already, checks the availability, prices the registration
+// and sends the create command. When the outcome of the create command is unknown (e.g. a timeout) or the
+// registration isn't real-time, the domain is looked up in the account until the outcome is known or
+// PollTimeout elapses.
+//
+// The premium price and the EAP fee are filled from the availability check for the premium domains.
+// An unavailable domain isn't an error, check DomainRegisterResult.Status.
+//
+// NOTE: the outcome can only be reconciled while ctx is alive, prefer the HTTP client timeout
+// over a short ctx deadline to bound the create request.
+func (ds *DomainsService) Register(ctx context.Context, args DomainRegisterArgs) (*DomainRegisterResult, error) {
+	err := args.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	domain := strings.ToLower(args.DomainName)
+	result := &DomainRegisterResult{Domain: domain}
+
+	owned, err := ds.isInAccount(ctx, domain)
+	if err != nil {
+		return nil, fmt.Errorf("unable to look %s up in the account: %v", domain, err)
+	}
+	if owned {
+		result.Status = DomainRegisterStatusAlreadyOwned
+		result.Reconciled = true
+		return result, nil
+	}
+
+	check, err := ds.checkOne(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	if !check.Available {
+		result.Status = DomainRegisterStatusUnavailable
+		return result, nil
+	}
+
+	estimator := args.Estimator
+	if estimator == nil {
+		estimator = NewCostEstimator(ds.client, nil)
+	}
+	operation := PlannedOperation{Action: ActionNameRegister, Domain: domain, Years: args.Years}
+	result.Estimate, err = estimateOperation(ctx, estimator.catalog(args.PromotionCode), operation, *check)
+	if err != nil {
+		return nil, fmt.Errorf("unable to price %s: %v", operation, err)
+	}
+
+	if !args.MaxCharge.IsZero() {
+		cmp, err := result.Estimate.Total.Cmp(args.MaxCharge)
+		if err != nil {
+			return nil, err
+		}
+		if cmp > 0 {
+			result.Status = DomainRegisterStatusNotRegistered
+			return result, fmt.Errorf("%s estimated at %s exceeds the maximum charge of %s", operation, result.Estimate.Total, args.MaxCharge)
+		}
+	}
+
+	createArgs := args.DomainCreateArgs
+	if check.IsPremiumName {
+		createArgs.IsPremiumDomain = true
+		if createArgs.PremiumPrice.IsZero() {
+			createArgs.PremiumPrice = check.PremiumRegistrationPrice
+		}
+		if createArgs.EapFee.IsZero() {
+			createArgs.EapFee = check.EapFee
+		}
+	}
+
+	createResult, unknownOutcome, createErr := ds.createGuarded(ctx, createArgs)
+	switch {
+	case unknownOutcome:
+		return ds.reconcileRegistration(ctx, args, result, false, createErr)
+	case createErr != nil:
+		result.Status = DomainRegisterStatusNotRegistered
+		result.CreateResult = createResult
+		return result, createErr
+	}
+
+	result.CreateResult = createResult
+	if strings.EqualFold(createResult.NonRealTime, "true") {
+		return ds.reconcileRegistration(ctx, args, result, true, nil)
+	}
+	if !strings.EqualFold(createResult.Registered, "true") {
+		result.Status = DomainRegisterStatusNotRegistered
+		return result, fmt.Errorf("%s hasn't been registered", domain)
+	}
+
+	result.Status = DomainRegisterStatusRegistered
+	return result, nil
+}
+
+// reconcileRegistration polls the account until the domain shows up in it. The availability check lags the registry,
+// so it's never trusted for the accepted non real-time registrations, which end up pending instead. The outcome of a
+// failed request is only proven negative by several available answers after the grace period.
+// createErr is the error of the create command, if any.
+func (ds *DomainsService) reconcileRegistration(ctx context.Context, args DomainRegisterArgs, result *DomainRegisterResult, nonRealTime bool, createErr error) (*DomainRegisterResult, error) {
+	pollInterval := args.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultRegisterPollInterval
+	}
+	pollTimeout := args.PollTimeout
+	if pollTimeout <= 0 {
+		pollTimeout = defaultRegisterPollTimeout
+	}
+
+	gracePeriod := args.AvailabilityGracePeriod
+	if gracePeriod <= 0 {
+		gracePeriod = defaultRegisterAvailabilityGrace
+	}
+	requiredChecks := args.AvailabilityChecks
+	if requiredChecks <= 0 {
+		requiredChecks = defaultRegisterAvailabilityChecks
+	}
+
+	started := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, pollTimeout)
+	defer cancel()
+
+	result.Reconciled = true
+
+	var lastErr error
+	availableChecks := 0
+	for {
+		owned, err := ds.isInAccount(ctx, result.Domain)
+		if err == nil && owned {
+			result.Status = DomainRegisterStatusRegistered
+			return result, nil
+		}
+
+		if err == nil && !nonRealTime && time.Since(started) >= gracePeriod {
+			// a registered domain, or one in the middle of the registration, isn't available anymore
+			var check *DomainCheckResult
+			check, err = ds.checkOne(ctx, result.Domain)
+			if err == nil && check.Available {
+				availableChecks++
+			} else {
+				availableChecks = 0
+			}
+
+			if availableChecks >= requiredChecks {
+				result.Status = DomainRegisterStatusNotRegistered
+				if createErr == nil {
+					createErr = fmt.Errorf("%s hasn't been registered", result.Domain)
+				}
+				return result, createErr
+			}
+		}
+
+		// the lookups interrupted by the poll timeout aren't worth reporting
+		if ctx.Err() == nil {
+			lastErr = err
+		}
+
+		timer := time.NewTimer(pollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			result.Status = DomainRegisterStatusPending
+			message := fmt.Sprintf("registration of %s is still pending after %s", result.Domain, pollTimeout)
+			switch {
+			case createErr != nil:
+				return result, fmt.Errorf("%s: %v", message, createErr)
+			case lastErr != nil:
+				return result, fmt.Errorf("%s: %v", message, lastErr)
+			}
+			return result, fmt.Errorf("%s", message)
+		case <-timer.C:
+		}
+	}
+}
+
+// isInAccount reports whether the domain is in the account
+func (ds *DomainsService) isInAccount(ctx context.Context, domain string) (bool, error) {
+	response, err := ds.GetList(ctx, &DomainsGetListArgs{SearchTerm: domain, PageSize: 100})
+	if err != nil {
+		return false, err
+	}
+	if response == nil {
+		return false, nil
+	}
+
+	for _, d := range response.Domains {
+		if strings.EqualFold(d.Name, domain) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (ds *DomainsService) checkOne(ctx context.Context, domain string) (*DomainCheckResult, error) {
+	results, err := ds.Check(ctx, []string{domain})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		if strings.EqualFold(result.Domain, domain) {
+			return &result, nil
+		}
+	}
+	return nil, fmt.Errorf("no availability result for %s", domain)
+}
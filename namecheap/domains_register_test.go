@@ -0,0 +1,316 @@
+package namecheap
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// registerMock simulates the account: the domain shows up in the list once registered,
+// and the availability check reflects whether anyone holds the domain
+type registerMock struct {
+	m sync.Mutex
+
+	// Whether the domain is in the account
+	owned bool
+	// Whether the domain is held by anyone, including the account
+	taken bool
+	// Handles the create command, it may update the state
+	create func(writer http.ResponseWriter, mock *registerMock)
+	// Number of the list lookups to be answered with an empty list after the create command
+	pendingLookups int
+	// Whether the availability check lags behind and keeps reporting the domain as available
+	staleCheck bool
+
+	createCalls int
+	createQuery url.Values
+}
+
+func (rm *registerMock) handler(writer http.ResponseWriter, request *http.Request) {
+	body, _ := ioutil.ReadAll(request.Body)
+	query, _ := url.ParseQuery(string(body))
+
+	rm.m.Lock()
+	defer rm.m.Unlock()
+
+	switch query.Get("Command") {
+	case "namecheap.domains.getList":
+		domains := ""
+		if rm.owned && rm.pendingLookups == 0 {
+			domains = `<Domain ID="9007" Name="new.com" User="user" Created="06/02/2021" Expires="06/02/2022" IsExpired="false" IsLocked="false" AutoRenew="false" WhoisGuard="ENABLED" IsPremium="false" IsOurDNS="true" />`
+		}
+		if rm.createCalls > 0 && rm.pendingLookups > 0 {
+			rm.pendingLookups--
+		}
+		_, _ = writer.Write([]byte(`
+			<?xml version="1.0" encoding="utf-8"?>
+			<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+				<Errors />
+				<CommandResponse Type="namecheap.domains.getList">
+					<DomainGetListResult>` + domains + `</DomainGetListResult>
+					<Paging><TotalItems>1</TotalItems><CurrentPage>1</CurrentPage><PageSize>100</PageSize></Paging>
+				</CommandResponse>
+			</ApiResponse>
+		`))
+	case "namecheap.domains.check":
+		available := "true"
+		if rm.taken && !rm.staleCheck {
+			available = "false"
+		}
+		_, _ = writer.Write([]byte(`
+			<?xml version="1.0" encoding="utf-8"?>
+			<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+				<Errors />
+				<CommandResponse Type="namecheap.domains.check">
+					<DomainCheckResult Domain="new.com" Available="` + available + `" IsPremiumName="false" PremiumRegistrationPrice="0" PremiumRenewalPrice="0" PremiumRestorePrice="0" PremiumTransferPrice="0" IcannFee="0.18" EapFee="0" />
+				</CommandResponse>
+			</ApiResponse>
+		`))
+	case "namecheap.users.getPricing":
+		_, _ = writer.Write([]byte(fakePricingResponse))
+	case "namecheap.domains.create":
+		rm.createCalls++
+		rm.createQuery = query
+		rm.create(writer, rm)
+	}
+}
+
+func registeredCreate(nonRealTime string) func(writer http.ResponseWriter, mock *registerMock) {
+	return func(writer http.ResponseWriter, mock *registerMock) {
+		mock.owned, mock.taken = true, true
+		_, _ = writer.Write([]byte(`
+			<?xml version="1.0" encoding="utf-8"?>
+			<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+				<Errors />
+				<CommandResponse Type="namecheap.domains.create">
+					<DomainCreateResult Domain="new.com" Registered="true" ChargedAmount="9.06" DomainID="9007" OrderID="196074" TransactionID="380716" WhoisguardEnable="false" NonRealTimeDomain="` + nonRealTime + `" />
+				</CommandResponse>
+			</ApiResponse>
+		`))
+	}
+}
+
+// droppedCreate closes the connection without a response, optionally after registering the domain
+func droppedCreate(registered bool) func(writer http.ResponseWriter, mock *registerMock) {
+	return func(writer http.ResponseWriter, mock *registerMock) {
+		mock.owned, mock.taken = registered, registered
+		conn, _, _ := writer.(http.Hijacker).Hijack()
+		_ = conn.Close()
+	}
+}
+
+func TestDomainsRegister(t *testing.T) {
+	setupRegister := func(mock *registerMock) (*Client, func()) {
+		mockServer := httptest.NewServer(http.HandlerFunc(mock.handler))
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		return client, mockServer.Close
+	}
+
+	newArgs := func() DomainRegisterArgs {
+		return DomainRegisterArgs{
+			DomainCreateArgs: NewDomainCreateArgs("new.com", 1, fakeContact),
+			PollInterval:     time.Millisecond,
+			PollTimeout:      time.Second,
+		}
+	}
+
+	t.Run("registered", func(t *testing.T) {
+		mock := &registerMock{create: registeredCreate("false")}
+		client, closeServer := setupRegister(mock)
+		defer closeServer()
+
+		result, err := client.Domains.Register(context.TODO(), newArgs())
+		if err != nil {
+			t.Fatal("Unable to register domain", err)
+		}
+
+		assert.Equal(t, DomainRegisterStatusRegistered, result.Status)
+		assert.False(t, result.Reconciled)
+		assert.Equal(t, MustParseMoney("9.06", "USD"), result.Estimate.Total)
+		assert.Equal(t, MustParseMoney("9.06", "USD"), result.CreateResult.ChargedAmount)
+		assert.Equal(t, 1, mock.createCalls)
+	})
+
+	t.Run("retry_after_registration", func(t *testing.T) {
+		mock := &registerMock{owned: true, taken: true, create: registeredCreate("false")}
+		client, closeServer := setupRegister(mock)
+		defer closeServer()
+
+		result, err := client.Domains.Register(context.TODO(), newArgs())
+		if err != nil {
+			t.Fatal("Unable to register domain", err)
+		}
+
+		assert.Equal(t, DomainRegisterStatusAlreadyOwned, result.Status)
+		assert.Equal(t, 0, mock.createCalls)
+	})
+
+	t.Run("unavailable", func(t *testing.T) {
+		mock := &registerMock{taken: true, create: registeredCreate("false")}
+		client, closeServer := setupRegister(mock)
+		defer closeServer()
+
+		result, err := client.Domains.Register(context.TODO(), newArgs())
+		if err != nil {
+			t.Fatal("Unable to register domain", err)
+		}
+
+		assert.Equal(t, DomainRegisterStatusUnavailable, result.Status)
+		assert.Equal(t, 0, mock.createCalls)
+	})
+
+	t.Run("max_charge", func(t *testing.T) {
+		mock := &registerMock{create: registeredCreate("false")}
+		client, closeServer := setupRegister(mock)
+		defer closeServer()
+
+		args := newArgs()
+		args.MaxCharge = MustParseMoney("5", "USD")
+
+		result, err := client.Domains.Register(context.TODO(), args)
+
+		assert.EqualError(t, err, "register new.com for 1 year(s) estimated at 9.06 USD exceeds the maximum charge of 5.00 USD")
+		assert.Equal(t, DomainRegisterStatusNotRegistered, result.Status)
+		assert.Equal(t, 0, mock.createCalls)
+	})
+
+	t.Run("unknown_outcome_registered", func(t *testing.T) {
+		mock := &registerMock{create: droppedCreate(true)}
+		client, closeServer := setupRegister(mock)
+		defer closeServer()
+
+		result, err := client.Domains.Register(context.TODO(), newArgs())
+		if err != nil {
+			t.Fatal("Unable to register domain", err)
+		}
+
+		assert.Equal(t, DomainRegisterStatusRegistered, result.Status)
+		assert.True(t, result.Reconciled)
+		assert.Nil(t, result.CreateResult)
+	})
+
+	t.Run("unknown_outcome_not_registered", func(t *testing.T) {
+		mock := &registerMock{create: droppedCreate(false)}
+		client, closeServer := setupRegister(mock)
+		defer closeServer()
+
+		args := newArgs()
+		args.AvailabilityGracePeriod = 10 * time.Millisecond
+
+		result, err := client.Domains.Register(context.TODO(), args)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, DomainRegisterStatusNotRegistered, result.Status)
+		assert.True(t, result.Reconciled)
+		assert.Equal(t, 1, mock.createCalls)
+	})
+
+	t.Run("unknown_outcome_stale_check", func(t *testing.T) {
+		mock := &registerMock{create: droppedCreate(true), pendingLookups: 5, staleCheck: true}
+		client, closeServer := setupRegister(mock)
+		defer closeServer()
+
+		args := newArgs()
+		args.AvailabilityGracePeriod = time.Nanosecond
+		args.AvailabilityChecks = 10
+
+		result, err := client.Domains.Register(context.TODO(), args)
+		if err != nil {
+			t.Fatal("Unable to register domain", err)
+		}
+
+		assert.Equal(t, DomainRegisterStatusRegistered, result.Status)
+		assert.True(t, result.Reconciled)
+	})
+
+	t.Run("unknown_outcome_grace_period", func(t *testing.T) {
+		mock := &registerMock{create: droppedCreate(true), pendingLookups: 1000000, staleCheck: true}
+		client, closeServer := setupRegister(mock)
+		defer closeServer()
+
+		args := newArgs()
+		args.PollTimeout = 50 * time.Millisecond
+
+		result, err := client.Domains.Register(context.TODO(), args)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, DomainRegisterStatusPending, result.Status)
+	})
+
+	t.Run("non_real_time_polled", func(t *testing.T) {
+		mock := &registerMock{create: registeredCreate("true"), pendingLookups: 3, staleCheck: true}
+		client, closeServer := setupRegister(mock)
+		defer closeServer()
+
+		result, err := client.Domains.Register(context.TODO(), newArgs())
+		if err != nil {
+			t.Fatal("Unable to register domain", err)
+		}
+
+		assert.Equal(t, DomainRegisterStatusRegistered, result.Status)
+		assert.True(t, result.Reconciled)
+		assert.Equal(t, "9007", result.CreateResult.DomainID)
+		assert.Equal(t, 0, mock.pendingLookups)
+	})
+
+	t.Run("non_real_time_pending", func(t *testing.T) {
+		mock := &registerMock{create: registeredCreate("true"), pendingLookups: 1000000, staleCheck: true}
+		client, closeServer := setupRegister(mock)
+		defer closeServer()
+
+		args := newArgs()
+		args.PollTimeout = 50 * time.Millisecond
+
+		result, err := client.Domains.Register(context.TODO(), args)
+
+		assert.EqualError(t, err, "registration of new.com is still pending after 50ms")
+		assert.Equal(t, DomainRegisterStatusPending, result.Status)
+	})
+
+	t.Run("premium_price_filled", func(t *testing.T) {
+		mock := &registerMock{create: registeredCreate("false")}
+		mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			body, _ := ioutil.ReadAll(request.Body)
+			query, _ := url.ParseQuery(string(body))
+			if query.Get("Command") == "namecheap.domains.check" {
+				_, _ = writer.Write([]byte(`
+					<?xml version="1.0" encoding="utf-8"?>
+					<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+						<Errors />
+						<CommandResponse Type="namecheap.domains.check">
+							<DomainCheckResult Domain="new.com" Available="true" IsPremiumName="true" PremiumRegistrationPrice="100.00" PremiumRenewalPrice="20.00" PremiumRestorePrice="0" PremiumTransferPrice="20.00" IcannFee="0.18" EapFee="50.00" />
+						</CommandResponse>
+					</ApiResponse>
+				`))
+				return
+			}
+			request.Body = ioutil.NopCloser(strings.NewReader(string(body)))
+			mock.handler(writer, request)
+		}))
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		result, err := client.Domains.Register(context.TODO(), newArgs())
+		if err != nil {
+			t.Fatal("Unable to register domain", err)
+		}
+
+		assert.True(t, result.Estimate.IsPremium)
+		assert.Equal(t, "true", mock.createQuery.Get("IsPremiumDomain"))
+		assert.Equal(t, "100.00", mock.createQuery.Get("PremiumPrice"))
+		assert.Equal(t, "50.00", mock.createQuery.Get("EapFee"))
+	})
+}
@@ -0,0 +1,131 @@
+package namecheap
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type DomainRenewResponse struct {
+	XMLName xml.Name `xml:"ApiResponse"`
+	Errors  []struct {
+		Message string `xml:",chardata"`
+		Number  string `xml:"Number,attr"`
+	} `xml:"Errors>Error"`
+	CommandResponse *DomainRenewCommandResponse `xml:"CommandResponse"`
+}
+
+type DomainRenewCommandResponse struct {
+	DomainRenewResult DomainRenewResult `xml:"DomainRenewResult"`
+}
+
+/*
+DomainName	Domain name that you are trying to renew.
+DomainID	Unique integer value that represents the domain.
+Renew	Possible responses: True, False. Indicates whether the domain was renewed.
+ChargedAmount	Total amount charged for renewal.
+OrderID	Unique integer value that represents the order.
+TransactionID	Unique integer value that represents the transaction.
+ExpiredDate	New expiration date of the domain.
+*/
+type DomainRenewResult struct {
+	DomainName    string `xml:"DomainName,attr"`
+	DomainID      string `xml:"DomainID,attr"`
+	Renew         bool   `xml:"Renew,attr"`
+	ChargedAmount Money  `xml:"ChargedAmount,attr"`
+	OrderID       string `xml:"OrderID,attr"`
+	TransactionID string `xml:"TransactionID,attr"`
+	ExpiredDate   string `xml:"DomainDetails>ExpiredDate"`
+}
+
+/*
+DomainName	String	70	Yes	Domain name to renew
+Years	Number	2	Yes	Number of years to renew
+PromotionCode	String	20	No	Promotional (coupon) code for renewing the domain
+IsPremiumDomain	Boolean	10	No	Indication if the domain name is premium
+PremiumPrice	Currency	20	No	Renewal price for the premium domain
+*/
+type DomainRenewArgs struct {
+	DomainName      string // Required
+	Years           int    // Required
+	PromotionCode   string // Optional
+	IsPremiumDomain bool   // Optional
+	PremiumPrice    Money  // Required for the premium domains
+}
+
+// Renew renews an expiring domain, the expired ones must be reactivated instead, see Reactivate
+// The renewal is checked against the client SpendingPolicy, if any.
+//
+// Namecheap doc: https://www.namecheap.com/support/api/methods/domains/renew/
+func (ds *DomainsService) Renew(ctx context.Context, args DomainRenewArgs) (*DomainRenewResult, error) {
+	if _, err := ParseDomain(args.DomainName); err != nil {
+		return nil, fmt.Errorf("invalid DomainName value: %s, %v", args.DomainName, err)
+	}
+	if args.Years < 1 || args.Years > 10 {
+		return nil, fmt.Errorf("invalid Years value: %d, minimum value is 1, and maximum value is 10", args.Years)
+	}
+	if args.IsPremiumDomain && args.PremiumPrice.IsZero() {
+		return nil, fmt.Errorf("PremiumPrice is required for the premium domains")
+	}
+
+	var result *DomainRenewResult
+
+	operation := PlannedOperation{
+		Action: ActionNameRenew,
+		Domain: strings.ToLower(args.DomainName),
+		Years:  args.Years,
+	}
+
+	err := ds.client.spendGuard.spend(ctx, operation, args.PromotionCode, func() (*Money, bool, error) {
+		var err error
+		result, err = ds.renew(ctx, args)
+		if result != nil && result.Renew {
+			return &result.ChargedAmount, true, err
+		}
+		// API errors are definitive, the transport ones leave the outcome unknown
+		return nil, result != nil, err
+	})
+
+	return result, err
+}
+
+func (ds *DomainsService) renew(ctx context.Context, args DomainRenewArgs) (*DomainRenewResult, error) {
+	var resp DomainRenewResponse
+
+	params := map[string]string{
+		"Command":    "namecheap.domains.renew",
+		"DomainName": args.DomainName,
+		"Years":      strconv.Itoa(args.Years),
+	}
+
+	if args.PromotionCode != "" {
+		params["PromotionCode"] = args.PromotionCode
+	}
+
+	if args.IsPremiumDomain {
+		params["IsPremiumDomain"] = "true"
+		params["PremiumPrice"] = args.PremiumPrice.Amount.String()
+	}
+
+	_, err := ds.client.DoXML(ctx, params, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiErr error
+	if resp.Errors != nil && len(resp.Errors) > 0 {
+		errMessages := []string{}
+		for _, e := range resp.Errors {
+			errMessages = append(errMessages, fmt.Sprintf("%s (%s)", e.Message, e.Number))
+		}
+		apiErr = fmt.Errorf("%s", strings.Join(errMessages, "; "))
+	}
+
+	if resp.CommandResponse == nil {
+		return &DomainRenewResult{}, apiErr
+	}
+
+	return &resp.CommandResponse.DomainRenewResult, apiErr
+}
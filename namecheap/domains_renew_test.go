@@ -0,0 +1,97 @@
+package namecheap
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainsRenew(t *testing.T) {
+	fakeResponse := `
+		<?xml version="1.0" encoding="utf-8"?>
+		<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+			<Errors />
+			<RequestedCommand>namecheap.domains.renew</RequestedCommand>
+			<CommandResponse Type="namecheap.domains.renew">
+				<DomainRenewResult DomainName="domain.com" DomainID="151378" Renew="true" OrderID="82171" TransactionID="137441" ChargedAmount="28.3200">
+					<DomainDetails>
+						<ExpiredDate>06/05/2023 11:59:59 PM</ExpiredDate>
+						<NumYears>0</NumYears>
+					</DomainDetails>
+				</DomainRenewResult>
+			</CommandResponse>
+		</ApiResponse>
+	`
+
+	t.Run("request_data_passing", func(t *testing.T) {
+		var sentQuery url.Values
+		mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			body, _ := ioutil.ReadAll(request.Body)
+			sentQuery, _ = url.ParseQuery(string(body))
+			_, _ = writer.Write([]byte(fakeResponse))
+		}))
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		result, err := client.Domains.Renew(context.TODO(), DomainRenewArgs{
+			DomainName:      "domain.com",
+			Years:           2,
+			PromotionCode:   "COUPON",
+			IsPremiumDomain: true,
+			PremiumPrice:    MustParseMoney("14.16", DefaultCurrency),
+		})
+		if err != nil {
+			t.Fatal("Unable to renew domain", err)
+		}
+
+		assert.Equal(t, "namecheap.domains.renew", sentQuery.Get("Command"))
+		assert.Equal(t, "domain.com", sentQuery.Get("DomainName"))
+		assert.Equal(t, "2", sentQuery.Get("Years"))
+		assert.Equal(t, "COUPON", sentQuery.Get("PromotionCode"))
+		assert.Equal(t, "true", sentQuery.Get("IsPremiumDomain"))
+		assert.Equal(t, "14.16", sentQuery.Get("PremiumPrice"))
+
+		assert.True(t, result.Renew)
+		assert.Equal(t, "151378", result.DomainID)
+		assert.Equal(t, "06/05/2023 11:59:59 PM", result.ExpiredDate)
+		assert.Equal(t, MustParseMoney("28.32", DefaultCurrency), result.ChargedAmount)
+	})
+
+	t.Run("api_error", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			_, _ = writer.Write([]byte(`
+				<?xml version="1.0" encoding="utf-8"?>
+				<ApiResponse Status="ERROR" xmlns="http://api.namecheap.com/xml.response">
+					<Errors>
+						<Error Number="2020166">Domain has expired, please reactivate it</Error>
+					</Errors>
+				</ApiResponse>
+			`))
+		}))
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		_, err := client.Domains.Renew(context.TODO(), DomainRenewArgs{DomainName: "domain.com", Years: 1})
+
+		assert.EqualError(t, err, "Domain has expired, please reactivate it (2020166)")
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		client := setupClient(nil)
+
+		_, err := client.Domains.Renew(context.TODO(), DomainRenewArgs{DomainName: "domain.com", Years: 11})
+		assert.EqualError(t, err, "invalid Years value: 11, minimum value is 1, and maximum value is 10")
+
+		_, err = client.Domains.Renew(context.TODO(), DomainRenewArgs{DomainName: "domain.com", Years: 1, IsPremiumDomain: true})
+		assert.EqualError(t, err, "PremiumPrice is required for the premium domains")
+	})
+}
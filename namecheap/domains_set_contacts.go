@@ -0,0 +1,141 @@
+package namecheap
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+type DomainSetContactsResponse struct {
+	XMLName xml.Name `xml:"ApiResponse"`
+	Errors  []struct {
+		Message string `xml:",chardata"`
+		Number  string `xml:"Number,attr"`
+	} `xml:"Errors>Error"`
+	CommandResponse *DomainSetContactsCommandResponse `xml:"CommandResponse"`
+}
+
+type DomainSetContactsCommandResponse struct {
+	DomainSetContactResult DomainSetContactResult `xml:"DomainSetContactResult"`
+}
+
+/*
+Domain	Domain name the contacts were set for
+IsSuccess	Possible responses: True, False. Indicates whether the contacts were changed
+*/
+type DomainSetContactResult struct {
+	Domain    string `xml:"Domain,attr"`
+	IsSuccess bool   `xml:"IsSuccess,attr"`
+}
+
+func (d DomainSetContactResult) String() string {
+	return fmt.Sprintf("{Domain: %s, IsSuccess: %t}", d.Domain, d.IsSuccess)
+}
+
+/*
+DomainName	String	70	Yes	Domain name to set the contacts for
+Registrant, Tech, Admin, AuxBilling	Contact	n/a	Yes	Contacts of the roles, sent as RegistrantFirstName, TechFirstName, etc.
+Billing	Contact	n/a	No	Billing contact
+ExtendedAttributes	ExtendedAttributes	n/a	Yes	Required for the same TLDs as on registration, see IsExtendedAttributesRequired
+*/
+type DomainSetContactsArgs struct {
+	DomainName         string             // Required
+	Registrant         Contact            // Required
+	Tech               Contact            // Required
+	Admin              Contact            // Required
+	AuxBilling         Contact            // Required
+	Billing            Contact            // Optional
+	ExtendedAttributes ExtendedAttributes // Required for some TLDs, see IsExtendedAttributesRequired
+}
+
+// NewDomainSetContactsArgs returns DomainSetContactsArgs with the same contact used for the Registrant, Tech, Admin and AuxBilling roles
+func NewDomainSetContactsArgs(domainName string, contact Contact) DomainSetContactsArgs {
+	return DomainSetContactsArgs{
+		DomainName: domainName,
+		Registrant: contact,
+		Tech:       contact,
+		Admin:      contact,
+		AuxBilling: contact,
+	}
+}
+
+// GetContact returns the contact of the role: Registrant, Tech, Admin, AuxBilling or Billing
+func (args DomainSetContactsArgs) GetContact(role string) Contact {
+	switch role {
+	case ContactRoleRegistrant:
+		return args.Registrant
+	case ContactRoleTech:
+		return args.Tech
+	case ContactRoleAdmin:
+		return args.Admin
+	case ContactRoleAuxBilling:
+		return args.AuxBilling
+	case ContactRoleBilling:
+		return args.Billing
+	}
+	return Contact{}
+}
+
+// Validate checks the arguments before they're sent to the API.
+// The returned *ValidationError names every offending field, e.g. RegistrantPhone.
+func (args DomainSetContactsArgs) Validate() error {
+	issues := &ValidationError{}
+
+	if args.DomainName == "" {
+		issues.add("DomainName", "DomainName is required")
+	} else if parsedDomain, err := ParseDomain(args.DomainName); err != nil {
+		issues.add("DomainName", fmt.Sprintf("invalid DomainName value: %s, %v", args.DomainName, err))
+	} else {
+		validateExtendedAttributes(parsedDomain.TLD, args.ExtendedAttributes, issues)
+	}
+
+	for _, role := range RequiredContactRoles {
+		args.GetContact(role).validate(role, issues)
+	}
+
+	if !args.Billing.IsEmpty() {
+		args.Billing.validate(ContactRoleBilling, issues)
+	}
+
+	return issues.errorOrNil()
+}
+
+// SetContacts sets the contacts of the domain along with the extended attributes of its TLD
+// The arguments are validated first, see DomainSetContactsArgs.Validate.
+//
+// Namecheap doc: https://www.namecheap.com/support/api/methods/domains/set-contacts/
+func (ds *DomainsService) SetContacts(ctx context.Context, args DomainSetContactsArgs) (*DomainSetContactsCommandResponse, error) {
+	err := args.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	var resp DomainSetContactsResponse
+
+	params := map[string]string{
+		"Command":    "namecheap.domains.setContacts",
+		"DomainName": args.DomainName,
+	}
+
+	for _, role := range RequiredContactRoles {
+		args.GetContact(role).toParams(role, params)
+	}
+	args.Billing.toParams(ContactRoleBilling, params)
+
+	extendedAttributesToParams(args.ExtendedAttributes, params)
+
+	_, err = ds.client.DoXML(ctx, params, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Errors != nil && len(resp.Errors) > 0 {
+		errMessages := []string{}
+		for _, e := range resp.Errors {
+			errMessages = append(errMessages, fmt.Sprintf("%s (%s)", e.Message, e.Number))
+		}
+		return nil, fmt.Errorf("%s", strings.Join(errMessages, "; "))
+	}
+
+	return resp.CommandResponse, nil
+}
@@ -0,0 +1,73 @@
+package namecheap
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainsSetContacts(t *testing.T) {
+	fakeResponse := `
+		<?xml version="1.0" encoding="utf-8"?>
+		<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+			<Errors />
+			<RequestedCommand>namecheap.domains.setContacts</RequestedCommand>
+			<CommandResponse Type="namecheap.domains.setContacts">
+				<DomainSetContactResult Domain="domain.us" IsSuccess="true" />
+			</CommandResponse>
+		</ApiResponse>
+	`
+
+	t.Run("request_data_passing", func(t *testing.T) {
+		var sentQuery url.Values
+		mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			body, _ := ioutil.ReadAll(request.Body)
+			sentQuery, _ = url.ParseQuery(string(body))
+			_, _ = writer.Write([]byte(fakeResponse))
+		}))
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		args := NewDomainSetContactsArgs("domain.us", fakeContact)
+		args.Admin.FirstName = "John"
+		args.ExtendedAttributes = USExtendedAttributes{Nexus: "C11", Purpose: "P3"}
+
+		result, err := client.Domains.SetContacts(context.TODO(), args)
+		if err != nil {
+			t.Fatal("Unable to set contacts", err)
+		}
+
+		assert.Equal(t, "namecheap.domains.setContacts", sentQuery.Get("Command"))
+		assert.Equal(t, "domain.us", sentQuery.Get("DomainName"))
+		assert.Equal(t, "Jane", sentQuery.Get("RegistrantFirstName"))
+		assert.Equal(t, "John", sentQuery.Get("AdminFirstName"))
+		assert.Equal(t, "webmaster@example.com", sentQuery.Get("AuxBillingEmailAddress"))
+		assert.NotContains(t, sentQuery, "BillingFirstName")
+		assert.Equal(t, "C11", sentQuery.Get("RegistrantNexus"))
+		assert.Equal(t, "P3", sentQuery.Get("RegistrantPurpose"))
+
+		assert.Equal(t, DomainSetContactResult{Domain: "domain.us", IsSuccess: true}, result.DomainSetContactResult)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		client := setupClient(nil)
+
+		args := NewDomainSetContactsArgs("domain.us", fakeContact)
+		args.Tech.Phone = "12145550000"
+
+		_, err := client.Domains.SetContacts(context.TODO(), args)
+
+		var validationErr *ValidationError
+		if assert.True(t, errors.As(err, &validationErr)) {
+			assert.Equal(t, []string{"ExtendedAttributes", "TechPhone"}, validationErr.Fields())
+		}
+	})
+}
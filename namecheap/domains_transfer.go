@@ -0,0 +1,183 @@
+package namecheap
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type DomainTransferResponse struct {
+	XMLName xml.Name `xml:"ApiResponse"`
+	Errors  []struct {
+		Message string `xml:",chardata"`
+		Number  string `xml:"Number,attr"`
+	} `xml:"Errors>Error"`
+	CommandResponse *DomainTransferCommandResponse `xml:"CommandResponse"`
+}
+
+type DomainTransferCommandResponse struct {
+	DomainTransferCreateResult DomainTransferResult `xml:"DomainTransferCreateResult"`
+}
+
+/*
+DomainName	Domain name that you are trying to transfer.
+Transfer	Possible responses: True, False. Indicates whether the transfer was created.
+TransferID	Unique integer value that represents the transfer.
+StatusID	Status of the transfer, see domains.transfer.getStatus.
+OrderID	Unique integer value that represents the order.
+TransactionID	Unique integer value that represents the transaction.
+ChargedAmount	Total amount charged for the transfer.
+*/
+type DomainTransferResult struct {
+	DomainName    string `xml:"DomainName,attr"`
+	Transfer      bool   `xml:"Transfer,attr"`
+	TransferID    string `xml:"TransferID,attr"`
+	StatusID      string `xml:"StatusID,attr"`
+	OrderID       string `xml:"OrderID,attr"`
+	TransactionID string `xml:"TransactionID,attr"`
+	ChargedAmount Money  `xml:"ChargedAmount,attr"`
+}
+
+/*
+DomainName	String	70	Yes	Domain name to transfer
+Years	Number	2	Yes	Number of years to renew after a successful transfer -- Default Value: 1
+EPPCode	String	n/a	No	The EPPCode is required for transferring most of the TLDs
+PromotionCode	String	20	No	Promotional (coupon) code for transfer
+AddFreeWhoisguard	String	10	No	Adds free domain privacy for the domain -- Default Value: no
+WGEnabled	String	10	No	Enables free domain privacy for the domain -- Default Value: no
+IsPremiumDomain	Boolean	10	No	Indication if the domain name is premium
+PremiumPrice	Currency	20	No	Transfer price for the premium domain
+EapFee	Currency	20	No	Purchase fee for the premium domain during Early Access Program (EAP)
+ExtendedAttributes	ExtendedAttributes	n/a	No	Registry-specific attributes, sent as on registration
+*/
+type DomainTransferArgs struct {
+	DomainName         string             // Required
+	Years              int                // Optional
+	EPPCode            string             // Optional
+	PromotionCode      string             // Optional
+	AddFreeWhoisguard  string             // Optional
+	WGEnabled          string             // Optional
+	IsPremiumDomain    bool               // Optional
+	PremiumPrice       Money              // Required for the premium domains
+	EapFee             Money              // Optional
+	ExtendedAttributes ExtendedAttributes // Optional
+}
+
+// Validate checks the arguments before they're sent to the API.
+// The returned *ValidationError names every offending field.
+func (args DomainTransferArgs) Validate() error {
+	issues := &ValidationError{}
+
+	if args.DomainName == "" {
+		issues.add("DomainName", "DomainName is required")
+	} else if parsedDomain, err := ParseDomain(args.DomainName); err != nil {
+		issues.add("DomainName", fmt.Sprintf("invalid DomainName value: %s, %v", args.DomainName, err))
+	} else if args.ExtendedAttributes != nil {
+		validateExtendedAttributes(parsedDomain.TLD, args.ExtendedAttributes, issues)
+	}
+
+	if args.Years != 0 && args.Years != 1 {
+		issues.add("Years", fmt.Sprintf("invalid Years value: %d, transfers add a single year", args.Years))
+	}
+
+	if len(args.PromotionCode) > 20 {
+		issues.add("PromotionCode", "invalid PromotionCode value: maximum length is 20 characters")
+	}
+
+	if args.IsPremiumDomain && args.PremiumPrice.IsZero() {
+		issues.add("PremiumPrice", "PremiumPrice is required for the premium domains")
+	}
+
+	return issues.errorOrNil()
+}
+
+// Transfer creates a transfer of the domain from another registrar
+// The arguments are validated first, see DomainTransferArgs.Validate. The transfer is checked
+// against the client SpendingPolicy, if any.
+//
+// Namecheap doc: https://www.namecheap.com/support/api/methods/domains-transfer/create/
+func (ds *DomainsService) Transfer(ctx context.Context, args DomainTransferArgs) (*DomainTransferResult, error) {
+	err := args.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	var result *DomainTransferResult
+
+	operation := PlannedOperation{
+		Action: ActionNameTransfer,
+		Domain: strings.ToLower(strings.TrimSpace(args.DomainName)),
+		Years:  1,
+	}
+
+	err = ds.client.spendGuard.spend(ctx, operation, args.PromotionCode, func() (*Money, bool, error) {
+		var err error
+		result, err = ds.transfer(ctx, args)
+		if result != nil && result.Transfer {
+			return &result.ChargedAmount, true, err
+		}
+		// API errors are definitive, the transport ones leave the outcome unknown
+		return nil, result != nil, err
+	})
+
+	return result, err
+}
+
+func (ds *DomainsService) transfer(ctx context.Context, args DomainTransferArgs) (*DomainTransferResult, error) {
+	var resp DomainTransferResponse
+
+	params := map[string]string{
+		"Command":    "namecheap.domains.transfer.create",
+		"DomainName": args.DomainName,
+		"Years":      "1",
+	}
+
+	if args.EPPCode != "" {
+		params["EPPCode"] = args.EPPCode
+	}
+
+	if args.PromotionCode != "" {
+		params["PromotionCode"] = args.PromotionCode
+	}
+
+	if args.AddFreeWhoisguard != "" {
+		params["AddFreeWhoisguard"] = args.AddFreeWhoisguard
+	}
+
+	if args.WGEnabled != "" {
+		params["WGEnabled"] = args.WGEnabled
+	}
+
+	if args.IsPremiumDomain {
+		params["IsPremiumDomain"] = strconv.FormatBool(args.IsPremiumDomain)
+		params["PremiumPrice"] = args.PremiumPrice.Amount.String()
+	}
+
+	if !args.EapFee.IsZero() {
+		params["EapFee"] = args.EapFee.Amount.String()
+	}
+
+	extendedAttributesToParams(args.ExtendedAttributes, params)
+
+	_, err := ds.client.DoXML(ctx, params, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiErr error
+	if resp.Errors != nil && len(resp.Errors) > 0 {
+		errMessages := []string{}
+		for _, e := range resp.Errors {
+			errMessages = append(errMessages, fmt.Sprintf("%s (%s)", e.Message, e.Number))
+		}
+		apiErr = fmt.Errorf("%s", strings.Join(errMessages, "; "))
+	}
+
+	if resp.CommandResponse == nil {
+		return &DomainTransferResult{}, apiErr
+	}
+
+	return &resp.CommandResponse.DomainTransferCreateResult, apiErr
+}
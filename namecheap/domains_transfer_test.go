@@ -0,0 +1,77 @@
+package namecheap
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainsTransfer(t *testing.T) {
+	fakeResponse := `
+		<?xml version="1.0" encoding="utf-8"?>
+		<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+			<Errors />
+			<RequestedCommand>namecheap.domains.transfer.create</RequestedCommand>
+			<CommandResponse Type="namecheap.domains.transfer.create">
+				<DomainTransferCreateResult DomainName="domain.ca" Transfer="true" TransferID="15" StatusID="-1" OrderID="1234" TransactionID="5678" ChargedAmount="9.0200" />
+			</CommandResponse>
+		</ApiResponse>
+	`
+
+	t.Run("request_data_passing", func(t *testing.T) {
+		var sentQuery url.Values
+		mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			body, _ := ioutil.ReadAll(request.Body)
+			sentQuery, _ = url.ParseQuery(string(body))
+			_, _ = writer.Write([]byte(fakeResponse))
+		}))
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		result, err := client.Domains.Transfer(context.TODO(), DomainTransferArgs{
+			DomainName: "domain.ca",
+			EPPCode:    "EPP-CODE",
+			ExtendedAttributes: CAExtendedAttributes{
+				LegalType:         "CCT",
+				AgreementVersion:  "2.0",
+				AgreementAccepted: true,
+			},
+		})
+		if err != nil {
+			t.Fatal("Unable to transfer domain", err)
+		}
+
+		assert.Equal(t, "namecheap.domains.transfer.create", sentQuery.Get("Command"))
+		assert.Equal(t, "domain.ca", sentQuery.Get("DomainName"))
+		assert.Equal(t, "1", sentQuery.Get("Years"))
+		assert.Equal(t, "EPP-CODE", sentQuery.Get("EPPCode"))
+		assert.Equal(t, "CCT", sentQuery.Get("CIRALegalType"))
+		assert.Equal(t, "Y", sentQuery.Get("CIRAAgreementValue"))
+		assert.NotContains(t, sentQuery, "IsPremiumDomain")
+
+		assert.True(t, result.Transfer)
+		assert.Equal(t, "15", result.TransferID)
+		assert.Equal(t, MustParseMoney("9.02", DefaultCurrency), result.ChargedAmount)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		client := setupClient(nil)
+
+		_, err := client.Domains.Transfer(context.TODO(), DomainTransferArgs{
+			DomainName:         "domain.de",
+			Years:              2,
+			IsPremiumDomain:    true,
+			ExtendedAttributes: RawExtendedAttributes{"EPPCode": "other"},
+		})
+
+		assert.EqualError(t, err, `invalid ExtendedAttributes value: "EPPCode" is a parameter of the command, not an extended attribute; `+
+			`invalid Years value: 2, transfers add a single year; PremiumPrice is required for the premium domains`)
+	})
+}
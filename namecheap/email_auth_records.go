@@ -0,0 +1,676 @@
+package namecheap
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	SPFQualifierPass     = "+"
+	SPFQualifierFail     = "-"
+	SPFQualifierSoftFail = "~"
+	SPFQualifierNeutral  = "?"
+)
+
+const (
+	DMARCPolicyNone       = "none"
+	DMARCPolicyQuarantine = "quarantine"
+	DMARCPolicyReject     = "reject"
+)
+
+const (
+	DMARCAlignmentRelaxed = "r"
+	DMARCAlignmentStrict  = "s"
+)
+
+const (
+	DKIMKeyTypeRSA     = "rsa"
+	DKIMKeyTypeEd25519 = "ed25519"
+)
+
+// maxSPFLookups is the limit of the DNS querying terms a receiver evaluates for a single SPF check
+const maxSPFLookups = 10
+
+// minDKIMRSAKeyBits is the shortest RSA key the receivers verify signatures with
+const minDKIMRSAKeyBits = 1024
+
+var validSPFModifierName = regexp.MustCompile("^[a-zA-Z][a-zA-Z0-9_.-]*$")
+
+// SPFRecord is the Sender Policy Framework record of a domain, the TXT record listing the hosts allowed to send its email:
+//
+//	spf := SPFRecord{Mechanisms: []string{"mx", "include:_spf.google.com"}, All: SPFQualifierSoftFail}
+//	spf.Value() // v=spf1 mx include:_spf.google.com ~all
+type SPFRecord struct {
+	// Terms evaluated in order, e.g. mx, ip4:192.0.2.0/24 or include:_spf.google.com.
+	// A mechanism may be prefixed with a qualifier, e.g. -ip4:192.0.2.1. The modifiers other than redirect, e.g. exp=, are kept here too.
+	Mechanisms []string
+	// Qualifier of the trailing all mechanism, e.g. SPFQualifierSoftFail for ~all. If empty, then no all mechanism is written
+	All string
+	// Domain the policy is taken from when no mechanism matches, written as the redirect= modifier. Ignored when All is set
+	Redirect string
+}
+
+// ParseSPF decodes the value of an SPF record, e.g. v=spf1 mx ~all. The record isn't validated.
+// The modifiers may follow all, e.g. exp=, they're kept in the Mechanisms.
+func ParseSPF(value string) (*SPFRecord, error) {
+	terms := strings.Fields(value)
+	if len(terms) == 0 || !strings.EqualFold(terms[0], "v=spf1") {
+		return nil, fmt.Errorf("invalid SPF record: %s, it must start with v=spf1", value)
+	}
+
+	spf := &SPFRecord{}
+	allTerm := ""
+	for _, term := range terms[1:] {
+		lowerTerm := strings.ToLower(term)
+		if _, argument := splitSPFTerm(term); allTerm != "" && !strings.HasPrefix(argument, "=") {
+			return nil, fmt.Errorf("invalid SPF record: %s, the mechanisms after %s are never evaluated", value, allTerm)
+		}
+
+		switch {
+		case strings.HasPrefix(lowerTerm, "redirect="):
+			spf.Redirect = term[len("redirect="):]
+		case strings.TrimLeft(lowerTerm, "+-~?") == "all" && len(lowerTerm) <= 4:
+			allTerm = term
+			spf.All = SPFQualifierPass
+			if len(term) == 4 {
+				spf.All = term[:1]
+			}
+		default:
+			spf.Mechanisms = append(spf.Mechanisms, term)
+		}
+	}
+	return spf, nil
+}
+
+// Value encodes the record as the TXT value
+func (s SPFRecord) Value() string {
+	terms := append([]string{"v=spf1"}, s.Mechanisms...)
+	if s.Redirect != "" && s.All == "" {
+		terms = append(terms, "redirect="+s.Redirect)
+	}
+	if s.All != "" {
+		terms = append(terms, s.All+"all")
+	}
+	return strings.Join(terms, " ")
+}
+
+// HostRecord returns the TXT host record of the host name, e.g. @
+func (s SPFRecord) HostRecord(hostName string) DomainsDNSHostRecord {
+	return TXTHostRecord(hostName, s.Value())
+}
+
+func (s SPFRecord) String() string {
+	return s.Value()
+}
+
+// LookupCount returns the number of the DNS lookups the record costs: one for every include, a, mx, ptr and exists
+// mechanism, and one for redirect. The records of the included domains aren't resolved, their lookups count too.
+func (s SPFRecord) LookupCount() int {
+	count := 0
+	for _, term := range s.Mechanisms {
+		switch name, _ := splitSPFTerm(term); name {
+		case "include", "a", "mx", "ptr", "exists":
+			count++
+		}
+	}
+	if s.Redirect != "" && s.All == "" {
+		count++
+	}
+	return count
+}
+
+// Validate checks the syntax of the terms, the qualifier of all, and the DNS lookup count limit of 10.
+// The returned error is a *ValidationError listing all the issues.
+func (s SPFRecord) Validate() error {
+	issues := &ValidationError{}
+
+	for i, term := range s.Mechanisms {
+		if err := validateSPFTerm(term); err != nil {
+			field := fmt.Sprintf("Mechanisms[%d]", i)
+			issues.add(field, fmt.Sprintf("invalid %s value: %s, %v", field, term, err))
+		}
+	}
+
+	switch s.All {
+	case "", SPFQualifierPass, SPFQualifierFail, SPFQualifierSoftFail, SPFQualifierNeutral:
+	default:
+		issues.add("All", fmt.Sprintf("invalid All value: %s, allowed values are +, -, ~, ?", s.All))
+	}
+
+	if s.Redirect != "" && !isValidSPFDomain(s.Redirect) {
+		issues.add("Redirect", fmt.Sprintf("invalid Redirect value: %s, domain is invalid", s.Redirect))
+	}
+
+	if count := s.LookupCount(); count > maxSPFLookups {
+		issues.add("Mechanisms", fmt.Sprintf("SPF record needs %d DNS lookups, maximum is %d", count, maxSPFLookups))
+	}
+
+	return issues.errorOrNil()
+}
+
+// splitSPFTerm returns the lower case name of the mechanism or modifier without the qualifier, and its argument
+// with the separator, e.g. :domain.net/24 or =domain.net
+func splitSPFTerm(term string) (string, string) {
+	term = strings.TrimLeft(term, "+-~?")
+	end := strings.IndexAny(term, ":/=")
+	if end == -1 {
+		return strings.ToLower(term), ""
+	}
+	return strings.ToLower(term[:end]), term[end:]
+}
+
+func validateSPFTerm(term string) error {
+	name, argument := splitSPFTerm(term)
+
+	if strings.HasPrefix(argument, "=") {
+		if strings.IndexAny(term, "+-~?") == 0 {
+			return fmt.Errorf("modifier can't have a qualifier")
+		}
+		if !validSPFModifierName.MatchString(name) {
+			return fmt.Errorf("modifier name is invalid")
+		}
+		if name == "exp" && !isValidSPFDomain(argument[1:]) {
+			return fmt.Errorf("domain is invalid")
+		}
+		return nil
+	}
+
+	switch name {
+	case "all":
+		return fmt.Errorf("use All for the all mechanism")
+	case "include", "exists":
+		if !strings.HasPrefix(argument, ":") || !isValidSPFDomain(argument[1:]) {
+			return fmt.Errorf("%s requires a valid domain, e.g. %s:domain.net", name, name)
+		}
+	case "a", "mx":
+		domain, cidr := argument, ""
+		if index := strings.Index(argument, "/"); index != -1 {
+			domain, cidr = argument[:index], argument[index:]
+		}
+		if domain != "" && (!strings.HasPrefix(domain, ":") || !isValidSPFDomain(domain[1:])) {
+			return fmt.Errorf("domain is invalid")
+		}
+		if cidr != "" && !isValidSPFDualCIDR(cidr) {
+			return fmt.Errorf("CIDR prefix length is invalid")
+		}
+	case "ptr":
+		if argument != "" && (!strings.HasPrefix(argument, ":") || !isValidSPFDomain(argument[1:])) {
+			return fmt.Errorf("domain is invalid")
+		}
+	case "ip4", "ip6":
+		if !strings.HasPrefix(argument, ":") {
+			return fmt.Errorf("%s requires an address, e.g. %s:192.0.2.0/24 or %s:2001:db8::/32", name, name, name)
+		}
+		address := argument[1:]
+		if !strings.Contains(address, "/") && name == "ip4" {
+			address += "/32"
+		} else if !strings.Contains(address, "/") {
+			address += "/128"
+		}
+		ip, _, err := net.ParseCIDR(address)
+		if err != nil || (ip.To4() != nil) != (name == "ip4") {
+			return fmt.Errorf("%s address is invalid", name)
+		}
+	default:
+		return fmt.Errorf("unknown mechanism %s", name)
+	}
+	return nil
+}
+
+// isValidSPFDomain checks the domain of a term, the macros, e.g. %{i}._spf.domain.net, aren't expanded
+func isValidSPFDomain(domain string) bool {
+	if strings.Contains(domain, "%") {
+		return domain != ""
+	}
+	return isValidRecordTarget(domain)
+}
+
+// isValidSPFDualCIDR checks the prefix lengths of a and mx, e.g. /24, //64 or /24//64
+func isValidSPFDualCIDR(cidr string) bool {
+	ip4, ip6 := cidr, ""
+	if index := strings.Index(cidr, "//"); index != -1 {
+		ip4, ip6 = cidr[:index], cidr[index+1:]
+	}
+	for _, prefix := range []struct {
+		value string
+		max   int
+	}{{ip4, 32}, {ip6, 128}} {
+		if prefix.value == "" {
+			continue
+		}
+		length, err := strconv.Atoi(strings.TrimPrefix(prefix.value, "/"))
+		if !strings.HasPrefix(prefix.value, "/") || err != nil || length < 0 || length > prefix.max {
+			return false
+		}
+	}
+	return true
+}
+
+// DMARCRecord is the Domain-based Message Authentication, Reporting and Conformance policy of a domain:
+//
+//	dmarc := DMARCRecord{Policy: DMARCPolicyReject, AggregateReportURIs: []string{"mailto:dmarc@domain.net"}}
+//	dmarc.HostRecord("@") // _dmarc TXT v=DMARC1; p=reject; rua=mailto:dmarc@domain.net
+type DMARCRecord struct {
+	// Policy for the messages failing the check, possible values are none, quarantine or reject. Required
+	Policy string
+	// Policy for the subdomains, if empty, then Policy applies
+	SubdomainPolicy string
+	// Policy for the non-existent subdomains, np tag. If empty, then SubdomainPolicy applies
+	NonexistentSubdomainPolicy string
+	// Percentage of the failing messages the policy applies to, between 0 and 100. If nil, then 100
+	Percent *uint8
+	// URIs the aggregate reports are sent to, rua tag, e.g. mailto:dmarc@domain.net or https://reports.domain.net
+	AggregateReportURIs []string
+	// URIs the failure reports are sent to, ruf tag
+	FailureReportURIs []string
+	// DKIM and SPF identifier alignment modes, r for relaxed or s for strict. If empty, then relaxed
+	DKIMAlignment string
+	SPFAlignment  string
+	// Failure reporting options separated by colons, e.g. 1 or d:s. If empty, then 0
+	FailureOptions string
+	// Interval between the aggregate reports in seconds. If 0, then 86400
+	ReportInterval int
+}
+
+// ParseDMARC decodes the value of a DMARC record, e.g. v=DMARC1; p=reject. The record isn't validated,
+// but the unknown tags are rejected, since the receivers silently ignore them, e.g. a misspelled rua.
+func ParseDMARC(value string) (*DMARCRecord, error) {
+	tags, err := parseTagList(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DMARC record: %s, %v", value, err)
+	}
+	if len(tags) == 0 || tags[0][0] != "v" || tags[0][1] != "DMARC1" {
+		return nil, fmt.Errorf("invalid DMARC record: %s, it must start with v=DMARC1", value)
+	}
+
+	dmarc := &DMARCRecord{}
+	for _, tag := range tags[1:] {
+		name, tagValue := tag[0], tag[1]
+		switch name {
+		case "p":
+			dmarc.Policy = tagValue
+		case "sp":
+			dmarc.SubdomainPolicy = tagValue
+		case "np":
+			dmarc.NonexistentSubdomainPolicy = tagValue
+		case "pct":
+			percent, err := strconv.ParseUint(tagValue, 10, 8)
+			if err != nil {
+				return nil, fmt.Errorf("invalid DMARC record: %s, pct must be a number between 0 and 100", value)
+			}
+			dmarc.Percent = UInt8(uint8(percent))
+		case "rua":
+			dmarc.AggregateReportURIs = splitDMARCURIs(tagValue)
+		case "ruf":
+			dmarc.FailureReportURIs = splitDMARCURIs(tagValue)
+		case "adkim":
+			dmarc.DKIMAlignment = tagValue
+		case "aspf":
+			dmarc.SPFAlignment = tagValue
+		case "fo":
+			dmarc.FailureOptions = tagValue
+		case "ri":
+			interval, err := strconv.ParseUint(tagValue, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid DMARC record: %s, ri must be a number of seconds", value)
+			}
+			dmarc.ReportInterval = int(interval)
+		default:
+			return nil, fmt.Errorf("invalid DMARC record: %s, unknown tag %s", value, name)
+		}
+	}
+	return dmarc, nil
+}
+
+// Value encodes the record as the TXT value, the tags are written in the conventional order
+func (d DMARCRecord) Value() string {
+	tags := []string{"v=DMARC1", "p=" + d.Policy}
+	if d.SubdomainPolicy != "" {
+		tags = append(tags, "sp="+d.SubdomainPolicy)
+	}
+	if d.NonexistentSubdomainPolicy != "" {
+		tags = append(tags, "np="+d.NonexistentSubdomainPolicy)
+	}
+	if d.Percent != nil {
+		tags = append(tags, fmt.Sprintf("pct=%d", *d.Percent))
+	}
+	if len(d.AggregateReportURIs) > 0 {
+		tags = append(tags, "rua="+strings.Join(d.AggregateReportURIs, ","))
+	}
+	if len(d.FailureReportURIs) > 0 {
+		tags = append(tags, "ruf="+strings.Join(d.FailureReportURIs, ","))
+	}
+	if d.DKIMAlignment != "" {
+		tags = append(tags, "adkim="+d.DKIMAlignment)
+	}
+	if d.SPFAlignment != "" {
+		tags = append(tags, "aspf="+d.SPFAlignment)
+	}
+	if d.FailureOptions != "" {
+		tags = append(tags, "fo="+d.FailureOptions)
+	}
+	if d.ReportInterval != 0 {
+		tags = append(tags, fmt.Sprintf("ri=%d", d.ReportInterval))
+	}
+	return strings.Join(tags, "; ")
+}
+
+// HostRecord returns the TXT host record of the policy for the host name, e.g. _dmarc for @ or _dmarc.shop for shop
+func (d DMARCRecord) HostRecord(hostName string) DomainsDNSHostRecord {
+	return TXTHostRecord(prefixHostName("_dmarc", hostName), d.Value())
+}
+
+func (d DMARCRecord) String() string {
+	return d.Value()
+}
+
+// Validate checks the policies, the percentage, the report URIs and the alignment modes.
+// The returned error is a *ValidationError listing all the issues.
+func (d DMARCRecord) Validate() error {
+	issues := &ValidationError{}
+
+	if d.Policy == "" {
+		issues.add("Policy", "Policy is required")
+	} else if !isDMARCPolicy(d.Policy) {
+		issues.add("Policy", fmt.Sprintf("invalid Policy value: %s, allowed values are none, quarantine, reject", d.Policy))
+	}
+	if d.SubdomainPolicy != "" && !isDMARCPolicy(d.SubdomainPolicy) {
+		issues.add("SubdomainPolicy", fmt.Sprintf("invalid SubdomainPolicy value: %s, allowed values are none, quarantine, reject", d.SubdomainPolicy))
+	}
+	if d.NonexistentSubdomainPolicy != "" && !isDMARCPolicy(d.NonexistentSubdomainPolicy) {
+		issues.add("NonexistentSubdomainPolicy", fmt.Sprintf("invalid NonexistentSubdomainPolicy value: %s, allowed values are none, quarantine, reject", d.NonexistentSubdomainPolicy))
+	}
+
+	if d.Percent != nil && *d.Percent > 100 {
+		issues.add("Percent", fmt.Sprintf("invalid Percent value: %d, maximum value is 100", *d.Percent))
+	}
+
+	for i, uri := range d.AggregateReportURIs {
+		if err := validateDMARCURI(uri); err != nil {
+			field := fmt.Sprintf("AggregateReportURIs[%d]", i)
+			issues.add(field, fmt.Sprintf("invalid %s value: %s, %v", field, uri, err))
+		}
+	}
+	for i, uri := range d.FailureReportURIs {
+		if err := validateDMARCURI(uri); err != nil {
+			field := fmt.Sprintf("FailureReportURIs[%d]", i)
+			issues.add(field, fmt.Sprintf("invalid %s value: %s, %v", field, uri, err))
+		}
+	}
+
+	if !isDMARCAlignment(d.DKIMAlignment) {
+		issues.add("DKIMAlignment", fmt.Sprintf("invalid DKIMAlignment value: %s, allowed values are r, s", d.DKIMAlignment))
+	}
+	if !isDMARCAlignment(d.SPFAlignment) {
+		issues.add("SPFAlignment", fmt.Sprintf("invalid SPFAlignment value: %s, allowed values are r, s", d.SPFAlignment))
+	}
+
+	if d.FailureOptions != "" {
+		for _, option := range strings.Split(d.FailureOptions, ":") {
+			if option != "0" && option != "1" && option != "d" && option != "s" {
+				issues.add("FailureOptions", fmt.Sprintf("invalid FailureOptions value: %s, allowed options are 0, 1, d, s", d.FailureOptions))
+				break
+			}
+		}
+	}
+
+	return issues.errorOrNil()
+}
+
+func isDMARCPolicy(policy string) bool {
+	return policy == DMARCPolicyNone || policy == DMARCPolicyQuarantine || policy == DMARCPolicyReject
+}
+
+func isDMARCAlignment(alignment string) bool {
+	return alignment == "" || alignment == DMARCAlignmentRelaxed || alignment == DMARCAlignmentStrict
+}
+
+func splitDMARCURIs(value string) []string {
+	uris := []string{}
+	for _, uri := range strings.Split(value, ",") {
+		if uri = strings.TrimSpace(uri); uri != "" {
+			uris = append(uris, uri)
+		}
+	}
+	return uris
+}
+
+// validateDMARCURI checks a report URI with an optional size limit: a mailto: URI, e.g. mailto:dmarc@domain.net!10m,
+// or an https: one, e.g. https://reports.domain.net/dmarc
+func validateDMARCURI(uri string) error {
+	lowerURI := strings.ToLower(uri)
+	if strings.HasPrefix(lowerURI, "https://") || strings.HasPrefix(lowerURI, "http://") {
+		parsedURI, err := url.Parse(uri)
+		if err != nil || parsedURI.Host == "" {
+			return fmt.Errorf("https: URI must contain a host")
+		}
+		return nil
+	}
+	if !strings.HasPrefix(lowerURI, "mailto:") {
+		return fmt.Errorf("report URI must start with mailto: or https:")
+	}
+	address := uri[len("mailto:"):]
+	if index := strings.LastIndex(address, "!"); index != -1 {
+		size := strings.TrimRight(address[index+1:], "kmgtKMGT")
+		if size == "" || !isDigits(size) {
+			return fmt.Errorf("size limit is invalid")
+		}
+		address = address[:index]
+	}
+	at := strings.LastIndex(address, "@")
+	if at <= 0 || !isValidRecordTarget(address[at+1:]) {
+		return fmt.Errorf("mailto: URI must contain an email address")
+	}
+	return nil
+}
+
+// DKIMRecord is the DomainKeys Identified Mail public key a domain signs its email with:
+//
+//	dkim := DKIMRecord{Selector: "mail", PublicKey: "MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEA..."}
+//	dkim.HostRecord("@") // mail._domainkey TXT v=DKIM1; k=rsa; p=MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEA...
+type DKIMRecord struct {
+	// Selector the signatures refer to the key by, e.g. google or s1. Not a part of the value, the record is written to selector._domainkey
+	Selector string
+	// Possible values are rsa or ed25519. If empty, then rsa is written
+	KeyType string
+	// Base64 encoded public key, the whitespace is removed. An empty key revokes the selector
+	PublicKey string
+	// Hash algorithms allowed, separated by colons, e.g. sha256. If empty, then all of them
+	HashAlgorithms string
+	// Service types the key applies to, separated by colons, e.g. email. If empty, then all of them
+	ServiceTypes string
+	// Flags separated by colons, y for testing, s for no subdomains
+	Flags string
+	// Notes for the administrators, n tag, not interpreted by the receivers
+	Notes string
+}
+
+// ParseDKIM decodes the value of a DKIM record, e.g. v=DKIM1; k=rsa; p=MIIB..., the Selector is left empty.
+// The record isn't validated, and the unknown tags are ignored, as they're by the receivers.
+func ParseDKIM(value string) (*DKIMRecord, error) {
+	tags, err := parseTagList(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DKIM record: %s, %v", value, err)
+	}
+
+	dkim := &DKIMRecord{}
+	hasPublicKey := false
+	for i, tag := range tags {
+		name, tagValue := tag[0], tag[1]
+		switch name {
+		case "v":
+			if i != 0 || tagValue != "DKIM1" {
+				return nil, fmt.Errorf("invalid DKIM record: %s, v=DKIM1 must be the first tag", value)
+			}
+		case "k":
+			dkim.KeyType = tagValue
+		case "p":
+			dkim.PublicKey = removeWhitespace(tagValue)
+			hasPublicKey = true
+		case "h":
+			dkim.HashAlgorithms = tagValue
+		case "s":
+			dkim.ServiceTypes = tagValue
+		case "t":
+			dkim.Flags = tagValue
+		case "n":
+			dkim.Notes = tagValue
+		}
+	}
+	if !hasPublicKey {
+		return nil, fmt.Errorf("invalid DKIM record: %s, p tag is required", value)
+	}
+	return dkim, nil
+}
+
+// Value encodes the record as the TXT value. The 2048 bits RSA keys are longer than 255 characters,
+// see TXTHostRecord on how the long values are written.
+func (d DKIMRecord) Value() string {
+	keyType := d.KeyType
+	if keyType == "" {
+		keyType = DKIMKeyTypeRSA
+	}
+
+	tags := []string{"v=DKIM1", "k=" + keyType}
+	if d.HashAlgorithms != "" {
+		tags = append(tags, "h="+d.HashAlgorithms)
+	}
+	if d.ServiceTypes != "" {
+		tags = append(tags, "s="+d.ServiceTypes)
+	}
+	if d.Flags != "" {
+		tags = append(tags, "t="+d.Flags)
+	}
+	if d.Notes != "" {
+		tags = append(tags, "n="+d.Notes)
+	}
+	tags = append(tags, "p="+removeWhitespace(d.PublicKey))
+	return strings.Join(tags, "; ")
+}
+
+// HostRecord returns the TXT host record of the key for the host name, e.g. mail._domainkey for @
+// or mail._domainkey.shop for shop
+func (d DKIMRecord) HostRecord(hostName string) DomainsDNSHostRecord {
+	return TXTHostRecord(prefixHostName(d.Selector+"._domainkey", hostName), d.Value())
+}
+
+func (d DKIMRecord) String() string {
+	return d.Value()
+}
+
+// Validate checks the selector, the key type, the public key encoding and the RSA key length.
+// The returned error is a *ValidationError listing all the issues.
+func (d DKIMRecord) Validate() error {
+	issues := &ValidationError{}
+
+	if d.Selector == "" {
+		issues.add("Selector", "Selector is required")
+	} else if !isValidDNSName(d.Selector) {
+		issues.add("Selector", fmt.Sprintf("invalid Selector value: %s, it must be a valid host name", d.Selector))
+	}
+
+	switch d.KeyType {
+	case "", DKIMKeyTypeRSA, DKIMKeyTypeEd25519:
+		if err := validateDKIMPublicKey(d.KeyType, removeWhitespace(d.PublicKey)); err != nil {
+			issues.add("PublicKey", fmt.Sprintf("invalid PublicKey value: %v", err))
+		}
+	default:
+		issues.add("KeyType", fmt.Sprintf("invalid KeyType value: %s, allowed values are rsa, ed25519", d.KeyType))
+	}
+
+	if d.HashAlgorithms != "" {
+		for _, algorithm := range strings.Split(d.HashAlgorithms, ":") {
+			if algorithm != "sha1" && algorithm != "sha256" {
+				issues.add("HashAlgorithms", fmt.Sprintf("invalid HashAlgorithms value: %s, allowed algorithms are sha1, sha256", d.HashAlgorithms))
+				break
+			}
+		}
+	}
+
+	if d.Flags != "" {
+		for _, flag := range strings.Split(d.Flags, ":") {
+			if flag != "y" && flag != "s" {
+				issues.add("Flags", fmt.Sprintf("invalid Flags value: %s, allowed flags are y, s", d.Flags))
+				break
+			}
+		}
+	}
+
+	return issues.errorOrNil()
+}
+
+// validateDKIMPublicKey checks the base64 encoded key: a SubjectPublicKeyInfo or PKCS #1 RSA key of at least
+// 1024 bits, or a raw 32 bytes Ed25519 key. An empty key is a revoked one.
+func validateDKIMPublicKey(keyType string, publicKey string) error {
+	if publicKey == "" {
+		return nil
+	}
+
+	der, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil {
+		return fmt.Errorf("public key isn't valid base64")
+	}
+
+	if keyType == DKIMKeyTypeEd25519 {
+		if len(der) != 32 {
+			return fmt.Errorf("ed25519 public key must be 32 bytes, got %d", len(der))
+		}
+		return nil
+	}
+
+	var rsaKey *rsa.PublicKey
+	if key, err := x509.ParsePKIXPublicKey(der); err == nil {
+		rsaKey, _ = key.(*rsa.PublicKey)
+	} else if key, err := x509.ParsePKCS1PublicKey(der); err == nil {
+		rsaKey = key
+	}
+	if rsaKey == nil {
+		return fmt.Errorf("public key isn't an RSA key")
+	}
+	if bits := rsaKey.N.BitLen(); bits < minDKIMRSAKeyBits {
+		return fmt.Errorf("RSA key is %d bits long, minimum is %d", bits, minDKIMRSAKeyBits)
+	}
+	return nil
+}
+
+// parseTagList splits a DMARC or DKIM value into the name and value pairs, e.g. v=DMARC1; p=reject
+func parseTagList(value string) ([][2]string, error) {
+	tags := [][2]string{}
+	seen := map[string]bool{}
+	for _, tag := range strings.Split(value, ";") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		nameValue := strings.SplitN(tag, "=", 2)
+		if len(nameValue) != 2 || strings.TrimSpace(nameValue[0]) == "" {
+			return nil, fmt.Errorf("tag %s must be name=value", tag)
+		}
+		name := strings.TrimSpace(nameValue[0])
+		if seen[name] {
+			return nil, fmt.Errorf("tag %s is repeated", name)
+		}
+		seen[name] = true
+		tags = append(tags, [2]string{name, strings.TrimSpace(nameValue[1])})
+	}
+	return tags, nil
+}
+
+// prefixHostName returns the host name of a service record, e.g. _dmarc for @ and _dmarc.shop for shop
+func prefixHostName(prefix string, hostName string) string {
+	if hostName == "" || hostName == "@" {
+		return prefix
+	}
+	return prefix + "." + hostName
+}
+
+func removeWhitespace(value string) string {
+	return strings.Join(strings.Fields(value), "")
+}
@@ -0,0 +1,465 @@
+package namecheap
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"math/big"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRSAPublicKey returns the base64 encoded SubjectPublicKeyInfo of an RSA key of the length, it's only good for the validation
+func fakeRSAPublicKey(bits int) string {
+	modulus := new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+	der, _ := x509.MarshalPKIXPublicKey(&rsa.PublicKey{N: modulus.Add(modulus, big.NewInt(1)), E: 65537})
+	return base64.StdEncoding.EncodeToString(der)
+}
+
+func TestSPFRecord(t *testing.T) {
+	t.Run("value", func(t *testing.T) {
+		spf := SPFRecord{Mechanisms: []string{"mx", "include:_spf.google.com", "ip4:192.0.2.0/24"}, All: SPFQualifierSoftFail}
+
+		assert.Equal(t, "v=spf1 mx include:_spf.google.com ip4:192.0.2.0/24 ~all", spf.Value())
+		assert.Equal(t, spf.Value(), spf.String())
+		assert.Equal(t, DomainsDNSHostRecord{HostName: "@", RecordType: RecordTypeTXT, Address: spf.Value()}, spf.HostRecord("@"))
+	})
+
+	t.Run("value_redirect", func(t *testing.T) {
+		assert.Equal(t, "v=spf1 redirect=_spf.domain.net", SPFRecord{Redirect: "_spf.domain.net"}.Value())
+		assert.Equal(t, "v=spf1 -all", SPFRecord{Redirect: "_spf.domain.net", All: SPFQualifierFail}.Value())
+	})
+
+	t.Run("parse", func(t *testing.T) {
+		spf, err := ParseSPF("v=spf1 a:mail.domain.net/24 -ip6:2001:db8::/32 exp=explain.domain.net ?all")
+		if err != nil {
+			t.Fatal("Unable to parse SPF record", err)
+		}
+
+		assert.Equal(t, SPFRecord{Mechanisms: []string{"a:mail.domain.net/24", "-ip6:2001:db8::/32", "exp=explain.domain.net"}, All: SPFQualifierNeutral}, *spf)
+	})
+
+	t.Run("parse_unqualified_all_and_redirect", func(t *testing.T) {
+		spf, err := ParseSPF("V=SPF1 redirect=_spf.domain.net")
+		if err != nil {
+			t.Fatal("Unable to parse SPF record", err)
+		}
+		assert.Equal(t, SPFRecord{Redirect: "_spf.domain.net"}, *spf)
+
+		spf, err = ParseSPF("v=spf1 all")
+		if err != nil {
+			t.Fatal("Unable to parse SPF record", err)
+		}
+		assert.Equal(t, SPFRecord{All: SPFQualifierPass}, *spf)
+	})
+
+	t.Run("parse_errors", func(t *testing.T) {
+		_, err := ParseSPF("spf1 -all")
+		assert.EqualError(t, err, "invalid SPF record: spf1 -all, it must start with v=spf1")
+
+		_, err = ParseSPF("v=spf1 -all mx")
+		assert.EqualError(t, err, "invalid SPF record: v=spf1 -all mx, the mechanisms after -all are never evaluated")
+	})
+
+	t.Run("parse_modifier_after_all", func(t *testing.T) {
+		spf, err := ParseSPF("v=spf1 mx ~all exp=explain.domain.net")
+		if err != nil {
+			t.Fatal("Unable to parse SPF record", err)
+		}
+
+		assert.Equal(t, SPFRecord{Mechanisms: []string{"mx", "exp=explain.domain.net"}, All: SPFQualifierSoftFail}, *spf)
+		assert.NoError(t, spf.Validate())
+	})
+
+	t.Run("lookup_count", func(t *testing.T) {
+		spf := SPFRecord{Mechanisms: []string{"a", "mx", "ip4:192.0.2.1", "include:a.net", "exists:%{i}.b.net", "ptr"}, Redirect: "c.net"}
+
+		assert.Equal(t, 6, spf.LookupCount())
+	})
+
+	var validCases = map[string]SPFRecord{
+		"empty":        {All: SPFQualifierFail},
+		"mechanisms":   {Mechanisms: []string{"a", "a/24", "a:domain.net//64", "mx:domain.net/24//64", "ptr", "ip4:192.0.2.1", "ip6:2001:db8::1", "include:_spf.google.com"}, All: SPFQualifierSoftFail},
+		"macro":        {Mechanisms: []string{"exists:%{i}._spf.domain.net"}, All: SPFQualifierFail},
+		"modifiers":    {Mechanisms: []string{"exp=explain.domain.net", "x-custom=1"}, Redirect: "_spf.domain.net"},
+		"ten_lookups":  {Mechanisms: strings.Split(strings.Repeat("mx ", 10), " ")[:10]},
+		"qualified_ip": {Mechanisms: []string{"-ip4:192.0.2.1", "+ip4:192.0.2.2"}},
+	}
+
+	for name, spf := range validCases {
+		t.Run("valid_"+name, func(t *testing.T) {
+			assert.NoError(t, spf.Validate())
+		})
+	}
+
+	var errorCases = map[string]struct {
+		Record        SPFRecord
+		ExpectedError string
+	}{
+		"unknown_mechanism": {
+			Record:        SPFRecord{Mechanisms: []string{"inclde:_spf.google.com"}},
+			ExpectedError: "invalid Mechanisms[0] value: inclde:_spf.google.com, unknown mechanism inclde",
+		},
+		"include_without_domain": {
+			Record:        SPFRecord{Mechanisms: []string{"include"}},
+			ExpectedError: "invalid Mechanisms[0] value: include, include requires a valid domain, e.g. include:domain.net",
+		},
+		"invalid_ip4": {
+			Record:        SPFRecord{Mechanisms: []string{"ip4:192.0.2.256"}},
+			ExpectedError: "invalid Mechanisms[0] value: ip4:192.0.2.256, ip4 address is invalid",
+		},
+		"ip6_address_in_ip4": {
+			Record:        SPFRecord{Mechanisms: []string{"ip4:2001:db8::1"}},
+			ExpectedError: "invalid Mechanisms[0] value: ip4:2001:db8::1, ip4 address is invalid",
+		},
+		"invalid_cidr": {
+			Record:        SPFRecord{Mechanisms: []string{"a/33"}},
+			ExpectedError: "invalid Mechanisms[0] value: a/33, CIDR prefix length is invalid",
+		},
+		"all_in_mechanisms": {
+			Record:        SPFRecord{Mechanisms: []string{"-all"}},
+			ExpectedError: "invalid Mechanisms[0] value: -all, use All for the all mechanism",
+		},
+		"qualified_modifier": {
+			Record:        SPFRecord{Mechanisms: []string{"-exp=explain.domain.net"}},
+			ExpectedError: "invalid Mechanisms[0] value: -exp=explain.domain.net, modifier can't have a qualifier",
+		},
+		"invalid_all": {
+			Record:        SPFRecord{All: "!"},
+			ExpectedError: "invalid All value: !, allowed values are +, -, ~, ?",
+		},
+		"invalid_redirect": {
+			Record:        SPFRecord{Redirect: "spf domain"},
+			ExpectedError: "invalid Redirect value: spf domain, domain is invalid",
+		},
+		"too_many_lookups": {
+			Record: SPFRecord{
+				Mechanisms: []string{"a", "mx", "include:a.net", "include:b.net", "include:c.net", "include:d.net", "include:e.net", "include:f.net", "include:g.net", "include:h.net"},
+				Redirect:   "i.net",
+			},
+			ExpectedError: "SPF record needs 11 DNS lookups, maximum is 10",
+		},
+		"several_issues": {
+			Record:        SPFRecord{Mechanisms: []string{"mx", "ip6:192.0.2.1", "a:-"}, All: "x"},
+			ExpectedError: "invalid Mechanisms[1] value: ip6:192.0.2.1, ip6 address is invalid; invalid Mechanisms[2] value: a:-, domain is invalid; invalid All value: x, allowed values are +, -, ~, ?",
+		},
+	}
+
+	for name, c := range errorCases {
+		t.Run(name, func(t *testing.T) {
+			assert.EqualError(t, c.Record.Validate(), c.ExpectedError)
+		})
+	}
+}
+
+func TestDMARCRecord(t *testing.T) {
+	t.Run("value", func(t *testing.T) {
+		dmarc := DMARCRecord{
+			Policy:              DMARCPolicyQuarantine,
+			SubdomainPolicy:     DMARCPolicyReject,
+			Percent:             UInt8(50),
+			AggregateReportURIs: []string{"mailto:dmarc@domain.net", "mailto:reports@vendor.net!10m"},
+			FailureReportURIs:   []string{"mailto:forensic@domain.net"},
+			DKIMAlignment:       DMARCAlignmentStrict,
+			SPFAlignment:        DMARCAlignmentRelaxed,
+			FailureOptions:      "1",
+			ReportInterval:      3600,
+		}
+
+		expected := "v=DMARC1; p=quarantine; sp=reject; pct=50; rua=mailto:dmarc@domain.net,mailto:reports@vendor.net!10m; " +
+			"ruf=mailto:forensic@domain.net; adkim=s; aspf=r; fo=1; ri=3600"
+		assert.Equal(t, expected, dmarc.Value())
+		assert.Equal(t, expected, dmarc.String())
+		assert.NoError(t, dmarc.Validate())
+	})
+
+	t.Run("host_record", func(t *testing.T) {
+		dmarc := DMARCRecord{Policy: DMARCPolicyNone}
+
+		assert.Equal(t, DomainsDNSHostRecord{HostName: "_dmarc", RecordType: RecordTypeTXT, Address: "v=DMARC1; p=none"}, dmarc.HostRecord("@"))
+		assert.Equal(t, "_dmarc.shop", dmarc.HostRecord("shop").HostName)
+	})
+
+	t.Run("parse", func(t *testing.T) {
+		dmarc, err := ParseDMARC("v=DMARC1;p=reject; rua=mailto:a@domain.net, mailto:b@domain.net ;pct=100; ri=86400;")
+		if err != nil {
+			t.Fatal("Unable to parse DMARC record", err)
+		}
+
+		assert.Equal(t, DMARCRecord{
+			Policy:              DMARCPolicyReject,
+			Percent:             UInt8(100),
+			AggregateReportURIs: []string{"mailto:a@domain.net", "mailto:b@domain.net"},
+			ReportInterval:      86400,
+		}, *dmarc)
+	})
+
+	t.Run("round_trip", func(t *testing.T) {
+		value := "v=DMARC1; p=none; sp=quarantine; np=reject; pct=25; rua=mailto:dmarc@domain.net,https://reports.domain.net/dmarc; adkim=r; aspf=s; fo=d:s"
+
+		dmarc, err := ParseDMARC(value)
+		if err != nil {
+			t.Fatal("Unable to parse DMARC record", err)
+		}
+
+		assert.Equal(t, value, dmarc.Value())
+	})
+
+	var parseErrorCases = map[string]struct {
+		Value         string
+		ExpectedError string
+	}{
+		"missing_version": {
+			Value:         "p=reject",
+			ExpectedError: "invalid DMARC record: p=reject, it must start with v=DMARC1",
+		},
+		"misspelled_tag": {
+			Value:         "v=DMARC1; p=reject; rau=mailto:dmarc@domain.net",
+			ExpectedError: "invalid DMARC record: v=DMARC1; p=reject; rau=mailto:dmarc@domain.net, unknown tag rau",
+		},
+		"repeated_tag": {
+			Value:         "v=DMARC1; p=reject; p=none",
+			ExpectedError: "invalid DMARC record: v=DMARC1; p=reject; p=none, tag p is repeated",
+		},
+		"invalid_pct": {
+			Value:         "v=DMARC1; p=reject; pct=half",
+			ExpectedError: "invalid DMARC record: v=DMARC1; p=reject; pct=half, pct must be a number between 0 and 100",
+		},
+		"tag_without_value": {
+			Value:         "v=DMARC1; p",
+			ExpectedError: "invalid DMARC record: v=DMARC1; p, tag p must be name=value",
+		},
+	}
+
+	for name, c := range parseErrorCases {
+		t.Run("parse_"+name, func(t *testing.T) {
+			_, err := ParseDMARC(c.Value)
+
+			assert.EqualError(t, err, c.ExpectedError)
+		})
+	}
+
+	var errorCases = map[string]struct {
+		Record         DMARCRecord
+		ExpectedError  string
+		ExpectedFields []string
+	}{
+		"missing_policy": {
+			Record:         DMARCRecord{},
+			ExpectedError:  "Policy is required",
+			ExpectedFields: []string{"Policy"},
+		},
+		"invalid_policies": {
+			Record:         DMARCRecord{Policy: "rejected", SubdomainPolicy: "Reject", NonexistentSubdomainPolicy: "drop"},
+			ExpectedError:  "invalid Policy value: rejected, allowed values are none, quarantine, reject; invalid SubdomainPolicy value: Reject, allowed values are none, quarantine, reject; invalid NonexistentSubdomainPolicy value: drop, allowed values are none, quarantine, reject",
+			ExpectedFields: []string{"Policy", "SubdomainPolicy", "NonexistentSubdomainPolicy"},
+		},
+		"invalid_percent": {
+			Record:         DMARCRecord{Policy: DMARCPolicyReject, Percent: UInt8(101)},
+			ExpectedError:  "invalid Percent value: 101, maximum value is 100",
+			ExpectedFields: []string{"Percent"},
+		},
+		"invalid_report_uris": {
+			Record: DMARCRecord{
+				Policy:              DMARCPolicyReject,
+				AggregateReportURIs: []string{"dmarc@domain.net", "mailto:dmarc@domain.net!big", "https:///dmarc"},
+				FailureReportURIs:   []string{"mailto:domain.net"},
+			},
+			ExpectedError: "invalid AggregateReportURIs[0] value: dmarc@domain.net, report URI must start with mailto: or https:; " +
+				"invalid AggregateReportURIs[1] value: mailto:dmarc@domain.net!big, size limit is invalid; " +
+				"invalid AggregateReportURIs[2] value: https:///dmarc, https: URI must contain a host; " +
+				"invalid FailureReportURIs[0] value: mailto:domain.net, mailto: URI must contain an email address",
+			ExpectedFields: []string{"AggregateReportURIs[0]", "AggregateReportURIs[1]", "AggregateReportURIs[2]", "FailureReportURIs[0]"},
+		},
+		"invalid_alignments_and_options": {
+			Record:         DMARCRecord{Policy: DMARCPolicyReject, DKIMAlignment: "strict", SPFAlignment: "R", FailureOptions: "0:x"},
+			ExpectedError:  "invalid DKIMAlignment value: strict, allowed values are r, s; invalid SPFAlignment value: R, allowed values are r, s; invalid FailureOptions value: 0:x, allowed options are 0, 1, d, s",
+			ExpectedFields: []string{"DKIMAlignment", "SPFAlignment", "FailureOptions"},
+		},
+	}
+
+	for name, c := range errorCases {
+		t.Run(name, func(t *testing.T) {
+			err := c.Record.Validate()
+
+			assert.EqualError(t, err, c.ExpectedError)
+			if validationErr, ok := err.(*ValidationError); assert.True(t, ok) {
+				assert.Equal(t, c.ExpectedFields, validationErr.Fields())
+			}
+		})
+	}
+}
+
+func TestDKIMRecord(t *testing.T) {
+	publicKey := fakeRSAPublicKey(2048)
+
+	t.Run("value", func(t *testing.T) {
+		dkim := DKIMRecord{Selector: "mail", PublicKey: publicKey[:100] + "\n  " + publicKey[100:]}
+
+		assert.Equal(t, "v=DKIM1; k=rsa; p="+publicKey, dkim.Value())
+		assert.Equal(t, dkim.Value(), dkim.String())
+		assert.True(t, len(dkim.Value()) > maxTXTStringLength)
+		assert.NoError(t, dkim.Validate())
+	})
+
+	t.Run("value_options", func(t *testing.T) {
+		dkim := DKIMRecord{Selector: "ed", KeyType: DKIMKeyTypeEd25519, PublicKey: "11qYAYKxCrfVS/7TyWQHOg7hcvPapiMlrwIaaPcHURo=", HashAlgorithms: "sha256", Flags: "y:s"}
+
+		assert.Equal(t, "v=DKIM1; k=ed25519; h=sha256; t=y:s; p=11qYAYKxCrfVS/7TyWQHOg7hcvPapiMlrwIaaPcHURo=", dkim.Value())
+		assert.NoError(t, dkim.Validate())
+	})
+
+	t.Run("host_record", func(t *testing.T) {
+		dkim := DKIMRecord{Selector: "google", PublicKey: publicKey}
+
+		assert.Equal(t, DomainsDNSHostRecord{HostName: "google._domainkey", RecordType: RecordTypeTXT, Address: dkim.Value()}, dkim.HostRecord("@"))
+		assert.Equal(t, "google._domainkey.shop", dkim.HostRecord("shop").HostName)
+	})
+
+	t.Run("parse", func(t *testing.T) {
+		dkim, err := ParseDKIM("v=DKIM1; k=rsa; t=y; p=" + publicKey[:50] + " " + publicKey[50:])
+		if err != nil {
+			t.Fatal("Unable to parse DKIM record", err)
+		}
+
+		assert.Equal(t, DKIMRecord{KeyType: DKIMKeyTypeRSA, PublicKey: publicKey, Flags: "y"}, *dkim)
+	})
+
+	t.Run("parse_service_notes_and_unknown_tags", func(t *testing.T) {
+		value := "v=DKIM1; k=rsa; s=email; n=rotated yearly; x-vendor=1; p=" + publicKey
+
+		dkim, err := ParseDKIM(value)
+		if err != nil {
+			t.Fatal("Unable to parse DKIM record", err)
+		}
+
+		assert.Equal(t, DKIMRecord{KeyType: DKIMKeyTypeRSA, PublicKey: publicKey, ServiceTypes: "email", Notes: "rotated yearly"}, *dkim)
+		assert.Equal(t, "v=DKIM1; k=rsa; s=email; n=rotated yearly; p="+publicKey, dkim.Value())
+	})
+
+	t.Run("parse_revoked_without_version", func(t *testing.T) {
+		dkim, err := ParseDKIM("p=")
+		if err != nil {
+			t.Fatal("Unable to parse DKIM record", err)
+		}
+
+		assert.Equal(t, DKIMRecord{}, *dkim)
+	})
+
+	var parseErrorCases = map[string]struct {
+		Value         string
+		ExpectedError string
+	}{
+		"version_not_first": {
+			Value:         "k=rsa; v=DKIM1; p=",
+			ExpectedError: "invalid DKIM record: k=rsa; v=DKIM1; p=, v=DKIM1 must be the first tag",
+		},
+		"missing_key": {
+			Value:         "v=DKIM1; k=rsa",
+			ExpectedError: "invalid DKIM record: v=DKIM1; k=rsa, p tag is required",
+		},
+	}
+
+	for name, c := range parseErrorCases {
+		t.Run("parse_"+name, func(t *testing.T) {
+			_, err := ParseDKIM(c.Value)
+
+			assert.EqualError(t, err, c.ExpectedError)
+		})
+	}
+
+	var errorCases = map[string]struct {
+		Record        DKIMRecord
+		ExpectedError string
+	}{
+		"missing_selector": {
+			Record:        DKIMRecord{PublicKey: publicKey},
+			ExpectedError: "Selector is required",
+		},
+		"invalid_selector": {
+			Record:        DKIMRecord{Selector: "mail key", PublicKey: publicKey},
+			ExpectedError: "invalid Selector value: mail key, it must be a valid host name",
+		},
+		"invalid_key_type": {
+			Record:        DKIMRecord{Selector: "mail", KeyType: "dsa", PublicKey: publicKey},
+			ExpectedError: "invalid KeyType value: dsa, allowed values are rsa, ed25519",
+		},
+		"invalid_base64": {
+			Record:        DKIMRecord{Selector: "mail", PublicKey: "MIIB!"},
+			ExpectedError: "invalid PublicKey value: public key isn't valid base64",
+		},
+		"not_rsa_key": {
+			Record:        DKIMRecord{Selector: "mail", PublicKey: "11qYAYKxCrfVS/7TyWQHOg7hcvPapiMlrwIaaPcHURo="},
+			ExpectedError: "invalid PublicKey value: public key isn't an RSA key",
+		},
+		"short_rsa_key": {
+			Record:        DKIMRecord{Selector: "mail", PublicKey: fakeRSAPublicKey(512)},
+			ExpectedError: "invalid PublicKey value: RSA key is 512 bits long, minimum is 1024",
+		},
+		"short_ed25519_key": {
+			Record:        DKIMRecord{Selector: "mail", KeyType: DKIMKeyTypeEd25519, PublicKey: "AAAA"},
+			ExpectedError: "invalid PublicKey value: ed25519 public key must be 32 bytes, got 3",
+		},
+		"invalid_hashes_and_flags": {
+			Record:        DKIMRecord{Selector: "mail", HashAlgorithms: "md5", Flags: "t"},
+			ExpectedError: "invalid HashAlgorithms value: md5, allowed algorithms are sha1, sha256; invalid Flags value: t, allowed flags are y, s",
+		},
+	}
+
+	for name, c := range errorCases {
+		t.Run(name, func(t *testing.T) {
+			assert.EqualError(t, c.Record.Validate(), c.ExpectedError)
+		})
+	}
+}
+
+func TestEmailAuthRecordsSetHosts(t *testing.T) {
+	publicKey := fakeRSAPublicKey(2048)
+
+	// the records are written back as they are, e.g. by the read-modify-write helpers, so only the builders are strict
+	var validCases = map[string][]DomainsDNSHostRecord{
+		"builders": {
+			SPFRecord{Mechanisms: []string{"mx"}, All: SPFQualifierFail}.HostRecord("@"),
+			DMARCRecord{Policy: DMARCPolicyReject}.HostRecord("@"),
+			DKIMRecord{Selector: "mail", PublicKey: publicKey}.HostRecord("@"),
+			DKIMRecord{Selector: "mail", PublicKey: publicKey}.HostRecord("shop"),
+			TXTHostRecord("@", "google-site-verification=abc"),
+		},
+		"spf_too_many_lookups": {
+			TXTHostRecord("@", "v=spf1 a mx include:a.net include:b.net include:c.net include:d.net include:e.net include:f.net include:g.net include:h.net include:i.net -all"),
+		},
+		"spf_modifier_after_all": {
+			TXTHostRecord("@", "v=spf1 mx ~all exp=explain.domain.net"),
+		},
+		"dkim_service_and_notes": {
+			TXTHostRecord("mail._domainkey", "v=DKIM1; s=email; n=notes; p="+publicKey),
+		},
+		"dmarc_np_and_https_rua": {
+			TXTHostRecord("_dmarc", "v=DMARC1; p=reject; np=reject; rua=https://reports.domain.net/dmarc"),
+		},
+		"spf_typo": {
+			TXTHostRecord("@", "v=spf1 inclde:_spf.google.com ~all"),
+		},
+	}
+
+	for name, records := range validCases {
+		t.Run(name, func(t *testing.T) {
+			mockServer := newRecordsMockServer(fakeRecordsGetHostsResponse, &url.Values{})
+			defer mockServer.Close()
+
+			client := setupClient(nil)
+			client.BaseURL = mockServer.URL
+
+			_, err := client.DomainsDNS.SetHosts(context.TODO(), &DomainsDNSSetHostsArgs{
+				Domain:  "domain.net",
+				Records: records,
+			})
+
+			assert.NoError(t, err)
+		})
+	}
+}
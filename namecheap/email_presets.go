@@ -0,0 +1,334 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	EmailProviderGoogleWorkspace = "google-workspace"
+	EmailProviderMicrosoft365    = "microsoft-365"
+	EmailProviderFastmail        = "fastmail"
+	EmailProviderZoho            = "zoho"
+	EmailProviderPrivateEmail    = "private-email"
+)
+
+var AllowedEmailProviderValues = []string{EmailProviderGoogleWorkspace, EmailProviderMicrosoft365, EmailProviderFastmail, EmailProviderZoho, EmailProviderPrivateEmail}
+
+// AllowedZohoRegionValues are the Zoho data centers, the region is the top-level domain of the Zoho mail servers
+var AllowedZohoRegionValues = []string{"com", "eu", "in", "com.au", "jp", "ca"}
+
+// emailProviderSPFIncludes are the prefixes of the SPF includes of the providers, they're dropped from the existing
+// SPF record when a preset is merged into it, so switching the provider doesn't leave the previous one authorized
+var emailProviderSPFIncludes = []string{
+	"include:_spf.google.com",
+	"include:spf.protection.outlook.com",
+	"include:spf.messagingengine.com",
+	"include:zoho.",
+	"include:spf.privateemail.com",
+}
+
+// EmailPresetArgs struct is an input arguments for NewEmailPreset and DomainsDNSService.ApplyEmailPreset functions
+type EmailPresetArgs struct {
+	// Domain the email is set up for, e.g. domain.net. Required
+	Domain string
+	// One of AllowedEmailProviderValues, e.g. EmailProviderGoogleWorkspace. Required
+	Provider string
+	// Domain ownership verification TXT value given by the provider, e.g. google-site-verification=..., MS=ms12345678
+	// or zoho-verification=zb12345678.zmverify.zoho.com. Optional
+	VerificationToken string
+	// Zoho data center, one of AllowedZohoRegionValues. Zoho only
+	// Default value: com
+	Region string
+	// DKIM key generated in the admin console of the provider. Optional, the Selector defaults to google for
+	// Google Workspace and to default for Private Email. Fastmail keys are published with CNAME records without it
+	DKIM *DKIMRecord
+	// DMARC policy of the domain. Optional, it replaces the existing one
+	DMARC *DMARCRecord
+}
+
+// EmailPreset is the email type and the host records an email provider requires
+type EmailPreset struct {
+	Provider string
+	// EmailType to set, e.g. EmailTypeMX. For EmailTypeGmail and EmailTypePrivate Namecheap manages the MX records itself
+	EmailType string
+	// Records to merge into the host records of the domain
+	Records []DomainsDNSHostRecord
+}
+
+// NewEmailPreset returns the email type and the host records of the provider:
+//
+//	preset, err := NewEmailPreset(&EmailPresetArgs{Domain: "domain.net", Provider: EmailProviderFastmail})
+//	records, err := preset.Merge(currentRecords)
+func NewEmailPreset(args *EmailPresetArgs) (*EmailPreset, error) {
+	if err := validateEmailPresetArgs(args); err != nil {
+		return nil, err
+	}
+
+	domain := strings.ToLower(strings.TrimSuffix(args.Domain, "."))
+	preset := &EmailPreset{Provider: args.Provider}
+	spf := SPFRecord{All: SPFQualifierSoftFail}
+	dkimSelector := ""
+
+	switch args.Provider {
+	case EmailProviderGoogleWorkspace:
+		preset.EmailType = EmailTypeGmail
+		spf.Mechanisms = []string{"include:_spf.google.com"}
+		dkimSelector = "google"
+	case EmailProviderMicrosoft365:
+		preset.EmailType = EmailTypeMX
+		preset.Records = []DomainsDNSHostRecord{
+			{HostName: "@", RecordType: RecordTypeMX, Address: strings.Replace(domain, ".", "-", -1) + ".mail.protection.outlook.com.", MXPref: UInt8(0)},
+			{HostName: "autodiscover", RecordType: RecordTypeCNAME, Address: "autodiscover.outlook.com."},
+		}
+		spf.Mechanisms = []string{"include:spf.protection.outlook.com"}
+		spf.All = SPFQualifierFail
+	case EmailProviderFastmail:
+		preset.EmailType = EmailTypeMX
+		preset.Records = []DomainsDNSHostRecord{
+			{HostName: "@", RecordType: RecordTypeMX, Address: "in1-smtp.messagingengine.com.", MXPref: UInt8(10)},
+			{HostName: "@", RecordType: RecordTypeMX, Address: "in2-smtp.messagingengine.com.", MXPref: UInt8(20)},
+		}
+		for _, selector := range []string{"fm1", "fm2", "fm3"} {
+			preset.Records = append(preset.Records, DomainsDNSHostRecord{
+				HostName:   selector + "._domainkey",
+				RecordType: RecordTypeCNAME,
+				Address:    fmt.Sprintf("%s.%s.dkim.fmhosted.com.", selector, domain),
+			})
+		}
+		spf.Mechanisms = []string{"include:spf.messagingengine.com"}
+		spf.All = SPFQualifierNeutral
+	case EmailProviderZoho:
+		region := args.Region
+		if region == "" {
+			region = "com"
+		}
+		preset.EmailType = EmailTypeMX
+		preset.Records = []DomainsDNSHostRecord{
+			{HostName: "@", RecordType: RecordTypeMX, Address: "mx.zoho." + region + ".", MXPref: UInt8(10)},
+			{HostName: "@", RecordType: RecordTypeMX, Address: "mx2.zoho." + region + ".", MXPref: UInt8(20)},
+			{HostName: "@", RecordType: RecordTypeMX, Address: "mx3.zoho." + region + ".", MXPref: UInt8(50)},
+		}
+		spf.Mechanisms = []string{"include:zoho." + region}
+	case EmailProviderPrivateEmail:
+		preset.EmailType = EmailTypePrivate
+		spf.Mechanisms = []string{"include:spf.privateemail.com"}
+		dkimSelector = "default"
+	}
+
+	preset.Records = append(preset.Records, spf.HostRecord("@"))
+
+	if args.VerificationToken != "" {
+		preset.Records = append(preset.Records, TXTHostRecord("@", args.VerificationToken))
+	}
+
+	if args.DKIM != nil {
+		dkim := *args.DKIM
+		if dkim.Selector == "" {
+			dkim.Selector = dkimSelector
+		}
+		if err := dkim.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid DKIM: %v", err)
+		}
+		preset.Records = append(preset.Records, dkim.HostRecord("@"))
+	}
+
+	if args.DMARC != nil {
+		if err := args.DMARC.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid DMARC: %v", err)
+		}
+		preset.Records = append(preset.Records, args.DMARC.HostRecord("@"))
+	}
+
+	return preset, nil
+}
+
+// Merge returns the records with the preset merged in, without duplicates. The existing records are kept
+// in their order except for:
+//
+//   - the MX and MXE records of the apex (@) the preset targets, which are replaced, since they belong to
+//     the email type of the domain. The MX records of the other hosts, e.g. a mg subdomain of a mailing service,
+//     are kept, and an error is returned if the EmailType of the preset doesn't allow them;
+//   - the SPF record of the same host, the includes of the known providers are replaced with the mechanisms
+//     of the preset, the other mechanisms and the all mechanism are kept;
+//   - the DKIM and DMARC records and the CNAME records of the same host, which are replaced.
+//
+// The other preset records are appended unless an identical record exists already.
+func (p *EmailPreset) Merge(records []DomainsDNSHostRecord) ([]DomainsDNSHostRecord, error) {
+	merged := make([]DomainsDNSHostRecord, 0, len(records)+len(p.Records))
+	var keptMXHosts []string
+	for _, record := range records {
+		recordType := strings.ToUpper(record.RecordType)
+		if recordType != RecordTypeMX && recordType != RecordTypeMXE {
+			merged = append(merged, record)
+			continue
+		}
+		// all the presets target the apex
+		if normalizeRecordHostName(record.HostName) == "@" {
+			continue
+		}
+		if recordType != RecordTypeMX || p.EmailType != EmailTypeMX {
+			keptMXHosts = append(keptMXHosts, record.HostName)
+		}
+		merged = append(merged, record)
+	}
+
+	if len(keptMXHosts) > 0 {
+		return nil, fmt.Errorf("the MX records of %s can't be kept with EmailType %s, remove them first", strings.Join(keptMXHosts, ", "), p.EmailType)
+	}
+
+	for _, presetRecord := range p.Records {
+		kind := emailPresetRecordKind(presetRecord)
+
+		replaced := false
+		for i := 0; i < len(merged); i++ {
+			existing := merged[i]
+			if normalizeRecordHostName(existing.HostName) != normalizeRecordHostName(presetRecord.HostName) ||
+				!strings.EqualFold(existing.RecordType, presetRecord.RecordType) {
+				continue
+			}
+
+			switch {
+			case existing.Key() == presetRecord.Key():
+				replaced = true
+			case kind == "" || kind != emailPresetRecordKind(existing):
+				continue
+			case replaced:
+				// a duplicate of the kind, e.g. a second SPF record, which is invalid anyway
+				merged = append(merged[:i], merged[i+1:]...)
+				i--
+				continue
+			case kind == "spf":
+				merged[i].Address = mergeSPF(existing.Address, presetRecord.Address)
+				replaced = true
+			default:
+				ttl := existing.TTL
+				merged[i] = presetRecord
+				merged[i].TTL = ttl
+				replaced = true
+			}
+		}
+
+		if !replaced {
+			merged = append(merged, presetRecord)
+		}
+	}
+
+	return merged, nil
+}
+
+// mergeSPF replaces the provider includes of the existing SPF value with the mechanisms of the preset one,
+// the unparsable values are replaced
+func mergeSPF(existingValue string, presetValue string) string {
+	existing, err := ParseSPF(existingValue)
+	if err != nil {
+		return presetValue
+	}
+	preset, _ := ParseSPF(presetValue)
+
+	mechanisms := []string{}
+	for _, mechanism := range existing.Mechanisms {
+		if !isEmailProviderSPFInclude(mechanism) {
+			mechanisms = append(mechanisms, mechanism)
+		}
+	}
+
+	existing.Mechanisms = append(mechanisms, preset.Mechanisms...)
+	if existing.All == "" && existing.Redirect == "" {
+		existing.All = preset.All
+	}
+	return existing.Value()
+}
+
+// ApplyEmailPreset sets the email type of the provider and merges its host records into the existing ones
+// with a single SetHosts call, see EmailPreset.Merge.
+func (dds DomainsDNSService) ApplyEmailPreset(ctx context.Context, args *EmailPresetArgs) (*DomainsDNSSetHostsCommandResponse, error) {
+	preset, err := NewEmailPreset(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return dds.updateHosts(ctx, args.Domain, func(result DomainDNSGetHostsResult) (*DomainsDNSSetHostsArgs, error) {
+		records, err := preset.Merge(HostRecordsFromDetailed(result.Hosts))
+		if err != nil {
+			return nil, err
+		}
+		return &DomainsDNSSetHostsArgs{
+			Domain:    args.Domain,
+			Records:   records,
+			EmailType: preset.EmailType,
+		}, nil
+	})
+}
+
+func validateEmailPresetArgs(args *EmailPresetArgs) error {
+	if args == nil {
+		return fmt.Errorf("args is required")
+	}
+	if args.Domain == "" {
+		return fmt.Errorf("Domain is required")
+	}
+	if !isValidEmailProvider(args.Provider) {
+		return fmt.Errorf("invalid Provider value: %s, allowed values are %s", args.Provider, strings.Join(AllowedEmailProviderValues, ", "))
+	}
+	if args.Region != "" {
+		if args.Provider != EmailProviderZoho {
+			return fmt.Errorf("Region is only supported by %s", EmailProviderZoho)
+		}
+		if !isValidZohoRegion(args.Region) {
+			return fmt.Errorf("invalid Region value: %s, allowed values are %s", args.Region, strings.Join(AllowedZohoRegionValues, ", "))
+		}
+	}
+	if args.DKIM != nil && args.Provider == EmailProviderFastmail {
+		return fmt.Errorf("DKIM isn't supported by %s, its keys are published with the fm1, fm2 and fm3 CNAME records", EmailProviderFastmail)
+	}
+	return nil
+}
+
+// emailPresetRecordKind returns spf, dkim or dmarc for the TXT records of the kind and cname for the CNAME records.
+// A host has a single record of each kind.
+func emailPresetRecordKind(record DomainsDNSHostRecord) string {
+	switch strings.ToUpper(record.RecordType) {
+	case RecordTypeCNAME:
+		return "cname"
+	case RecordTypeTXT:
+		address := strings.ToLower(record.Address)
+		switch {
+		case address == "v=spf1" || strings.HasPrefix(address, "v=spf1 "):
+			return "spf"
+		case strings.HasPrefix(address, "v=dkim1"):
+			return "dkim"
+		case strings.HasPrefix(address, "v=dmarc1"):
+			return "dmarc"
+		}
+	}
+	return ""
+}
+
+func isEmailProviderSPFInclude(mechanism string) bool {
+	for _, include := range emailProviderSPFIncludes {
+		if strings.HasPrefix(strings.ToLower(mechanism), include) {
+			return true
+		}
+	}
+	return false
+}
+
+func isValidEmailProvider(provider string) bool {
+	for _, value := range AllowedEmailProviderValues {
+		if value == provider {
+			return true
+		}
+	}
+	return false
+}
+
+func isValidZohoRegion(region string) bool {
+	for _, value := range AllowedZohoRegionValues {
+		if value == region {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,302 @@
+package namecheap
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEmailPreset(t *testing.T) {
+	var cases = map[string]struct {
+		Args              EmailPresetArgs
+		ExpectedEmailType string
+		ExpectedRecords   []DomainsDNSHostRecord
+	}{
+		"google_workspace": {
+			Args:              EmailPresetArgs{Domain: "domain.net", Provider: EmailProviderGoogleWorkspace, VerificationToken: "google-site-verification=abc"},
+			ExpectedEmailType: EmailTypeGmail,
+			ExpectedRecords: []DomainsDNSHostRecord{
+				{HostName: "@", RecordType: "TXT", Address: "v=spf1 include:_spf.google.com ~all"},
+				{HostName: "@", RecordType: "TXT", Address: "google-site-verification=abc"},
+			},
+		},
+		"microsoft_365": {
+			Args:              EmailPresetArgs{Domain: "My.Domain.net.", Provider: EmailProviderMicrosoft365, VerificationToken: "MS=ms12345678"},
+			ExpectedEmailType: EmailTypeMX,
+			ExpectedRecords: []DomainsDNSHostRecord{
+				{HostName: "@", RecordType: "MX", Address: "my-domain-net.mail.protection.outlook.com.", MXPref: UInt8(0)},
+				{HostName: "autodiscover", RecordType: "CNAME", Address: "autodiscover.outlook.com."},
+				{HostName: "@", RecordType: "TXT", Address: "v=spf1 include:spf.protection.outlook.com -all"},
+				{HostName: "@", RecordType: "TXT", Address: "MS=ms12345678"},
+			},
+		},
+		"fastmail": {
+			Args:              EmailPresetArgs{Domain: "domain.net", Provider: EmailProviderFastmail},
+			ExpectedEmailType: EmailTypeMX,
+			ExpectedRecords: []DomainsDNSHostRecord{
+				{HostName: "@", RecordType: "MX", Address: "in1-smtp.messagingengine.com.", MXPref: UInt8(10)},
+				{HostName: "@", RecordType: "MX", Address: "in2-smtp.messagingengine.com.", MXPref: UInt8(20)},
+				{HostName: "fm1._domainkey", RecordType: "CNAME", Address: "fm1.domain.net.dkim.fmhosted.com."},
+				{HostName: "fm2._domainkey", RecordType: "CNAME", Address: "fm2.domain.net.dkim.fmhosted.com."},
+				{HostName: "fm3._domainkey", RecordType: "CNAME", Address: "fm3.domain.net.dkim.fmhosted.com."},
+				{HostName: "@", RecordType: "TXT", Address: "v=spf1 include:spf.messagingengine.com ?all"},
+			},
+		},
+		"zoho_eu": {
+			Args:              EmailPresetArgs{Domain: "domain.net", Provider: EmailProviderZoho, Region: "eu"},
+			ExpectedEmailType: EmailTypeMX,
+			ExpectedRecords: []DomainsDNSHostRecord{
+				{HostName: "@", RecordType: "MX", Address: "mx.zoho.eu.", MXPref: UInt8(10)},
+				{HostName: "@", RecordType: "MX", Address: "mx2.zoho.eu.", MXPref: UInt8(20)},
+				{HostName: "@", RecordType: "MX", Address: "mx3.zoho.eu.", MXPref: UInt8(50)},
+				{HostName: "@", RecordType: "TXT", Address: "v=spf1 include:zoho.eu ~all"},
+			},
+		},
+		"private_email": {
+			Args:              EmailPresetArgs{Domain: "domain.net", Provider: EmailProviderPrivateEmail, DMARC: &DMARCRecord{Policy: DMARCPolicyQuarantine}},
+			ExpectedEmailType: EmailTypePrivate,
+			ExpectedRecords: []DomainsDNSHostRecord{
+				{HostName: "@", RecordType: "TXT", Address: "v=spf1 include:spf.privateemail.com ~all"},
+				{HostName: "_dmarc", RecordType: "TXT", Address: "v=DMARC1; p=quarantine"},
+			},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			preset, err := NewEmailPreset(&c.Args)
+			if err != nil {
+				t.Fatal("Unable to create email preset", err)
+			}
+
+			assert.Equal(t, c.Args.Provider, preset.Provider)
+			assert.Equal(t, c.ExpectedEmailType, preset.EmailType)
+			assert.Equal(t, c.ExpectedRecords, preset.Records)
+		})
+	}
+
+	t.Run("default_dkim_selector", func(t *testing.T) {
+		publicKey := fakeRSAPublicKey(2048)
+
+		preset, err := NewEmailPreset(&EmailPresetArgs{Domain: "domain.net", Provider: EmailProviderGoogleWorkspace, DKIM: &DKIMRecord{PublicKey: publicKey}})
+		if err != nil {
+			t.Fatal("Unable to create email preset", err)
+		}
+
+		assert.Equal(t, DomainsDNSHostRecord{HostName: "google._domainkey", RecordType: "TXT", Address: "v=DKIM1; k=rsa; p=" + publicKey}, preset.Records[1])
+	})
+
+	var errorCases = map[string]struct {
+		Args          *EmailPresetArgs
+		ExpectedError string
+	}{
+		"nil_args": {
+			Args:          nil,
+			ExpectedError: "args is required",
+		},
+		"missing_domain": {
+			Args:          &EmailPresetArgs{Provider: EmailProviderZoho},
+			ExpectedError: "Domain is required",
+		},
+		"unknown_provider": {
+			Args:          &EmailPresetArgs{Domain: "domain.net", Provider: "gmail"},
+			ExpectedError: "invalid Provider value: gmail, allowed values are google-workspace, microsoft-365, fastmail, zoho, private-email",
+		},
+		"region_of_other_provider": {
+			Args:          &EmailPresetArgs{Domain: "domain.net", Provider: EmailProviderFastmail, Region: "eu"},
+			ExpectedError: "Region is only supported by zoho",
+		},
+		"invalid_region": {
+			Args:          &EmailPresetArgs{Domain: "domain.net", Provider: EmailProviderZoho, Region: "de"},
+			ExpectedError: "invalid Region value: de, allowed values are com, eu, in, com.au, jp, ca",
+		},
+		"fastmail_dkim": {
+			Args:          &EmailPresetArgs{Domain: "domain.net", Provider: EmailProviderFastmail, DKIM: &DKIMRecord{}},
+			ExpectedError: "DKIM isn't supported by fastmail, its keys are published with the fm1, fm2 and fm3 CNAME records",
+		},
+		"dkim_without_selector": {
+			Args:          &EmailPresetArgs{Domain: "domain.net", Provider: EmailProviderZoho, DKIM: &DKIMRecord{}},
+			ExpectedError: "invalid DKIM: Selector is required",
+		},
+		"invalid_dmarc": {
+			Args:          &EmailPresetArgs{Domain: "domain.net", Provider: EmailProviderZoho, DMARC: &DMARCRecord{Policy: "block"}},
+			ExpectedError: "invalid DMARC: invalid Policy value: block, allowed values are none, quarantine, reject",
+		},
+	}
+
+	for name, c := range errorCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewEmailPreset(c.Args)
+
+			assert.EqualError(t, err, c.ExpectedError)
+		})
+	}
+}
+
+func TestEmailPresetMerge(t *testing.T) {
+	t.Run("replaces_mx_and_keeps_the_rest", func(t *testing.T) {
+		preset, _ := NewEmailPreset(&EmailPresetArgs{Domain: "domain.net", Provider: EmailProviderFastmail})
+
+		merged, err := preset.Merge([]DomainsDNSHostRecord{
+			{HostName: "@", RecordType: "MX", Address: "mx1.domain.net.", MXPref: UInt8(5), TTL: 1800},
+			{HostName: "www", RecordType: "A", Address: "192.0.2.1", TTL: 300},
+			{HostName: "fm1._domainkey", RecordType: "CNAME", Address: "old.dkim.net.", TTL: 600},
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, []DomainsDNSHostRecord{
+			{HostName: "www", RecordType: "A", Address: "192.0.2.1", TTL: 300},
+			{HostName: "fm1._domainkey", RecordType: "CNAME", Address: "fm1.domain.net.dkim.fmhosted.com.", TTL: 600},
+			{HostName: "@", RecordType: "MX", Address: "in1-smtp.messagingengine.com.", MXPref: UInt8(10)},
+			{HostName: "@", RecordType: "MX", Address: "in2-smtp.messagingengine.com.", MXPref: UInt8(20)},
+			{HostName: "fm2._domainkey", RecordType: "CNAME", Address: "fm2.domain.net.dkim.fmhosted.com."},
+			{HostName: "fm3._domainkey", RecordType: "CNAME", Address: "fm3.domain.net.dkim.fmhosted.com."},
+			{HostName: "@", RecordType: "TXT", Address: "v=spf1 include:spf.messagingengine.com ?all"},
+		}, merged)
+	})
+
+	t.Run("keeps_subdomain_mx", func(t *testing.T) {
+		preset, _ := NewEmailPreset(&EmailPresetArgs{Domain: "domain.net", Provider: EmailProviderFastmail})
+
+		merged, err := preset.Merge([]DomainsDNSHostRecord{
+			{HostName: "@", RecordType: "MX", Address: "mx1.domain.net.", MXPref: UInt8(5)},
+			{HostName: "mg", RecordType: "MX", Address: "mxa.mailgun.org.", MXPref: UInt8(10)},
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, []DomainsDNSHostRecord{
+			{HostName: "mg", RecordType: "MX", Address: "mxa.mailgun.org.", MXPref: UInt8(10)},
+			{HostName: "@", RecordType: "MX", Address: "in1-smtp.messagingengine.com.", MXPref: UInt8(10)},
+			{HostName: "@", RecordType: "MX", Address: "in2-smtp.messagingengine.com.", MXPref: UInt8(20)},
+			{HostName: "fm1._domainkey", RecordType: "CNAME", Address: "fm1.domain.net.dkim.fmhosted.com."},
+			{HostName: "fm2._domainkey", RecordType: "CNAME", Address: "fm2.domain.net.dkim.fmhosted.com."},
+			{HostName: "fm3._domainkey", RecordType: "CNAME", Address: "fm3.domain.net.dkim.fmhosted.com."},
+			{HostName: "@", RecordType: "TXT", Address: "v=spf1 include:spf.messagingengine.com ?all"},
+		}, merged)
+	})
+
+	t.Run("subdomain_mx_not_allowed", func(t *testing.T) {
+		preset, _ := NewEmailPreset(&EmailPresetArgs{Domain: "domain.net", Provider: EmailProviderGoogleWorkspace})
+
+		_, err := preset.Merge([]DomainsDNSHostRecord{
+			{HostName: "@", RecordType: "MX", Address: "mx1.domain.net.", MXPref: UInt8(5)},
+			{HostName: "mg", RecordType: "MX", Address: "mxa.mailgun.org.", MXPref: UInt8(10)},
+		})
+
+		assert.EqualError(t, err, "the MX records of mg can't be kept with EmailType GMAIL, remove them first")
+	})
+
+	t.Run("merges_spf", func(t *testing.T) {
+		preset, _ := NewEmailPreset(&EmailPresetArgs{Domain: "domain.net", Provider: EmailProviderGoogleWorkspace})
+
+		merged, err := preset.Merge([]DomainsDNSHostRecord{
+			{HostName: "@", RecordType: "TXT", Address: "v=spf1 include:zoho.com include:sendgrid.net -all", TTL: 3600},
+			{HostName: "shop", RecordType: "TXT", Address: "v=spf1 -all"},
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, []DomainsDNSHostRecord{
+			{HostName: "@", RecordType: "TXT", Address: "v=spf1 include:sendgrid.net include:_spf.google.com -all", TTL: 3600},
+			{HostName: "shop", RecordType: "TXT", Address: "v=spf1 -all"},
+		}, merged)
+	})
+
+	t.Run("no_duplicates", func(t *testing.T) {
+		preset, _ := NewEmailPreset(&EmailPresetArgs{
+			Domain:            "domain.net",
+			Provider:          EmailProviderGoogleWorkspace,
+			VerificationToken: "google-site-verification=abc",
+			DMARC:             &DMARCRecord{Policy: DMARCPolicyReject},
+		})
+
+		records := []DomainsDNSHostRecord{
+			{HostName: "@", RecordType: "TXT", Address: "v=spf1 include:_spf.google.com ~all", TTL: 1800},
+			{HostName: "@", RecordType: "TXT", Address: "google-site-verification=abc", TTL: 1800},
+			{HostName: "_dmarc", RecordType: "TXT", Address: "v=DMARC1; p=none", TTL: 300},
+		}
+
+		merged, err := preset.Merge(records)
+		assert.NoError(t, err)
+		assert.Equal(t, []DomainsDNSHostRecord{
+			{HostName: "@", RecordType: "TXT", Address: "v=spf1 include:_spf.google.com ~all", TTL: 1800},
+			{HostName: "@", RecordType: "TXT", Address: "google-site-verification=abc", TTL: 1800},
+			{HostName: "_dmarc", RecordType: "TXT", Address: "v=DMARC1; p=reject", TTL: 300},
+		}, merged)
+
+		remerged, err := preset.Merge(merged)
+		assert.NoError(t, err)
+		assert.Equal(t, merged, remerged)
+	})
+
+	t.Run("drops_duplicate_spf", func(t *testing.T) {
+		preset, _ := NewEmailPreset(&EmailPresetArgs{Domain: "domain.net", Provider: EmailProviderPrivateEmail})
+
+		merged, err := preset.Merge([]DomainsDNSHostRecord{
+			{HostName: "@", RecordType: "TXT", Address: "v=spf1 mx -all"},
+			{HostName: "@", RecordType: "TXT", Address: "v=spf1 a -all"},
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, []DomainsDNSHostRecord{
+			{HostName: "@", RecordType: "TXT", Address: "v=spf1 mx include:spf.privateemail.com -all"},
+		}, merged)
+	})
+}
+
+func TestDomainsDNSApplyEmailPreset(t *testing.T) {
+	t.Run("one_call", func(t *testing.T) {
+		sentSetHosts := url.Values{}
+		mockServer := newRecordsMockServer(fakeRecordsGetHostsResponse, &sentSetHosts)
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		_, err := client.DomainsDNS.ApplyEmailPreset(context.TODO(), &EmailPresetArgs{
+			Domain:            "domain.net",
+			Provider:          EmailProviderMicrosoft365,
+			VerificationToken: "MS=ms12345678",
+		})
+		if err != nil {
+			t.Fatal("Unable to apply email preset", err)
+		}
+
+		assert.Equal(t, "MX", sentSetHosts.Get("EmailType"))
+		assert.Equal(t, "CAA", sentSetHosts.Get("RecordType1"))
+		assert.Equal(t, "www", sentSetHosts.Get("HostName2"))
+		assert.Equal(t, "MX", sentSetHosts.Get("RecordType3"))
+		assert.Equal(t, "domain-net.mail.protection.outlook.com.", sentSetHosts.Get("Address3"))
+		assert.Equal(t, "0", sentSetHosts.Get("MXPref3"))
+		assert.Equal(t, "autodiscover", sentSetHosts.Get("HostName4"))
+		assert.Equal(t, "v=spf1 include:spf.protection.outlook.com -all", sentSetHosts.Get("Address5"))
+		assert.Equal(t, "MS=ms12345678", sentSetHosts.Get("Address6"))
+		assert.Empty(t, sentSetHosts.Get("HostName7"))
+	})
+
+	t.Run("gmail_email_type", func(t *testing.T) {
+		sentSetHosts := url.Values{}
+		mockServer := newRecordsMockServer(fakeRecordsGetHostsResponse, &sentSetHosts)
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		_, err := client.DomainsDNS.ApplyEmailPreset(context.TODO(), &EmailPresetArgs{Domain: "domain.net", Provider: EmailProviderGoogleWorkspace})
+		if err != nil {
+			t.Fatal("Unable to apply email preset", err)
+		}
+
+		assert.Equal(t, "GMAIL", sentSetHosts.Get("EmailType"))
+		assert.NotContains(t, []string{sentSetHosts.Get("RecordType1"), sentSetHosts.Get("RecordType2"), sentSetHosts.Get("RecordType3")}, "MX")
+	})
+
+	t.Run("invalid_args", func(t *testing.T) {
+		client := setupClient(nil)
+
+		_, err := client.DomainsDNS.ApplyEmailPreset(context.TODO(), &EmailPresetArgs{Domain: "domain.net"})
+
+		assert.EqualError(t, err, "invalid Provider value: , allowed values are google-workspace, microsoft-365, fastmail, zoho, private-email")
+	})
+}
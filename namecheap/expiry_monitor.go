@@ -0,0 +1,308 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	ExpiryBucketExpired      = "EXPIRED"
+	ExpiryBucketWithin7Days  = "WITHIN_7_DAYS"
+	ExpiryBucketWithin30Days = "WITHIN_30_DAYS"
+	ExpiryBucketWithin90Days = "WITHIN_90_DAYS"
+	ExpiryBucketLater        = "LATER"
+)
+
+const defaultRenewalPlanWithinDays = 30
+
+// ExpiryReportEntry is the expiration status of a single domain
+type ExpiryReportEntry struct {
+	Domain Domain
+	// One of the ExpiryBucket constants
+	Bucket string
+	// Days left until the expiration, negative for the expired domains
+	DaysLeft int
+	// Whether the domain expires within 90 days without auto-renew turned on, or has expired anyway
+	NeedsAttention bool
+}
+
+// ExpiryReport is the expiration status of the domains, sorted by the expiration date
+type ExpiryReport struct {
+	Entries []ExpiryReportEntry
+}
+
+// Bucket returns the entries of the bucket, e.g. ExpiryBucketWithin7Days
+func (r ExpiryReport) Bucket(bucket string) []ExpiryReportEntry {
+	var entries []ExpiryReportEntry
+	for _, entry := range r.Entries {
+		if entry.Bucket == bucket {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// NeedsAttention returns the entries flagged as expiring without auto-renew, and the expired ones
+func (r ExpiryReport) NeedsAttention() []ExpiryReportEntry {
+	var entries []ExpiryReportEntry
+	for _, entry := range r.Entries {
+		if entry.NeedsAttention {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// String returns a human-readable summary of the report, one line per domain needing attention
+func (r ExpiryReport) String() string {
+	var lines []string
+	for _, entry := range r.Entries {
+		if entry.Bucket == ExpiryBucketLater {
+			continue
+		}
+		line := fmt.Sprintf("%s: %s (%d days left)", entry.Domain.Name, entry.Bucket, entry.DaysLeft)
+		switch {
+		case entry.NeedsAttention && entry.Domain.AutoRenew:
+			line += ", auto-renewal failed"
+		case entry.NeedsAttention:
+			line += ", auto-renew is off"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// RenewalPlanArgs struct is an input arguments for ExpiryMonitor.PlanRenewals function
+type RenewalPlanArgs struct {
+	// Plan the domains expiring within the number of days, the expired ones are always planned for reactivation
+	// Default value: 30
+	WithinDays int
+	// Whether to plan the domains with auto-renew turned on as well, the expired ones are always planned
+	IncludeAutoRenew bool
+	// Number of years to renew for, minimum value is 1, and maximum value is 10.
+	// The expired domains are reactivated for a single year.
+	// Default value: 1
+	Years int
+	// Promotional (coupon) code to be applied to the renewal prices
+	PromotionCode string
+}
+
+// RenewalPlanItem is a single planned renewal, or reactivation for the expired domains
+type RenewalPlanItem struct {
+	Entry    ExpiryReportEntry
+	Estimate CostEstimateItem
+}
+
+// RenewalPlan is the list of renewals with their estimated cost
+type RenewalPlan struct {
+	Items         []RenewalPlanItem
+	Total         Money
+	PromotionCode string
+}
+
+// RenewalOutcome is the result of a single renewal executed by ExpiryMonitor.ExecuteRenewals
+type RenewalOutcome struct {
+	Item   RenewalPlanItem
+	Result *DomainRenewResult
+	// Result of the reactivation, set instead of the Result for the expired domains
+	ReactivateResult *DomainReactivateResult
+	Err              error
+}
+
+// ExpiryMonitor classifies the domains of the account by their expiration date, and plans and executes the renewals
+type ExpiryMonitor struct {
+	client    *Client
+	estimator *CostEstimator
+	now       func() time.Time
+}
+
+// NewExpiryMonitor returns a monitor pricing the renewals with the estimator, nil means NewCostEstimator(client, nil)
+func NewExpiryMonitor(client *Client, estimator *CostEstimator) *ExpiryMonitor {
+	if estimator == nil {
+		estimator = NewCostEstimator(client, nil)
+	}
+	return &ExpiryMonitor{
+		client:    client,
+		estimator: estimator,
+		now:       time.Now,
+	}
+}
+
+// Check lists all the domains of the account and classifies them
+func (m *ExpiryMonitor) Check(ctx context.Context) (*ExpiryReport, error) {
+	domains, err := m.client.Domains.ListAll(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.Classify(domains), nil
+}
+
+// Classify sorts the domains into the expiration buckets
+func (m *ExpiryMonitor) Classify(domains []Domain) *ExpiryReport {
+	// the expiration dates have no time, so count the days from the start of the day
+	now := m.now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	report := &ExpiryReport{Entries: make([]ExpiryReportEntry, 0, len(domains))}
+	for _, domain := range domains {
+		entry := ExpiryReportEntry{
+			Domain:   domain,
+			DaysLeft: int(domain.Expires.Sub(today).Hours() / 24),
+		}
+
+		switch {
+		case domain.IsExpired || entry.DaysLeft < 0:
+			entry.Bucket = ExpiryBucketExpired
+		case entry.DaysLeft < 7:
+			entry.Bucket = ExpiryBucketWithin7Days
+		case entry.DaysLeft < 30:
+			entry.Bucket = ExpiryBucketWithin30Days
+		case entry.DaysLeft < 90:
+			entry.Bucket = ExpiryBucketWithin90Days
+		default:
+			entry.Bucket = ExpiryBucketLater
+		}
+
+		// an expired domain with auto-renew turned on means the auto-renewal has failed
+		entry.NeedsAttention = entry.Bucket == ExpiryBucketExpired || (!domain.AutoRenew && entry.Bucket != ExpiryBucketLater)
+
+		report.Entries = append(report.Entries, entry)
+	}
+
+	sort.SliceStable(report.Entries, func(i, j int) bool {
+		return report.Entries[i].Domain.Expires.Before(report.Entries[j].Domain.Expires.Time)
+	})
+
+	return report
+}
+
+// PlanRenewals selects the domains to be renewed from the report and estimates the cost.
+// By default only the domains without auto-renew expiring within 30 days, and the expired ones, are planned.
+// The expired domains can't be renewed anymore, they're planned for reactivation.
+func (m *ExpiryMonitor) PlanRenewals(ctx context.Context, report *ExpiryReport, args *RenewalPlanArgs) (*RenewalPlan, error) {
+	var planArgs RenewalPlanArgs
+	if args != nil {
+		planArgs = *args
+	}
+	if planArgs.WithinDays == 0 {
+		planArgs.WithinDays = defaultRenewalPlanWithinDays
+	}
+	if planArgs.Years == 0 {
+		planArgs.Years = 1
+	}
+	if planArgs.WithinDays < 0 {
+		return nil, fmt.Errorf("invalid WithinDays value: %d, minimum value is 1", planArgs.WithinDays)
+	}
+
+	plan := &RenewalPlan{PromotionCode: planArgs.PromotionCode}
+
+	var entries []ExpiryReportEntry
+	var operations []PlannedOperation
+	for _, entry := range report.Entries {
+		if entry.Domain.AutoRenew && !planArgs.IncludeAutoRenew && entry.Bucket != ExpiryBucketExpired {
+			continue
+		}
+		if entry.Bucket != ExpiryBucketExpired && entry.DaysLeft >= planArgs.WithinDays {
+			continue
+		}
+
+		operation := PlannedOperation{
+			Action: ActionNameRenew,
+			Domain: entry.Domain.Name,
+			Years:  planArgs.Years,
+		}
+		if entry.Bucket == ExpiryBucketExpired {
+			operation.Action = ActionNameReactivate
+			operation.Years = 1
+		}
+
+		entries = append(entries, entry)
+		operations = append(operations, operation)
+	}
+
+	if len(operations) == 0 {
+		return plan, nil
+	}
+
+	estimate, err := m.estimator.Estimate(ctx, &CostEstimateArgs{
+		Operations:    operations,
+		PromotionCode: planArgs.PromotionCode,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to estimate the renewals: %v", err)
+	}
+
+	for i, item := range estimate.Items {
+		plan.Items = append(plan.Items, RenewalPlanItem{Entry: entries[i], Estimate: item})
+	}
+	plan.Total = estimate.Total
+
+	return plan, nil
+}
+
+// ExecuteRenewals renews, or reactivates, the domains of the plan one by one, stopping at the first failure.
+// The client must have a SpendingPolicy set, every renewal is checked against it.
+// The outcomes of the renewals attempted so far are returned along with the error.
+func (m *ExpiryMonitor) ExecuteRenewals(ctx context.Context, plan *RenewalPlan) ([]RenewalOutcome, error) {
+	m.client.spendGuard.m.Lock()
+	policy := m.client.spendGuard.policy
+	m.client.spendGuard.m.Unlock()
+
+	if policy == nil {
+		return nil, fmt.Errorf("a spending policy is required to execute renewals, see Client.SetSpendingPolicy")
+	}
+
+	var outcomes []RenewalOutcome
+	for _, item := range plan.Items {
+		domainName := item.Estimate.Operation.Domain
+
+		var check *DomainCheckResult
+		if item.Estimate.IsPremium {
+			var err error
+			check, err = m.client.Domains.checkOne(ctx, domainName)
+			if err != nil {
+				return outcomes, fmt.Errorf("unable to get the premium price of %s: %v", domainName, err)
+			}
+		}
+
+		if item.Estimate.Operation.Action == ActionNameReactivate {
+			args := DomainReactivateArgs{
+				DomainName:    domainName,
+				PromotionCode: plan.PromotionCode,
+			}
+			if check != nil {
+				args.IsPremiumDomain = true
+				args.PremiumPrice = check.PremiumRestorePrice
+			}
+
+			result, err := m.client.Domains.Reactivate(ctx, args)
+			outcomes = append(outcomes, RenewalOutcome{Item: item, ReactivateResult: result, Err: err})
+			if err != nil {
+				return outcomes, fmt.Errorf("unable to reactivate %s: %v", domainName, err)
+			}
+			continue
+		}
+
+		args := DomainRenewArgs{
+			DomainName:    domainName,
+			Years:         item.Estimate.Operation.Years,
+			PromotionCode: plan.PromotionCode,
+		}
+		if check != nil {
+			args.IsPremiumDomain = true
+			args.PremiumPrice = check.PremiumRenewalPrice
+		}
+
+		result, err := m.client.Domains.Renew(ctx, args)
+		outcomes = append(outcomes, RenewalOutcome{Item: item, Result: result, Err: err})
+		if err != nil {
+			return outcomes, fmt.Errorf("unable to renew %s: %v", domainName, err)
+		}
+	}
+
+	return outcomes, nil
+}
@@ -0,0 +1,268 @@
+package namecheap
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var expiryTestNow = time.Date(2021, 6, 2, 15, 4, 5, 0, time.UTC)
+
+func expiryTestDomain(name string, expiresInDays int, autoRenew bool) Domain {
+	return Domain{
+		Name:      name,
+		Expires:   DateTime{time.Date(2021, 6, 2, 0, 0, 0, 0, time.UTC).AddDate(0, 0, expiresInDays)},
+		IsExpired: expiresInDays < 0,
+		AutoRenew: autoRenew,
+	}
+}
+
+func TestExpiryMonitorClassify(t *testing.T) {
+	monitor := NewExpiryMonitor(setupClient(nil), nil)
+	monitor.now = func() time.Time { return expiryTestNow }
+
+	report := monitor.Classify([]Domain{
+		expiryTestDomain("later.com", 200, false),
+		expiryTestDomain("quarter.com", 60, true),
+		expiryTestDomain("month.com", 20, false),
+		expiryTestDomain("today.com", 0, false),
+		expiryTestDomain("week.com", 7, true),
+		expiryTestDomain("gone.com", -3, false),
+	})
+
+	var names, buckets []string
+	var daysLeft []int
+	for _, entry := range report.Entries {
+		names = append(names, entry.Domain.Name)
+		buckets = append(buckets, entry.Bucket)
+		daysLeft = append(daysLeft, entry.DaysLeft)
+	}
+
+	assert.Equal(t, []string{"gone.com", "today.com", "week.com", "month.com", "quarter.com", "later.com"}, names)
+	assert.Equal(t, []string{
+		ExpiryBucketExpired, ExpiryBucketWithin7Days, ExpiryBucketWithin30Days,
+		ExpiryBucketWithin30Days, ExpiryBucketWithin90Days, ExpiryBucketLater,
+	}, buckets)
+	assert.Equal(t, []int{-3, 0, 7, 20, 60, 200}, daysLeft)
+
+	assert.Len(t, report.Bucket(ExpiryBucketWithin30Days), 2)
+	assert.Len(t, report.NeedsAttention(), 3)
+	assert.Equal(t, "gone.com: EXPIRED (-3 days left), auto-renew is off\n"+
+		"today.com: WITHIN_7_DAYS (0 days left), auto-renew is off\n"+
+		"week.com: WITHIN_30_DAYS (7 days left)\n"+
+		"month.com: WITHIN_30_DAYS (20 days left), auto-renew is off\n"+
+		"quarter.com: WITHIN_90_DAYS (60 days left)", report.String())
+}
+
+func TestExpiryMonitorExpiredAutoRenew(t *testing.T) {
+	monitor := NewExpiryMonitor(setupClient(nil), nil)
+	monitor.now = func() time.Time { return expiryTestNow }
+
+	// the auto-renewal of the domain has failed
+	report := monitor.Classify([]Domain{
+		expiryTestDomain("premium.com", -2, true),
+		expiryTestDomain("mine.net", 5, true),
+	})
+
+	if assert.Len(t, report.NeedsAttention(), 1) {
+		assert.Equal(t, "premium.com", report.NeedsAttention()[0].Domain.Name)
+	}
+	assert.Equal(t, "premium.com: EXPIRED (-2 days left), auto-renewal failed\n"+
+		"mine.net: WITHIN_7_DAYS (5 days left)", report.String())
+
+	mockServer := httptest.NewServer(newCostEstimatorMockHandler("1000.00", nil))
+	defer mockServer.Close()
+	monitor.client.BaseURL = mockServer.URL
+
+	plan, err := monitor.PlanRenewals(context.TODO(), report, nil)
+	if err != nil {
+		t.Fatal("Unable to plan renewals", err)
+	}
+
+	if assert.Len(t, plan.Items, 1) {
+		assert.Equal(t, "premium.com", plan.Items[0].Estimate.Operation.Domain)
+		assert.Equal(t, ActionNameReactivate, plan.Items[0].Estimate.Operation.Action)
+	}
+}
+
+func TestExpiryMonitorRenewals(t *testing.T) {
+	fakeRenewResponse := `
+		<?xml version="1.0" encoding="utf-8"?>
+		<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+			<Errors />
+			<CommandResponse Type="namecheap.domains.renew">
+				<DomainRenewResult DomainName="new.com" DomainID="151378" Renew="true" OrderID="82171" TransactionID="137441" ChargedAmount="14.1600">
+					<DomainDetails>
+						<ExpiredDate>06/05/2022 11:59:59 PM</ExpiredDate>
+						<NumYears>0</NumYears>
+					</DomainDetails>
+				</DomainRenewResult>
+			</CommandResponse>
+		</ApiResponse>
+	`
+
+	fakeReactivateResponse := `
+		<?xml version="1.0" encoding="utf-8"?>
+		<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+			<Errors />
+			<CommandResponse Type="namecheap.domains.reactivate">
+				<DomainReactivateResult Domain="premium.com" IsSuccess="true" ChargedAmount="65.1800" OrderID="23569" TransactionID="25080" />
+			</CommandResponse>
+		</ApiResponse>
+	`
+
+	newMockServer := func(renewed *[]url.Values) *httptest.Server {
+		estimatorHandler := newCostEstimatorMockHandler("1000.00", nil)
+
+		return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			body, _ := ioutil.ReadAll(request.Body)
+			query, _ := url.ParseQuery(string(body))
+
+			switch query.Get("Command") {
+			case "namecheap.domains.renew":
+				*renewed = append(*renewed, query)
+				_, _ = writer.Write([]byte(fakeRenewResponse))
+				return
+			case "namecheap.domains.reactivate":
+				*renewed = append(*renewed, query)
+				_, _ = writer.Write([]byte(fakeReactivateResponse))
+				return
+			}
+
+			request.Body = ioutil.NopCloser(bytes.NewReader(body))
+			estimatorHandler(writer, request)
+		}))
+	}
+
+	setupMonitor := func(renewed *[]url.Values) (*ExpiryMonitor, func()) {
+		mockServer := newMockServer(renewed)
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		monitor := NewExpiryMonitor(client, nil)
+		monitor.now = func() time.Time { return expiryTestNow }
+
+		return monitor, mockServer.Close
+	}
+
+	classifier := NewExpiryMonitor(setupClient(nil), nil)
+	classifier.now = func() time.Time { return expiryTestNow }
+	report := classifier.Classify([]Domain{
+		expiryTestDomain("new.com", 3, false),
+		expiryTestDomain("premium.com", -10, false),
+		expiryTestDomain("mine.net", 5, true),
+		expiryTestDomain("later.com", 45, false),
+	})
+
+	t.Run("plan", func(t *testing.T) {
+		var renewed []url.Values
+		monitor, closeServer := setupMonitor(&renewed)
+		defer closeServer()
+
+		plan, err := monitor.PlanRenewals(context.TODO(), report, nil)
+		if err != nil {
+			t.Fatal("Unable to plan renewals", err)
+		}
+
+		if assert.Len(t, plan.Items, 2) {
+			// the expired domain can't be renewed anymore
+			assert.Equal(t, "premium.com", plan.Items[0].Estimate.Operation.Domain)
+			assert.Equal(t, ActionNameReactivate, plan.Items[0].Estimate.Operation.Action)
+			assert.Equal(t, MustParseMoney("65.18", "USD"), plan.Items[0].Estimate.Total)
+			assert.Equal(t, "new.com", plan.Items[1].Estimate.Operation.Domain)
+			assert.Equal(t, ActionNameRenew, plan.Items[1].Estimate.Operation.Action)
+			assert.Equal(t, MustParseMoney("14.16", "USD"), plan.Items[1].Estimate.Total)
+		}
+		assert.Equal(t, MustParseMoney("79.34", "USD"), plan.Total)
+	})
+
+	t.Run("execute_requires_policy", func(t *testing.T) {
+		var renewed []url.Values
+		monitor, closeServer := setupMonitor(&renewed)
+		defer closeServer()
+
+		plan, err := monitor.PlanRenewals(context.TODO(), report, nil)
+		if err != nil {
+			t.Fatal("Unable to plan renewals", err)
+		}
+
+		_, err = monitor.ExecuteRenewals(context.TODO(), plan)
+
+		assert.EqualError(t, err, "a spending policy is required to execute renewals, see Client.SetSpendingPolicy")
+		assert.Empty(t, renewed)
+	})
+
+	t.Run("execute_under_policy", func(t *testing.T) {
+		var renewed []url.Values
+		monitor, closeServer := setupMonitor(&renewed)
+		defer closeServer()
+
+		monitor.client.SetSpendingPolicy(&SpendingPolicy{MaxChargePerOperation: MustParseMoney("15", "USD")})
+
+		plan, err := monitor.PlanRenewals(context.TODO(), report, nil)
+		if err != nil {
+			t.Fatal("Unable to plan renewals", err)
+		}
+
+		// the premium reactivation is the first one and breaks the policy limit
+		outcomes, err := monitor.ExecuteRenewals(context.TODO(), plan)
+		assert.EqualError(t, err, "unable to reactivate premium.com: spending policy rejected reactivate premium.com for 1 year(s) estimated at 65.18 USD: premium domains aren't allowed")
+		assert.Len(t, outcomes, 1)
+		assert.Empty(t, renewed)
+
+		plan.Items = plan.Items[1:]
+		outcomes, err = monitor.ExecuteRenewals(context.TODO(), plan)
+		if err != nil {
+			t.Fatal("Unable to renew", err)
+		}
+
+		if assert.Len(t, outcomes, 1) {
+			assert.True(t, outcomes[0].Result.Renew)
+			assert.Equal(t, "06/05/2022 11:59:59 PM", outcomes[0].Result.ExpiredDate)
+		}
+		if assert.Len(t, renewed, 1) {
+			assert.Equal(t, "new.com", renewed[0].Get("DomainName"))
+			assert.Equal(t, "1", renewed[0].Get("Years"))
+		}
+		assert.Equal(t, MustParseMoney("14.16", "USD"), monitor.client.Spent())
+	})
+
+	t.Run("execute_reactivation", func(t *testing.T) {
+		var renewed []url.Values
+		monitor, closeServer := setupMonitor(&renewed)
+		defer closeServer()
+
+		monitor.client.SetSpendingPolicy(&SpendingPolicy{AllowPremium: true})
+
+		plan, err := monitor.PlanRenewals(context.TODO(), report, nil)
+		if err != nil {
+			t.Fatal("Unable to plan renewals", err)
+		}
+
+		outcomes, err := monitor.ExecuteRenewals(context.TODO(), plan)
+		if err != nil {
+			t.Fatal("Unable to renew", err)
+		}
+
+		if assert.Len(t, outcomes, 2) {
+			assert.Nil(t, outcomes[0].Result)
+			assert.True(t, outcomes[0].ReactivateResult.IsSuccess)
+			assert.True(t, outcomes[1].Result.Renew)
+		}
+		if assert.Len(t, renewed, 2) {
+			assert.Equal(t, "namecheap.domains.reactivate", renewed[0].Get("Command"))
+			assert.Equal(t, "premium.com", renewed[0].Get("DomainName"))
+			assert.Equal(t, "65.00", renewed[0].Get("PremiumPrice"))
+			assert.Equal(t, "namecheap.domains.renew", renewed[1].Get("Command"))
+		}
+		assert.Equal(t, MustParseMoney("79.34", "USD"), monitor.client.Spent())
+	})
+}
@@ -0,0 +1,409 @@
+package namecheap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// requiredExtendedAttributesTLDs are the TLDs the registries won't register without extended attributes
+var requiredExtendedAttributesTLDs = []string{
+	"us", "eu", "ca", "co.uk", "org.uk", "me.uk", "nu", "com.au", "net.au", "org.au",
+	"es", "nom.es", "com.es", "org.es", "de", "fr",
+}
+
+// ExtendedAttributes are the registry-specific attributes some TLDs require on registration.
+// Use one of USExtendedAttributes, CAExtendedAttributes, EUExtendedAttributes, UKExtendedAttributes,
+// AUExtendedAttributes, or RawExtendedAttributes for the TLDs not covered by a typed struct.
+type ExtendedAttributes interface {
+	// TLDs returns the TLDs the attributes apply to, nil means any TLD
+	TLDs() []string
+
+	validate(issues *ValidationError)
+	toParams(params map[string]string)
+}
+
+// IsExtendedAttributesRequired reports whether the TLD requires extended attributes on registration
+func IsExtendedAttributesRequired(tld string) bool {
+	return isOneOf(strings.ToLower(tld), requiredExtendedAttributesTLDs)
+}
+
+// validateExtendedAttributes checks that the attributes are set when the TLD requires them,
+// that they apply to the TLD and that their values are valid
+func validateExtendedAttributes(tld string, attributes ExtendedAttributes, issues *ValidationError) {
+	// an empty raw map sends no attributes at all, same as nil
+	if raw, ok := attributes.(RawExtendedAttributes); ok && len(raw) == 0 {
+		attributes = nil
+	}
+	if attributes == nil {
+		if IsExtendedAttributesRequired(tld) {
+			issues.add("ExtendedAttributes", fmt.Sprintf("ExtendedAttributes is required for .%s domains", tld))
+		}
+		return
+	}
+
+	if tlds := attributes.TLDs(); tlds != nil && !isOneOf(strings.ToLower(tld), tlds) {
+		issues.add("ExtendedAttributes", fmt.Sprintf("invalid ExtendedAttributes value: %T doesn't apply to .%s domains", attributes, tld))
+		return
+	}
+
+	attributes.validate(issues)
+}
+
+// extendedAttributesToParams adds the attributes to the params of the create, setContacts and transfer commands
+func extendedAttributesToParams(attributes ExtendedAttributes, params map[string]string) {
+	if attributes != nil {
+		attributes.toParams(params)
+	}
+}
+
+// USExtendedAttributes are the .us nexus attributes
+type USExtendedAttributes struct {
+	// Possible values are C11 (US citizen), C12 (permanent resident), C21 (US organization),
+	// C31 (foreign entity regularly engaging in activities in the US), C32 (foreign entity with an office in the US)
+	Nexus string
+	// ISO 3166-1 alpha-2 code of the entity's country, required for the C31 and C32 nexus categories
+	NexusCountry string
+	// Possible values are P1 (business), P2 (non-profit), P3 (personal), P4 (educational), P5 (government)
+	Purpose string
+}
+
+var (
+	allowedUSNexusValues   = []string{"C11", "C12", "C21", "C31", "C32"}
+	allowedUSPurposeValues = []string{"P1", "P2", "P3", "P4", "P5"}
+)
+
+func (a USExtendedAttributes) TLDs() []string {
+	return []string{"us"}
+}
+
+func (a USExtendedAttributes) validate(issues *ValidationError) {
+	if a.Nexus == "" {
+		issues.add("RegistrantNexus", "RegistrantNexus is required")
+	} else if !isOneOf(a.Nexus, allowedUSNexusValues) {
+		issues.add("RegistrantNexus", fmt.Sprintf("invalid RegistrantNexus value: %s, allowed values are %s", a.Nexus, strings.Join(allowedUSNexusValues, ", ")))
+	}
+
+	if a.NexusCountry == "" {
+		if a.Nexus == "C31" || a.Nexus == "C32" {
+			issues.add("RegistrantNexusCountry", fmt.Sprintf("RegistrantNexusCountry is required for the %s nexus", a.Nexus))
+		}
+	} else if !isValidCountryCode(a.NexusCountry) {
+		issues.add("RegistrantNexusCountry", fmt.Sprintf("invalid RegistrantNexusCountry value: %s, must be an ISO 3166-1 alpha-2 code", a.NexusCountry))
+	}
+
+	if a.Purpose == "" {
+		issues.add("RegistrantPurpose", "RegistrantPurpose is required")
+	} else if !isOneOf(a.Purpose, allowedUSPurposeValues) {
+		issues.add("RegistrantPurpose", fmt.Sprintf("invalid RegistrantPurpose value: %s, allowed values are %s", a.Purpose, strings.Join(allowedUSPurposeValues, ", ")))
+	}
+}
+
+func (a USExtendedAttributes) toParams(params map[string]string) {
+	params["RegistrantNexus"] = a.Nexus
+	if a.NexusCountry != "" {
+		params["RegistrantNexusCountry"] = strings.ToUpper(a.NexusCountry)
+	}
+	params["RegistrantPurpose"] = a.Purpose
+}
+
+// CAExtendedAttributes are the .ca attributes of the Canadian Internet Registration Authority (CIRA)
+type CAExtendedAttributes struct {
+	// Legal type of the registrant, e.g. CCT (Canadian citizen), CCO (Canadian corporation), RES (permanent resident)
+	LegalType string
+	// Possible values are FULL and PRIVATE, the latter is only honored for individuals -- Default Value: PRIVATE
+	WhoisDisplay string
+	// Version of the CIRA registrant agreement accepted, e.g. 2.0
+	AgreementVersion string
+	// Whether the registrant accepted the CIRA registrant agreement, required
+	AgreementAccepted bool
+	// Possible values are en and fr -- Default Value: en
+	Language string
+}
+
+var (
+	allowedCALegalTypeValues = []string{
+		"CCO", "CCT", "RES", "GOV", "EDU", "ASS", "HOP", "PRT", "TDM", "TRD",
+		"PLT", "LAM", "TRS", "ABO", "INB", "LGR", "OMK", "MAJ",
+	}
+	allowedCAWhoisDisplayValues = []string{"FULL", "PRIVATE"}
+	allowedCALanguageValues     = []string{"en", "fr"}
+)
+
+func (a CAExtendedAttributes) TLDs() []string {
+	return []string{"ca"}
+}
+
+func (a CAExtendedAttributes) validate(issues *ValidationError) {
+	if a.LegalType == "" {
+		issues.add("CIRALegalType", "CIRALegalType is required")
+	} else if !isOneOf(a.LegalType, allowedCALegalTypeValues) {
+		issues.add("CIRALegalType", fmt.Sprintf("invalid CIRALegalType value: %s, allowed values are %s", a.LegalType, strings.Join(allowedCALegalTypeValues, ", ")))
+	}
+
+	if a.WhoisDisplay != "" && !isOneOf(a.WhoisDisplay, allowedCAWhoisDisplayValues) {
+		issues.add("CIRAWhoisDisplay", fmt.Sprintf("invalid CIRAWhoisDisplay value: %s, allowed values are %s", a.WhoisDisplay, strings.Join(allowedCAWhoisDisplayValues, ", ")))
+	}
+
+	if a.AgreementVersion == "" {
+		issues.add("CIRAAgreementVersion", "CIRAAgreementVersion is required")
+	}
+
+	if !a.AgreementAccepted {
+		issues.add("CIRAAgreementValue", "CIRAAgreementValue is required, the registrant must accept the CIRA registrant agreement")
+	}
+
+	if a.Language != "" && !isOneOf(a.Language, allowedCALanguageValues) {
+		issues.add("CIRALanguage", fmt.Sprintf("invalid CIRALanguage value: %s, allowed values are %s", a.Language, strings.Join(allowedCALanguageValues, ", ")))
+	}
+}
+
+func (a CAExtendedAttributes) toParams(params map[string]string) {
+	params["CIRALegalType"] = a.LegalType
+	if a.WhoisDisplay != "" {
+		params["CIRAWhoisDisplay"] = a.WhoisDisplay
+	}
+	params["CIRAAgreementVersion"] = a.AgreementVersion
+	params["CIRAAgreementValue"] = boolToYesNo(a.AgreementAccepted, "Y", "N")
+	if a.Language != "" {
+		params["CIRALanguage"] = a.Language
+	}
+}
+
+// EUExtendedAttributes are the .eu policy agreements
+type EUExtendedAttributes struct {
+	// Whether the registrant agrees to the .eu WHOIS policy, required
+	AgreeWhoisPolicy bool
+	// Whether the registrant agrees to the .eu deletion policy, required
+	AgreeDeletePolicy bool
+	// Language of the alternative dispute resolution, two-letter code, e.g. EN -- Default Value: EN
+	ADRLanguage string
+}
+
+func (a EUExtendedAttributes) TLDs() []string {
+	return []string{"eu"}
+}
+
+func (a EUExtendedAttributes) validate(issues *ValidationError) {
+	if !a.AgreeWhoisPolicy {
+		issues.add("EUAgreeWhoisPolicy", "EUAgreeWhoisPolicy is required, the registrant must agree to the .eu WHOIS policy")
+	}
+
+	if !a.AgreeDeletePolicy {
+		issues.add("EUAgreeDeletePolicy", "EUAgreeDeletePolicy is required, the registrant must agree to the .eu deletion policy")
+	}
+
+	if a.ADRLanguage != "" && len(a.ADRLanguage) != 2 {
+		issues.add("EUADRLang", fmt.Sprintf("invalid EUADRLang value: %s, must be a two-letter language code", a.ADRLanguage))
+	}
+}
+
+func (a EUExtendedAttributes) toParams(params map[string]string) {
+	params["EUAgreeWhoisPolicy"] = boolToYesNo(a.AgreeWhoisPolicy, "YES", "NO")
+	params["EUAgreeDeletePolicy"] = boolToYesNo(a.AgreeDeletePolicy, "YES", "NO")
+	if a.ADRLanguage != "" {
+		params["EUADRLang"] = strings.ToUpper(a.ADRLanguage)
+	}
+}
+
+// UKExtendedAttributes are the Nominet attributes of the .co.uk, .org.uk and .me.uk domains
+type UKExtendedAttributes struct {
+	// Legal type of the registrant, e.g. IND (individual), LTD (UK limited company), FCORP (non-UK corporation)
+	LegalType string
+	// Company or charity registration number, required for the LTD, PLC, LLP, IP, SCH and RCHAR legal types
+	CompanyID string
+	// Name of the person or organization the domain is registered for -- Default Value: the registrant name
+	RegisteredFor string
+}
+
+var (
+	allowedUKLegalTypeValues = []string{
+		"IND", "FIND", "LTD", "PLC", "PTNR", "LLP", "IP", "STRA", "SCH", "RCHAR",
+		"GOV", "CRO", "STAT", "OTHER", "FCORP", "FOTHER",
+	}
+	ukLegalTypesWithCompanyID = []string{"LTD", "PLC", "LLP", "IP", "SCH", "RCHAR"}
+)
+
+func (a UKExtendedAttributes) TLDs() []string {
+	return []string{"co.uk", "org.uk", "me.uk"}
+}
+
+func (a UKExtendedAttributes) validate(issues *ValidationError) {
+	if a.LegalType == "" {
+		issues.add("COUKLegalType", "COUKLegalType is required")
+	} else if !isOneOf(a.LegalType, allowedUKLegalTypeValues) {
+		issues.add("COUKLegalType", fmt.Sprintf("invalid COUKLegalType value: %s, allowed values are %s", a.LegalType, strings.Join(allowedUKLegalTypeValues, ", ")))
+	}
+
+	if a.CompanyID == "" && isOneOf(a.LegalType, ukLegalTypesWithCompanyID) {
+		issues.add("COUKCompanyID", fmt.Sprintf("COUKCompanyID is required for the %s legal type", a.LegalType))
+	}
+}
+
+func (a UKExtendedAttributes) toParams(params map[string]string) {
+	params["COUKLegalType"] = a.LegalType
+	if a.CompanyID != "" {
+		params["COUKCompanyID"] = a.CompanyID
+	}
+	if a.RegisteredFor != "" {
+		params["COUKRegisteredfor"] = a.RegisteredFor
+	}
+}
+
+// AUExtendedAttributes are the auDA eligibility attributes of the .com.au, .net.au and .org.au domains
+type AUExtendedAttributes struct {
+	// Registrant identification number, e.g. the ABN or ACN
+	RegistrantID string
+	// Possible values are ABN, ACN, RBN, TM and OTHER
+	RegistrantIDType string
+	// Eligibility type of the registrant, e.g. Company, Registered Business, Trademark Owner
+	EligibilityType string
+	// Name of the eligibility entity, required when it differs from the registrant, e.g. a trademark
+	EligibilityName string
+	// Identification number of the eligibility entity, required along with EligibilityName
+	EligibilityID string
+	// Possible values are ABN, ACN, RBN, TM and OTHER, required along with EligibilityID
+	EligibilityIDType string
+	// Possible values are 1 (domain is an exact match, abbreviation or acronym of the registrant name or trademark)
+	// and 2 (domain is closely and substantially connected to the registrant)
+	PolicyReason string
+}
+
+var (
+	allowedAUIDTypeValues       = []string{"ABN", "ACN", "RBN", "TM", "OTHER"}
+	allowedAUPolicyReasonValues = []string{"1", "2"}
+)
+
+func (a AUExtendedAttributes) TLDs() []string {
+	return []string{"com.au", "net.au", "org.au"}
+}
+
+func (a AUExtendedAttributes) validate(issues *ValidationError) {
+	if a.RegistrantID == "" {
+		issues.add("COMAURegistrantId", "COMAURegistrantId is required")
+	}
+
+	if a.RegistrantIDType == "" {
+		issues.add("COMAURegistrantIdType", "COMAURegistrantIdType is required")
+	} else if !isOneOf(a.RegistrantIDType, allowedAUIDTypeValues) {
+		issues.add("COMAURegistrantIdType", fmt.Sprintf("invalid COMAURegistrantIdType value: %s, allowed values are %s", a.RegistrantIDType, strings.Join(allowedAUIDTypeValues, ", ")))
+	}
+
+	if a.EligibilityType == "" {
+		issues.add("COMAUEligibilityType", "COMAUEligibilityType is required")
+	}
+
+	if a.EligibilityName != "" && a.EligibilityID == "" {
+		issues.add("COMAUEligibilityID", "COMAUEligibilityID is required along with COMAUEligibilityName")
+	}
+
+	if a.EligibilityID != "" && a.EligibilityIDType == "" {
+		issues.add("COMAUEligibilityIDType", "COMAUEligibilityIDType is required along with COMAUEligibilityID")
+	} else if a.EligibilityIDType != "" && !isOneOf(a.EligibilityIDType, allowedAUIDTypeValues) {
+		issues.add("COMAUEligibilityIDType", fmt.Sprintf("invalid COMAUEligibilityIDType value: %s, allowed values are %s", a.EligibilityIDType, strings.Join(allowedAUIDTypeValues, ", ")))
+	}
+
+	if a.PolicyReason == "" {
+		issues.add("COMAUPolicyReason", "COMAUPolicyReason is required")
+	} else if !isOneOf(a.PolicyReason, allowedAUPolicyReasonValues) {
+		issues.add("COMAUPolicyReason", fmt.Sprintf("invalid COMAUPolicyReason value: %s, allowed values are %s", a.PolicyReason, strings.Join(allowedAUPolicyReasonValues, ", ")))
+	}
+}
+
+func (a AUExtendedAttributes) toParams(params map[string]string) {
+	values := map[string]string{
+		"COMAURegistrantId":      a.RegistrantID,
+		"COMAURegistrantIdType":  a.RegistrantIDType,
+		"COMAUEligibilityType":   a.EligibilityType,
+		"COMAUEligibilityName":   a.EligibilityName,
+		"COMAUEligibilityID":     a.EligibilityID,
+		"COMAUEligibilityIDType": a.EligibilityIDType,
+		"COMAUPolicyReason":      a.PolicyReason,
+	}
+
+	for name, value := range values {
+		if value != "" {
+			params[name] = value
+		}
+	}
+}
+
+// RawExtendedAttributes are sent as-is, use them for the TLDs without a typed struct, e.g. .de, .fr, .es or .nu.
+// The names and values of each TLD are described in the Namecheap API doc.
+type RawExtendedAttributes map[string]string
+
+func (a RawExtendedAttributes) TLDs() []string {
+	return nil
+}
+
+func (a RawExtendedAttributes) validate(issues *ValidationError) {
+	names := make([]string, 0, len(a))
+	for name := range a {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if name == "" || strings.TrimSpace(a[name]) == "" {
+			issues.add("ExtendedAttributes", fmt.Sprintf("invalid ExtendedAttributes value: %q has an empty name or value", name))
+		} else if isCoreParam(name) {
+			issues.add("ExtendedAttributes", fmt.Sprintf("invalid ExtendedAttributes value: %q is a parameter of the command, not an extended attribute", name))
+		}
+	}
+}
+
+// toParams never overwrites the params of the command, even if the attributes haven't been validated
+func (a RawExtendedAttributes) toParams(params map[string]string) {
+	for name, value := range a {
+		if !isCoreParam(name) {
+			params[name] = value
+		}
+	}
+}
+
+// coreParams are the params of the commands accepting the extended attributes, and the authentication ones
+var coreParams = []string{
+	"Command", "ApiUser", "ApiKey", "UserName", "ClientIp",
+	"DomainName", "Years", "PromotionCode", "IdnCode", "Nameservers", "EPPCode",
+	"AddFreeWhoisguard", "WGEnabled", "IsPremiumDomain", "PremiumPrice", "EapFee",
+}
+
+// contactParamFields are the names of the contact params without the role prefix, see Contact.toParams
+var contactParamFields = []string{
+	"OrganizationName", "JobTitle", "FirstName", "LastName", "Address1", "Address2", "City",
+	"StateProvince", "StateProvinceChoice", "PostalCode", "Country", "Phone", "PhoneExt", "Fax", "EmailAddress",
+}
+
+// isCoreParam reports whether the name is a param of the commands, compared case-insensitively to be on the safe side
+func isCoreParam(name string) bool {
+	for _, coreParam := range coreParams {
+		if strings.EqualFold(name, coreParam) {
+			return true
+		}
+	}
+	roles := append([]string{ContactRoleBilling}, RequiredContactRoles...)
+	for _, role := range roles {
+		for _, field := range contactParamFields {
+			if strings.EqualFold(name, role+field) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func boolToYesNo(value bool, yes string, no string) string {
+	if value {
+		return yes
+	}
+	return no
+}
+
+func isOneOf(value string, allowedValues []string) bool {
+	for _, allowedValue := range allowedValues {
+		if value == allowedValue {
+			return true
+		}
+	}
+	return false
+}
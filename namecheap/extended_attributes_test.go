@@ -0,0 +1,145 @@
+package namecheap
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtendedAttributesValidate(t *testing.T) {
+	t.Run("required_for_tld", func(t *testing.T) {
+		args := NewDomainCreateArgs("domain.ca", 1, fakeContact)
+
+		assert.EqualError(t, args.Validate(), "ExtendedAttributes is required for .ca domains")
+
+		args = NewDomainCreateArgs("domain.com", 1, fakeContact)
+		assert.Nil(t, args.Validate())
+	})
+
+	t.Run("wrong_tld", func(t *testing.T) {
+		args := NewDomainCreateArgs("domain.co.uk", 1, fakeContact)
+		args.ExtendedAttributes = USExtendedAttributes{Nexus: "C11", Purpose: "P3"}
+
+		assert.EqualError(t, args.Validate(), "invalid ExtendedAttributes value: namecheap.USExtendedAttributes doesn't apply to .co.uk domains")
+	})
+
+	t.Run("us_invalid_values", func(t *testing.T) {
+		args := NewDomainCreateArgs("domain.us", 1, fakeContact)
+		args.ExtendedAttributes = USExtendedAttributes{Nexus: "C31", Purpose: "P9"}
+
+		err := args.Validate()
+
+		var validationErr *ValidationError
+		if assert.True(t, errors.As(err, &validationErr)) {
+			assert.Equal(t, []string{"RegistrantNexusCountry", "RegistrantPurpose"}, validationErr.Fields())
+		}
+		assert.Contains(t, err.Error(), "RegistrantNexusCountry is required for the C31 nexus")
+		assert.Contains(t, err.Error(), "invalid RegistrantPurpose value: P9, allowed values are P1, P2, P3, P4, P5")
+	})
+
+	t.Run("ca_agreement_required", func(t *testing.T) {
+		err := validateAttributes("ca", CAExtendedAttributes{LegalType: "CCT"})
+
+		assert.EqualError(t, err, "CIRAAgreementVersion is required; CIRAAgreementValue is required, the registrant must accept the CIRA registrant agreement")
+	})
+
+	t.Run("uk_company_id", func(t *testing.T) {
+		assert.EqualError(t, validateAttributes("co.uk", UKExtendedAttributes{LegalType: "LTD"}), "COUKCompanyID is required for the LTD legal type")
+		assert.Nil(t, validateAttributes("me.uk", UKExtendedAttributes{LegalType: "IND"}))
+	})
+
+	t.Run("au_eligibility", func(t *testing.T) {
+		err := validateAttributes("com.au", AUExtendedAttributes{
+			RegistrantID:     "12345678901",
+			RegistrantIDType: "ABN",
+			EligibilityType:  "Company",
+			EligibilityName:  "Trademark",
+			PolicyReason:     "3",
+		})
+
+		assert.EqualError(t, err, "COMAUEligibilityID is required along with COMAUEligibilityName; invalid COMAUPolicyReason value: 3, allowed values are 1, 2")
+	})
+
+	t.Run("raw_any_tld", func(t *testing.T) {
+		assert.Nil(t, validateAttributes("de", RawExtendedAttributes{"DEConfirmAddress": "DE"}))
+		assert.EqualError(t, validateAttributes("fr", RawExtendedAttributes{"FRLegalType": ""}), `invalid ExtendedAttributes value: "FRLegalType" has an empty name or value`)
+	})
+
+	t.Run("raw_empty_required", func(t *testing.T) {
+		assert.EqualError(t, validateAttributes("us", RawExtendedAttributes{}), "ExtendedAttributes is required for .us domains")
+		assert.EqualError(t, validateAttributes("ca", RawExtendedAttributes(nil)), "ExtendedAttributes is required for .ca domains")
+		assert.Nil(t, validateAttributes("com", RawExtendedAttributes{}))
+	})
+
+	t.Run("raw_core_params", func(t *testing.T) {
+		err := validateAttributes("de", RawExtendedAttributes{"DomainName": "other.de", "years": "10", "RegistrantEmailAddress": "x@example.com", "DEConfirmAddress": "DE"})
+
+		assert.EqualError(t, err, `invalid ExtendedAttributes value: "DomainName" is a parameter of the command, not an extended attribute; `+
+			`invalid ExtendedAttributes value: "RegistrantEmailAddress" is a parameter of the command, not an extended attribute; `+
+			`invalid ExtendedAttributes value: "years" is a parameter of the command, not an extended attribute`)
+
+		params := map[string]string{"DomainName": "domain.de"}
+		RawExtendedAttributes{"DomainName": "other.de", "RegistrantNexus": "C11"}.toParams(params)
+		assert.Equal(t, map[string]string{"DomainName": "domain.de", "RegistrantNexus": "C11"}, params)
+	})
+}
+
+func validateAttributes(tld string, attributes ExtendedAttributes) error {
+	issues := &ValidationError{}
+	validateExtendedAttributes(tld, attributes, issues)
+	return issues.errorOrNil()
+}
+
+func TestExtendedAttributesParams(t *testing.T) {
+	var sentQuery url.Values
+	mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := ioutil.ReadAll(request.Body)
+		sentQuery, _ = url.ParseQuery(string(body))
+		_, _ = writer.Write([]byte(newPolicyTestCreateResponse("domain.ca", "13.98")))
+	}))
+	defer mockServer.Close()
+
+	client := setupClient(nil)
+	client.BaseURL = mockServer.URL
+
+	t.Run("ca", func(t *testing.T) {
+		args := NewDomainCreateArgs("domain.ca", 1, fakeContact)
+		args.ExtendedAttributes = CAExtendedAttributes{
+			LegalType:         "CCT",
+			WhoisDisplay:      "PRIVATE",
+			AgreementVersion:  "2.0",
+			AgreementAccepted: true,
+		}
+
+		_, err := client.Domains.Create(context.TODO(), args)
+		if err != nil {
+			t.Fatal("Unable to create domain", err)
+		}
+
+		assert.Equal(t, "CCT", sentQuery.Get("CIRALegalType"))
+		assert.Equal(t, "PRIVATE", sentQuery.Get("CIRAWhoisDisplay"))
+		assert.Equal(t, "2.0", sentQuery.Get("CIRAAgreementVersion"))
+		assert.Equal(t, "Y", sentQuery.Get("CIRAAgreementValue"))
+		assert.NotContains(t, sentQuery, "CIRALanguage")
+	})
+
+	t.Run("us", func(t *testing.T) {
+		args := NewDomainCreateArgs("domain.us", 1, fakeContact)
+		args.ExtendedAttributes = USExtendedAttributes{Nexus: "C32", NexusCountry: "ca", Purpose: "P1"}
+
+		_, err := client.Domains.Create(context.TODO(), args)
+		if err != nil {
+			t.Fatal("Unable to create domain", err)
+		}
+
+		assert.Equal(t, "C32", sentQuery.Get("RegistrantNexus"))
+		assert.Equal(t, "CA", sentQuery.Get("RegistrantNexusCountry"))
+		assert.Equal(t, "P1", sentQuery.Get("RegistrantPurpose"))
+	})
+}
@@ -0,0 +1,277 @@
+package namecheap
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	InventoryFormatCSV  = "csv"
+	InventoryFormatJSON = "json"
+	InventoryFormatYAML = "yaml"
+)
+
+const (
+	defaultInventoryConcurrency = 2
+	inventoryDateLayout         = "2006-01-02"
+)
+
+// InventoryOptions are the options of NewInventoryExporter
+type InventoryOptions struct {
+	// Number of domains fetched in parallel
+	// Default value: 2
+	Concurrency int
+}
+
+// InventoryRecord is the state of a single domain of the account
+type InventoryRecord struct {
+	Domain    string `json:"domain" yaml:"domain"`
+	Created   string `json:"created" yaml:"created"`
+	Expires   string `json:"expires" yaml:"expires"`
+	IsExpired bool   `json:"isExpired" yaml:"isExpired"`
+	AutoRenew bool   `json:"autoRenew" yaml:"autoRenew"`
+	IsLocked  bool   `json:"isLocked" yaml:"isLocked"`
+	// Domain privacy status, e.g. ENABLED, NOTPRESENT
+	Privacy   string `json:"privacy" yaml:"privacy"`
+	IsPremium bool   `json:"isPremium" yaml:"isPremium"`
+
+	// DNS provider type, e.g. FREE, CUSTOM
+	DNSProvider   string   `json:"dnsProvider" yaml:"dnsProvider"`
+	IsUsingOurDNS bool     `json:"isUsingOurDns" yaml:"isUsingOurDns"`
+	IsPremiumDNS  bool     `json:"isPremiumDns" yaml:"isPremiumDns"`
+	Nameservers   []string `json:"nameservers" yaml:"nameservers"`
+
+	// Number of the host records, only known for the domains using the Namecheap DNS
+	RecordCount int `json:"recordCount" yaml:"recordCount"`
+	// Number of the host records by type, e.g. A, MX
+	RecordCounts map[string]int `json:"recordCounts,omitempty" yaml:"recordCounts,omitempty"`
+
+	// Error of the lookups of the domain, the fields depending on the failed lookup are left empty
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Inventory is the state of all the domains of the account, sorted by the domain name
+type Inventory struct {
+	Records []InventoryRecord `json:"records" yaml:"records"`
+}
+
+// InventoryExporter joins the domain list, domain info, nameservers and host records of all the domains into an Inventory
+type InventoryExporter struct {
+	client  *Client
+	options InventoryOptions
+}
+
+// NewInventoryExporter returns an exporter, nil options mean the defaults
+func NewInventoryExporter(client *Client, options *InventoryOptions) *InventoryExporter {
+	exporter := &InventoryExporter{client: client}
+	if options != nil {
+		exporter.options = *options
+	}
+	if exporter.options.Concurrency <= 0 {
+		exporter.options.Concurrency = defaultInventoryConcurrency
+	}
+	return exporter
+}
+
+// Build fetches the inventory of all the domains of the account.
+// A failed lookup of a domain doesn't stop the export, it's reported in InventoryRecord.Error and
+// the inventory is returned along with an error listing the failed domains.
+func (ie *InventoryExporter) Build(ctx context.Context) (*Inventory, error) {
+	domains, err := ie.client.Domains.ListAll(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]InventoryRecord, len(domains))
+
+	semaphore := make(chan struct{}, ie.options.Concurrency)
+	var wg sync.WaitGroup
+
+	for i, domain := range domains {
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			records[i] = newInventoryRecord(domain)
+			records[i].Error = ctx.Err().Error()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, domain Domain) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			records[i] = ie.buildRecord(ctx, domain)
+		}(i, domain)
+	}
+
+	wg.Wait()
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Domain < records[j].Domain
+	})
+
+	inventory := &Inventory{Records: records}
+
+	errMessages := []string{}
+	for _, record := range records {
+		if record.Error != "" {
+			errMessages = append(errMessages, fmt.Sprintf("%s: %s", record.Domain, record.Error))
+		}
+	}
+	if len(errMessages) > 0 {
+		return inventory, fmt.Errorf("%s", strings.Join(errMessages, "; "))
+	}
+
+	return inventory, nil
+}
+
+func newInventoryRecord(domain Domain) InventoryRecord {
+	record := InventoryRecord{
+		Domain:    strings.ToLower(domain.Name),
+		IsExpired: domain.IsExpired,
+		AutoRenew: domain.AutoRenew,
+		IsLocked:  domain.IsLocked,
+		Privacy:   domain.WhoisGuard,
+		IsPremium: domain.IsPremium,
+
+		IsUsingOurDNS: domain.IsOurDNS,
+	}
+	if !domain.Created.IsZero() {
+		record.Created = domain.Created.Format(inventoryDateLayout)
+	}
+	if !domain.Expires.IsZero() {
+		record.Expires = domain.Expires.Format(inventoryDateLayout)
+	}
+	return record
+}
+
+func (ie *InventoryExporter) buildRecord(ctx context.Context, domain Domain) InventoryRecord {
+	record := newInventoryRecord(domain)
+	var errMessages []string
+
+	info, err := ie.client.Domains.GetInfo(ctx, record.Domain)
+	if err != nil {
+		errMessages = append(errMessages, fmt.Sprintf("info: %v", err))
+	} else {
+		details := info.DomainDNSGetListResult
+		record.IsPremium = record.IsPremium || details.IsPremium
+		record.DNSProvider = details.DnsDetails.ProviderType
+		record.IsPremiumDNS = details.PremiumDnsSubscription.IsActive
+	}
+
+	nameservers, err := ie.client.DomainsDNS.GetList(ctx, record.Domain)
+	if err != nil {
+		errMessages = append(errMessages, fmt.Sprintf("nameservers: %v", err))
+	} else {
+		record.IsUsingOurDNS = nameservers.DomainDNSGetListResult.IsUsingOurDNS
+		record.IsPremiumDNS = record.IsPremiumDNS || nameservers.DomainDNSGetListResult.IsPremiumDNS
+		record.Nameservers = nameservers.DomainDNSGetListResult.Nameservers
+	}
+
+	// the host records are only managed for the domains using the Namecheap DNS
+	if record.IsUsingOurDNS {
+		hosts, err := ie.client.DomainsDNS.GetHosts(ctx, record.Domain)
+		if err != nil {
+			errMessages = append(errMessages, fmt.Sprintf("hosts: %v", err))
+		} else {
+			record.RecordCount = len(hosts.DomainDNSGetHostsResult.Hosts)
+			record.RecordCounts = map[string]int{}
+			for _, host := range hosts.DomainDNSGetHostsResult.Hosts {
+				record.RecordCounts[host.Type]++
+			}
+		}
+	}
+
+	record.Error = strings.Join(errMessages, "; ")
+	return record
+}
+
+// Write writes the inventory in the format: csv, json or yaml
+func (inv *Inventory) Write(w io.Writer, format string) error {
+	switch strings.ToLower(format) {
+	case InventoryFormatCSV:
+		return inv.WriteCSV(w)
+	case InventoryFormatJSON:
+		return inv.WriteJSON(w)
+	case InventoryFormatYAML, "yml":
+		return inv.WriteYAML(w)
+	}
+	return fmt.Errorf("invalid format value: %s, allowed values are csv, json, yaml", format)
+}
+
+// WriteJSON writes the inventory as indented JSON
+func (inv *Inventory) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(inv)
+}
+
+// WriteYAML writes the inventory as YAML
+func (inv *Inventory) WriteYAML(w io.Writer) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(inv); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// WriteCSV writes the inventory as CSV with a header row, one domain per row.
+// The nameservers are joined by spaces, the record counts by type are written as A=2;MX=1.
+func (inv *Inventory) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write([]string{
+		"domain", "created", "expires", "is_expired", "auto_renew", "is_locked", "privacy", "is_premium",
+		"dns_provider", "is_using_our_dns", "is_premium_dns", "nameservers", "record_count", "record_counts", "error",
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, record := range inv.Records {
+		types := make([]string, 0, len(record.RecordCounts))
+		for recordType := range record.RecordCounts {
+			types = append(types, recordType)
+		}
+		sort.Strings(types)
+
+		counts := make([]string, 0, len(types))
+		for _, recordType := range types {
+			counts = append(counts, fmt.Sprintf("%s=%d", recordType, record.RecordCounts[recordType]))
+		}
+
+		err = writer.Write([]string{
+			record.Domain,
+			record.Created,
+			record.Expires,
+			strconv.FormatBool(record.IsExpired),
+			strconv.FormatBool(record.AutoRenew),
+			strconv.FormatBool(record.IsLocked),
+			record.Privacy,
+			strconv.FormatBool(record.IsPremium),
+			record.DNSProvider,
+			strconv.FormatBool(record.IsUsingOurDNS),
+			strconv.FormatBool(record.IsPremiumDNS),
+			strings.Join(record.Nameservers, " "),
+			strconv.Itoa(record.RecordCount),
+			strings.Join(counts, ";"),
+			record.Error,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,151 @@
+package namecheap
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+// newInventoryMockServer serves two domains: alpha.com on the Namecheap DNS with 3 host records,
+// and beta.net on custom nameservers whose info lookup fails
+func newInventoryMockServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := ioutil.ReadAll(request.Body)
+		query, _ := url.ParseQuery(string(body))
+
+		domain := query.Get("DomainName")
+		if domain == "" {
+			domain = query.Get("SLD") + "." + query.Get("TLD")
+		}
+
+		var response string
+		switch query.Get("Command") {
+		case "namecheap.domains.getList":
+			response = `
+				<CommandResponse Type="namecheap.domains.getList">
+					<DomainGetListResult>
+						<Domain ID="2" Name="beta.net" User="user" Created="01/15/2020" Expires="01/15/2022" IsExpired="false" IsLocked="false" AutoRenew="false" WhoisGuard="NOTPRESENT" IsPremium="false" IsOurDNS="false" />
+						<Domain ID="1" Name="alpha.com" User="user" Created="06/02/2021" Expires="06/02/2022" IsExpired="false" IsLocked="true" AutoRenew="true" WhoisGuard="ENABLED" IsPremium="false" IsOurDNS="true" />
+					</DomainGetListResult>
+					<Paging><TotalItems>2</TotalItems><CurrentPage>1</CurrentPage><PageSize>100</PageSize></Paging>
+				</CommandResponse>`
+		case "namecheap.domains.getInfo":
+			if domain == "beta.net" {
+				_, _ = writer.Write([]byte(`<ApiResponse Status="ERROR"><Errors><Error Number="5019169">Unknown exceptions</Error></Errors></ApiResponse>`))
+				return
+			}
+			response = `
+				<CommandResponse Type="namecheap.domains.getInfo">
+					<DomainGetInfoResult DomainName="alpha.com" IsPremium="false">
+						<PremiumDnsSubscription><IsActive>true</IsActive></PremiumDnsSubscription>
+						<DnsDetails ProviderType="FREE" IsUsingOurDNS="true" />
+					</DomainGetInfoResult>
+				</CommandResponse>`
+		case "namecheap.domains.dns.getList":
+			ourDNS, nameservers := "true", "<Nameserver>dns1.registrar-servers.com</Nameserver><Nameserver>dns2.registrar-servers.com</Nameserver>"
+			if domain == "beta.net" {
+				ourDNS, nameservers = "false", "<Nameserver>ns1.example.net</Nameserver><Nameserver>ns2.example.net</Nameserver>"
+			}
+			response = fmt.Sprintf(`
+				<CommandResponse Type="namecheap.domains.dns.getList">
+					<DomainDNSGetListResult Domain="%s" IsUsingOurDNS="%s" IsPremiumDNS="false" IsUsingFreeDNS="false">%s</DomainDNSGetListResult>
+				</CommandResponse>`, domain, ourDNS, nameservers)
+		case "namecheap.domains.dns.getHosts":
+			response = `
+				<CommandResponse Type="namecheap.domains.dns.getHosts">
+					<DomainDNSGetHostsResult Domain="alpha.com" EmailType="MX" IsUsingOurDNS="true">
+						<host HostId="1" Name="@" Type="A" Address="192.0.2.1" MXPref="10" TTL="1800" />
+						<host HostId="2" Name="www" Type="A" Address="192.0.2.1" MXPref="10" TTL="1800" />
+						<host HostId="3" Name="@" Type="MX" Address="mail.alpha.com." MXPref="10" TTL="1800" />
+					</DomainDNSGetHostsResult>
+				</CommandResponse>`
+		}
+
+		_, _ = writer.Write([]byte(`<?xml version="1.0" encoding="utf-8"?><ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response"><Errors />` + response + `</ApiResponse>`))
+	}))
+}
+
+func TestInventoryExporter(t *testing.T) {
+	mockServer := newInventoryMockServer()
+	defer mockServer.Close()
+
+	client := setupClient(nil)
+	client.BaseURL = mockServer.URL
+
+	inventory, err := NewInventoryExporter(client, nil).Build(context.TODO())
+
+	t.Run("build", func(t *testing.T) {
+		assert.EqualError(t, err, "beta.net: info: Unknown exceptions (5019169)")
+
+		if assert.Len(t, inventory.Records, 2) {
+			alpha := inventory.Records[0]
+			assert.Equal(t, "alpha.com", alpha.Domain)
+			assert.Equal(t, "2022-06-02", alpha.Expires)
+			assert.True(t, alpha.IsLocked)
+			assert.Equal(t, "ENABLED", alpha.Privacy)
+			assert.Equal(t, "FREE", alpha.DNSProvider)
+			assert.True(t, alpha.IsPremiumDNS)
+			assert.Equal(t, []string{"dns1.registrar-servers.com", "dns2.registrar-servers.com"}, alpha.Nameservers)
+			assert.Equal(t, 3, alpha.RecordCount)
+			assert.Equal(t, map[string]int{"A": 2, "MX": 1}, alpha.RecordCounts)
+			assert.Empty(t, alpha.Error)
+
+			beta := inventory.Records[1]
+			assert.Equal(t, "beta.net", beta.Domain)
+			assert.False(t, beta.IsUsingOurDNS)
+			assert.Equal(t, []string{"ns1.example.net", "ns2.example.net"}, beta.Nameservers)
+			assert.Equal(t, 0, beta.RecordCount)
+			assert.Equal(t, "info: Unknown exceptions (5019169)", beta.Error)
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buffer bytes.Buffer
+		if err := inventory.Write(&buffer, InventoryFormatCSV); err != nil {
+			t.Fatal("Unable to write CSV", err)
+		}
+
+		assert.Equal(t, "domain,created,expires,is_expired,auto_renew,is_locked,privacy,is_premium,dns_provider,is_using_our_dns,is_premium_dns,nameservers,record_count,record_counts,error\n"+
+			"alpha.com,2021-06-02,2022-06-02,false,true,true,ENABLED,false,FREE,true,true,dns1.registrar-servers.com dns2.registrar-servers.com,3,A=2;MX=1,\n"+
+			"beta.net,2020-01-15,2022-01-15,false,false,false,NOTPRESENT,false,,false,false,ns1.example.net ns2.example.net,0,,info: Unknown exceptions (5019169)\n",
+			buffer.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buffer bytes.Buffer
+		if err := inventory.Write(&buffer, InventoryFormatJSON); err != nil {
+			t.Fatal("Unable to write JSON", err)
+		}
+
+		assert.Contains(t, buffer.String(), `"recordCounts": {`)
+		assert.Contains(t, buffer.String(), `"isUsingOurDns": true`)
+		assert.Contains(t, buffer.String(), `"domain": "beta.net"`)
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		var buffer bytes.Buffer
+		if err := inventory.Write(&buffer, "YAML"); err != nil {
+			t.Fatal("Unable to write YAML", err)
+		}
+
+		assert.Contains(t, buffer.String(), "dnsProvider: FREE")
+
+		var decoded Inventory
+		if err := yaml.Unmarshal(buffer.Bytes(), &decoded); err != nil {
+			t.Fatal("Unable to decode YAML", err)
+		}
+		assert.Equal(t, *inventory, decoded)
+	})
+
+	t.Run("invalid_format", func(t *testing.T) {
+		assert.EqualError(t, inventory.Write(ioutil.Discard, "xlsx"), "invalid format value: xlsx, allowed values are csv, json, yaml")
+	})
+}
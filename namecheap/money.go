@@ -0,0 +1,153 @@
+package namecheap
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// DefaultCurrency is the currency assumed for the amounts the API returns without an explicit currency
+const DefaultCurrency = "USD"
+
+// Money is an exact amount of money in a currency.
+// It's parsed from the XML attributes and elements holding prices and charges.
+//
+// An empty Currency means the currency isn't known yet, such a value adopts the currency of the other operand
+// in arithmetic and comparison. The zero value is 0 of the unknown currency, so it can be used to start a sum.
+type Money struct {
+	Amount   Decimal
+	Currency string
+}
+
+// NewMoney returns Money of the amount in the currency
+func NewMoney(amount Decimal, currency string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(currency)}
+}
+
+// ParseMoney parses the amount string in the currency
+func ParseMoney(amount string, currency string) (Money, error) {
+	d, err := ParseDecimal(amount)
+	if err != nil {
+		return Money{}, err
+	}
+	return NewMoney(d, currency), nil
+}
+
+// MustParseMoney is like ParseMoney but panics if the amount can't be parsed
+func MustParseMoney(amount string, currency string) Money {
+	m, err := ParseMoney(amount, currency)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Add returns m + other. It fails if the currencies differ.
+func (m Money) Add(other Money) (Money, error) {
+	currency, err := m.commonCurrency(other)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount.Add(other.Amount), Currency: currency}, nil
+}
+
+// Sub returns m - other. It fails if the currencies differ.
+func (m Money) Sub(other Money) (Money, error) {
+	currency, err := m.commonCurrency(other)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount.Sub(other.Amount), Currency: currency}, nil
+}
+
+// MulInt returns m * n
+func (m Money) MulInt(n int64) Money {
+	return Money{Amount: m.Amount.MulInt(n), Currency: m.Currency}
+}
+
+// Cmp compares m and other and returns -1 if m < other, 0 if m == other and +1 if m > other.
+// It fails if the currencies differ.
+func (m Money) Cmp(other Money) (int, error) {
+	_, err := m.commonCurrency(other)
+	if err != nil {
+		return 0, err
+	}
+	return m.Amount.Cmp(other.Amount), nil
+}
+
+// IsZero reports whether the amount is 0
+func (m Money) IsZero() bool {
+	return m.Amount.IsZero()
+}
+
+// SumMoney returns the sum of the values. It fails if the currencies differ.
+func SumMoney(values ...Money) (Money, error) {
+	var sum Money
+	var err error
+	for _, value := range values {
+		sum, err = sum.Add(value)
+		if err != nil {
+			return Money{}, err
+		}
+	}
+	return sum, nil
+}
+
+func (m Money) commonCurrency(other Money) (string, error) {
+	switch {
+	case m.Currency == "":
+		return other.Currency, nil
+	case other.Currency == "" || strings.EqualFold(m.Currency, other.Currency):
+		return m.Currency, nil
+	default:
+		return "", fmt.Errorf("currency mismatch: %s and %s", m.Currency, other.Currency)
+	}
+}
+
+// String returns the amount followed by the currency, e.g. "12.88 USD"
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Amount.String()
+	}
+	return m.Amount.String() + " " + m.Currency
+}
+
+// UnmarshalText parses the amount of an XML attribute or element.
+// The currency is set to DefaultCurrency, the structs with an explicit currency attribute override it.
+func (m *Money) UnmarshalText(text []byte) error {
+	amount, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+
+	m.Amount = amount
+	m.Currency = DefaultCurrency
+	return nil
+}
+
+type moneyJSON struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency,omitempty"`
+}
+
+// MarshalJSON encodes the value as {"amount":"12.88","currency":"USD"}.
+// The amount is a string to keep it exact.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.Amount.String(), Currency: m.Currency})
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var value moneyJSON
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := ParseMoney(value.Amount, value.Currency)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
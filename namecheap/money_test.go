@@ -0,0 +1,101 @@
+package namecheap
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoneyArithmetic(t *testing.T) {
+	t.Run("sum", func(t *testing.T) {
+		sum, err := SumMoney(
+			MustParseMoney("8.88", "USD"),
+			MustParseMoney("0.18", "USD"),
+			MustParseMoney("10.10", "usd"),
+		)
+		if err != nil {
+			t.Fatal("Unable to sum", err)
+		}
+
+		assert.Equal(t, "19.16 USD", sum.String())
+	})
+
+	t.Run("sum_empty", func(t *testing.T) {
+		sum, err := SumMoney()
+		if err != nil {
+			t.Fatal("Unable to sum", err)
+		}
+
+		assert.True(t, sum.IsZero())
+	})
+
+	t.Run("currency_mismatch", func(t *testing.T) {
+		_, err := MustParseMoney("1", "USD").Add(MustParseMoney("1", "EUR"))
+		assert.EqualError(t, err, "currency mismatch: USD and EUR")
+
+		_, err = MustParseMoney("1", "USD").Cmp(MustParseMoney("1", "EUR"))
+		assert.EqualError(t, err, "currency mismatch: USD and EUR")
+	})
+
+	t.Run("compare", func(t *testing.T) {
+		cmp, err := MustParseMoney("10.99", "USD").Cmp(MustParseMoney("11", "USD"))
+		assert.Nil(t, err)
+		assert.Equal(t, -1, cmp)
+
+		diff, err := MustParseMoney("10.99", "USD").Sub(MustParseMoney("0.99", "USD"))
+		assert.Nil(t, err)
+		assert.Equal(t, MustParseMoney("10", "USD"), diff)
+	})
+
+	t.Run("mul", func(t *testing.T) {
+		assert.Equal(t, "26.96 USD", MustParseMoney("13.48", "USD").MulInt(2).String())
+	})
+}
+
+func TestMoneyJSON(t *testing.T) {
+	t.Run("marshal", func(t *testing.T) {
+		data, err := json.Marshal(MustParseMoney("13.48", "USD"))
+		if err != nil {
+			t.Fatal("Unable to marshal", err)
+		}
+
+		assert.Equal(t, `{"amount":"13.48","currency":"USD"}`, string(data))
+	})
+
+	t.Run("unmarshal", func(t *testing.T) {
+		var m Money
+		err := json.Unmarshal([]byte(`{"amount":"0.18","currency":"usd"}`), &m)
+		if err != nil {
+			t.Fatal("Unable to unmarshal", err)
+		}
+
+		assert.Equal(t, MustParseMoney("0.18", "USD"), m)
+	})
+}
+
+func TestMoneyXML(t *testing.T) {
+	t.Run("price_currency_applied", func(t *testing.T) {
+		var price Price
+		err := xml.Unmarshal([]byte(`<Price Duration="1" DurationType="YEAR" Price="8.88" RegularPrice="10.98" YourPrice="8.88" CouponPrice="" Currency="EUR" />`), &price)
+		if err != nil {
+			t.Fatal("Unable to decode", err)
+		}
+
+		assert.Equal(t, MustParseMoney("8.88", "EUR"), price.Price)
+		assert.Equal(t, MustParseMoney("10.98", "EUR"), price.RegularPrice)
+		assert.True(t, price.CouponPrice.IsZero())
+		assert.Equal(t, "1", price.Duration)
+	})
+
+	t.Run("charged_amount_default_currency", func(t *testing.T) {
+		var result DomainCreateResult
+		err := xml.Unmarshal([]byte(`<DomainCreateResult Domain="domain.com" Registered="true" ChargedAmount="20.6100" />`), &result)
+		if err != nil {
+			t.Fatal("Unable to decode", err)
+		}
+
+		assert.Equal(t, MustParseMoney("20.61", DefaultCurrency), result.ChargedAmount)
+	})
+}
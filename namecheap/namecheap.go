@@ -8,12 +8,14 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/go-cleanhttp"
 	"github.com/namecheap/go-namecheap-sdk/v2/namecheap/internal/syncretry"
@@ -34,9 +36,11 @@ type ClientOptions struct {
 }
 
 type Client struct {
-	http   *http.Client
-	common service
-	sr     *syncretry.SyncRetry
+	http       *http.Client
+	common     service
+	sr         *syncretry.SyncRetry
+	spendGuard *spendGuard
+	hostsGuard *hostsGuard
 
 	ClientOptions *ClientOptions
 	BaseURL       string
@@ -50,8 +54,18 @@ type service struct {
 	client *Client
 }
 
+// defaultClientIPLookupURL answers with the public IPv4 address of the caller in plain text
+const defaultClientIPLookupURL = "https://ipv4.icanhazip.com"
+
+// LookupClientIP returns the public IPv4 address of the machine
 func LookupClientIP(ctx context.Context) (string, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://ipv4.icanhazip.com", nil)
+	return LookupClientIPFrom(ctx, defaultClientIPLookupURL)
+}
+
+// LookupClientIPFrom returns the IP address the service at lookupURL answers with in plain text,
+// e.g. https://ipv4.icanhazip.com or https://api.ipify.org
+func LookupClientIPFrom(ctx context.Context, lookupURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, lookupURL, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to build HTTP request %v", err)
 	}
@@ -62,14 +76,21 @@ func LookupClientIP(ctx context.Context) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("failed to fetch external IP %v", err)
 	}
+	defer resp.Body.Close()
+
 	if resp.StatusCode != http.StatusOK {
 		return "", fmt.Errorf("did not get OK status when looking up external IP: %d/%s", resp.StatusCode, resp.Status)
 	}
-	respBody, err := ioutil.ReadAll(resp.Body)
+	respBody, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
 	if err != nil {
 		return "", fmt.Errorf("failed to read response body when looking up external IP: %v", err)
 	}
-	return strings.TrimSpace(string(respBody)), nil
+
+	ip := strings.TrimSpace(string(respBody))
+	if net.ParseIP(ip) == nil {
+		return "", fmt.Errorf("did not get an IP address when looking up external IP: %q", ip)
+	}
+	return ip, nil
 }
 
 func NewClientOptionsFromEnv(clientIP string) *ClientOptions {
@@ -88,6 +109,8 @@ func NewClient(options *ClientOptions) *Client {
 		ClientOptions: options,
 		http:          cleanhttp.DefaultClient(),
 		sr:            syncretry.NewSyncRetry(&syncretry.Options{Delays: []int{1, 5, 15, 30, 50}}),
+		spendGuard:    &spendGuard{now: time.Now},
+		hostsGuard:    &hostsGuard{},
 	}
 
 	client.BaseURL = namecheapProductionApiUrl
@@ -104,6 +127,7 @@ func NewClient(options *ClientOptions) *Client {
 }
 
 // NewRequest creates a new request with the params
+// The body map is copied before the authentication params are added, so it's safe to reuse it
 func (c *Client) NewRequest(ctx context.Context, body map[string]string) (*http.Request, error) {
 	u, err := url.Parse(c.BaseURL)
 
@@ -111,12 +135,17 @@ func (c *Client) NewRequest(ctx context.Context, body map[string]string) (*http.
 		return nil, fmt.Errorf("Error parsing base URL: %s", err)
 	}
 
-	body["Username"] = c.ClientOptions.UserName
-	body["ApiKey"] = c.ClientOptions.ApiKey
-	body["ApiUser"] = c.ClientOptions.ApiUser
-	body["ClientIp"] = c.ClientOptions.ClientIp
+	params := make(map[string]string, len(body)+4)
+	for k, v := range body {
+		params[k] = v
+	}
+
+	params["Username"] = c.ClientOptions.UserName
+	params["ApiKey"] = c.ClientOptions.ApiKey
+	params["ApiUser"] = c.ClientOptions.ApiUser
+	params["ClientIp"] = c.ClientOptions.ClientIp
 
-	rBody := encodeBody(body)
+	rBody := encodeBody(params)
 
 	// Build the request
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewBufferString(rBody))
@@ -132,7 +161,18 @@ func (c *Client) NewRequest(ctx context.Context, body map[string]string) (*http.
 }
 
 func (c *Client) DoXML(ctx context.Context, body map[string]string, obj interface{}) (*http.Response, error) {
+	response, rawBody, err := c.do(ctx, body)
+	if err != nil {
+		return response, err
+	}
+
+	return response, decodeBody(bytes.NewReader(rawBody), obj)
+}
+
+// do sends the request with the retry policy applied and returns the raw response body
+func (c *Client) do(ctx context.Context, body map[string]string) (*http.Response, []byte, error) {
 	var requestResponse *http.Response
+	var rawBody []byte
 	err := c.sr.Do(ctx, func() error {
 		request, err := c.NewRequest(ctx, body)
 		if err != nil {
@@ -143,23 +183,26 @@ func (c *Client) DoXML(ctx context.Context, body map[string]string, obj interfac
 		if err != nil {
 			return err
 		}
+		defer response.Body.Close()
 
 		if response.StatusCode == 405 {
 			return syncretry.RetryError
 		}
 
 		requestResponse = response
-		defer response.Body.Close()
 
-		err = decodeBody(response.Body, obj)
-		return err
+		rawBody, err = ioutil.ReadAll(response.Body)
+		if err != nil {
+			return fmt.Errorf("unable to read server response: %s", err)
+		}
+		return nil
 	})
 
 	if err != nil && errors.Is(err, syncretry.RetryAttemptsError) {
-		return nil, fmt.Errorf("API retry limit exceeded")
+		return nil, nil, fmt.Errorf("API retry limit exceeded")
 	}
 
-	return requestResponse, err
+	return requestResponse, rawBody, err
 }
 
 // decodeBody decodes the interface from received XML
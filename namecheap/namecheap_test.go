@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"log"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
@@ -157,6 +158,54 @@ func TestDecodeBody(t *testing.T) {
 	assert.Equal(t, obj.Boolean, true)
 }
 
+func TestLookupClientIPFrom(t *testing.T) {
+	var cases = map[string]struct {
+		Status        int
+		Body          string
+		ExpectedIP    string
+		ExpectedError string
+	}{
+		"ipv4": {
+			Status:     http.StatusOK,
+			Body:       "192.0.2.10\n",
+			ExpectedIP: "192.0.2.10",
+		},
+		"ipv6": {
+			Status:     http.StatusOK,
+			Body:       "2001:db8::1",
+			ExpectedIP: "2001:db8::1",
+		},
+		"not_an_ip": {
+			Status:        http.StatusOK,
+			Body:          "<html>blocked</html>",
+			ExpectedError: `did not get an IP address when looking up external IP: "<html>blocked</html>"`,
+		},
+		"error_status": {
+			Status:        http.StatusTooManyRequests,
+			ExpectedError: "did not get OK status when looking up external IP: 429/429 Too Many Requests",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				writer.WriteHeader(c.Status)
+				_, _ = writer.Write([]byte(c.Body))
+			}))
+			defer mockServer.Close()
+
+			ip, err := LookupClientIPFrom(context.TODO(), mockServer.URL)
+
+			if c.ExpectedError != "" {
+				assert.EqualError(t, err, c.ExpectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.ExpectedIP, ip)
+		})
+	}
+}
+
 func TestParseDomain(t *testing.T) {
 	successCases := []struct {
 		Domain string
@@ -0,0 +1,339 @@
+package namecheap
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DurationTypeYear = "YEAR"
+
+	defaultPricingCatalogTTL = 24 * time.Hour
+)
+
+// PricingEntry is a single price of the flattened UsersService.GetPricing result.
+// The prices are per year for every Duration, as returned by namecheap.users.getPricing, e.g. the total of a 2 year
+// registration is twice the Price of the Duration 2 entry. The Duration entries differ when multi-year discounts apply.
+type PricingEntry struct {
+	ProductType string `json:"productType"`
+	// For the DOMAIN product type the API reports the action (register, renew, transfer, reactivate) as the category
+	ProductCategory string `json:"productCategory"`
+	// For the DOMAIN product type it's the TLD, e.g. com
+	ProductName  string `json:"productName"`
+	Duration     int    `json:"duration"`
+	DurationType string `json:"durationType"`
+	Price        Money  `json:"price"`
+	RegularPrice Money  `json:"regularPrice"`
+	YourPrice    Money  `json:"yourPrice"`
+	CouponPrice  Money  `json:"couponPrice"`
+	Currency     string `json:"currency"`
+}
+
+func (p PricingEntry) String() string {
+	return fmt.Sprintf("{ProductType: %s, ProductCategory: %s, ProductName: %s, Duration: %d, DurationType: %s, Price: %s, RegularPrice: %s, YourPrice: %s, CouponPrice: %s}",
+		p.ProductType, p.ProductCategory, p.ProductName, p.Duration, p.DurationType, p.Price, p.RegularPrice, p.YourPrice, p.CouponPrice)
+}
+
+// PricingCatalogOptions struct is an input arguments for NewPricingCatalog function
+type PricingCatalogOptions struct {
+	// Product type to load the pricing for
+	// Default value: ProductTypeDomain
+	ProductType string
+	// Promotional (coupon) code to be applied to the prices
+	PromotionCode string
+	// How long the loaded pricing is considered fresh
+	// Default value: 24 hours
+	TTL time.Duration
+	// Optional path of a JSON file the pricing is cached in between the processes.
+	// Writing the file is best-effort, a failed write doesn't fail the lookups, see PricingCatalog.CacheError
+	CacheFile string
+}
+
+// PricingCatalog loads the pricing of a product type once and keeps it cached in memory
+// and, optionally, on disk until the TTL expires. The pricing call is slow and heavily quota-limited,
+// so the catalog should be shared rather than created per lookup. It's safe for concurrent use.
+type PricingCatalog struct {
+	client  *Client
+	options PricingCatalogOptions
+	now     func() time.Time
+
+	m        sync.Mutex
+	entries  []PricingEntry
+	loadedAt time.Time
+	cacheErr error
+}
+
+type pricingCatalogCache struct {
+	ProductType   string         `json:"productType"`
+	PromotionCode string         `json:"promotionCode,omitempty"`
+	LoadedAt      time.Time      `json:"loadedAt"`
+	Entries       []PricingEntry `json:"entries"`
+}
+
+// NewPricingCatalog returns a new PricingCatalog. Nothing is loaded until the first lookup.
+func NewPricingCatalog(client *Client, options *PricingCatalogOptions) *PricingCatalog {
+	catalog := &PricingCatalog{
+		client: client,
+		now:    time.Now,
+	}
+
+	if options != nil {
+		catalog.options = *options
+	}
+	if catalog.options.ProductType == "" {
+		catalog.options.ProductType = ProductTypeDomain
+	}
+	if catalog.options.TTL == 0 {
+		catalog.options.TTL = defaultPricingCatalogTTL
+	}
+
+	return catalog
+}
+
+// Entries returns all the prices of the catalog, loading them if needed
+func (pc *PricingCatalog) Entries(ctx context.Context) ([]PricingEntry, error) {
+	pc.m.Lock()
+	defer pc.m.Unlock()
+
+	err := pc.load(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]PricingEntry, len(pc.entries))
+	copy(entries, pc.entries)
+	return entries, nil
+}
+
+// Refresh reloads the pricing from the API regardless of the TTL
+func (pc *PricingCatalog) Refresh(ctx context.Context) error {
+	pc.m.Lock()
+	defer pc.m.Unlock()
+
+	return pc.load(ctx, true)
+}
+
+// Price returns the price of the product for the action and the number of years, e.g. Price(ctx, "com", ActionNameRegister, 1).
+// The prices of the entry are per year, see PricingEntry.
+// The TLD can be passed with or without the leading dot, the action is matched case-insensitively.
+func (pc *PricingCatalog) Price(ctx context.Context, tld string, action string, years int) (*PricingEntry, error) {
+	entries, err := pc.Entries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tld = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(tld)), ".")
+
+	for _, entry := range entries {
+		if strings.EqualFold(entry.ProductName, tld) &&
+			strings.EqualFold(entry.ProductCategory, action) &&
+			strings.EqualFold(entry.DurationType, DurationTypeYear) &&
+			entry.Duration == years {
+			return &entry, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no %s price found for %s for %d year(s)", strings.ToLower(action), tld, years)
+}
+
+// WriteJSON writes all the prices as a JSON array
+func (pc *PricingCatalog) WriteJSON(ctx context.Context, w io.Writer) error {
+	entries, err := pc.Entries(ctx)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(entries)
+}
+
+// WriteCSV writes all the prices as CSV with a header row
+func (pc *PricingCatalog) WriteCSV(ctx context.Context, w io.Writer) error {
+	entries, err := pc.Entries(ctx)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	err = writer.Write([]string{"product_type", "product_category", "product_name", "duration", "duration_type", "price", "regular_price", "your_price", "coupon_price", "currency"})
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		err = writer.Write([]string{
+			entry.ProductType,
+			entry.ProductCategory,
+			entry.ProductName,
+			strconv.Itoa(entry.Duration),
+			entry.DurationType,
+			entry.Price.Amount.String(),
+			entry.RegularPrice.Amount.String(),
+			entry.YourPrice.Amount.String(),
+			entry.CouponPrice.Amount.String(),
+			entry.Currency,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// load must be called with the lock held
+func (pc *PricingCatalog) load(ctx context.Context, force bool) error {
+	if !force && pc.entries != nil && pc.isFresh(pc.loadedAt) {
+		return nil
+	}
+
+	if !force && pc.options.CacheFile != "" {
+		cache, err := pc.readCacheFile()
+		if err == nil && cache.ProductType == pc.options.ProductType && cache.PromotionCode == pc.options.PromotionCode && pc.isFresh(cache.LoadedAt) {
+			pc.entries = cache.Entries
+			pc.loadedAt = cache.LoadedAt
+			return nil
+		}
+	}
+
+	result, err := pc.client.UsersService.GetPricing(ctx, UserGetPricingArgs{
+		ProductType:   pc.options.ProductType,
+		PromotionCode: pc.options.PromotionCode,
+	})
+	if err != nil {
+		return err
+	}
+
+	entries, err := flattenPricing(result)
+	if err != nil {
+		return err
+	}
+
+	pc.entries = entries
+	pc.loadedAt = pc.now()
+
+	// the pricing is loaded already, so a cache problem only costs a pricing call in the next process
+	if pc.options.CacheFile != "" {
+		pc.cacheErr = pc.writeCacheFile()
+	}
+
+	return nil
+}
+
+// CacheError returns the error of the last write of CacheFile, nil if it succeeded or no write was needed
+func (pc *PricingCatalog) CacheError() error {
+	pc.m.Lock()
+	defer pc.m.Unlock()
+
+	return pc.cacheErr
+}
+
+func (pc *PricingCatalog) isFresh(loadedAt time.Time) bool {
+	return pc.now().Sub(loadedAt) < pc.options.TTL
+}
+
+func (pc *PricingCatalog) readCacheFile() (*pricingCatalogCache, error) {
+	data, err := ioutil.ReadFile(pc.options.CacheFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var cache pricingCatalogCache
+	err = json.Unmarshal(data, &cache)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cache, nil
+}
+
+func (pc *PricingCatalog) writeCacheFile() error {
+	data, err := json.Marshal(pricingCatalogCache{
+		ProductType:   pc.options.ProductType,
+		PromotionCode: pc.options.PromotionCode,
+		LoadedAt:      pc.loadedAt,
+		Entries:       pc.entries,
+	})
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first, so concurrent readers never see a partial cache
+	tmpFile, err := ioutil.TempFile(filepath.Dir(pc.options.CacheFile), filepath.Base(pc.options.CacheFile)+".*")
+	if err != nil {
+		return fmt.Errorf("unable to write pricing cache: %v", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	_, err = tmpFile.Write(data)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("unable to write pricing cache: %v", err)
+	}
+
+	err = os.Rename(tmpFile.Name(), pc.options.CacheFile)
+	if err != nil {
+		return fmt.Errorf("unable to write pricing cache: %v", err)
+	}
+
+	return nil
+}
+
+func flattenPricing(result *UserGetPricingResult) ([]PricingEntry, error) {
+	entries := []PricingEntry{}
+	if result == nil {
+		return entries, nil
+	}
+
+	for _, category := range result.ProductType.ProductCategory {
+		for _, product := range category.Product {
+			for _, price := range product.Price {
+				duration, err := strconv.Atoi(price.Duration)
+				if err != nil {
+					return nil, fmt.Errorf("invalid Duration value of %s %s: %s", category.Name, product.Name, price.Duration)
+				}
+
+				entries = append(entries, PricingEntry{
+					ProductType:     result.ProductType.Name,
+					ProductCategory: category.Name,
+					ProductName:     product.Name,
+					Duration:        duration,
+					DurationType:    price.DurationType,
+					Price:           price.Price,
+					RegularPrice:    price.RegularPrice,
+					YourPrice:       price.YourPrice,
+					CouponPrice:     price.CouponPrice,
+					Currency:        price.Currency,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.ProductName != b.ProductName {
+			return a.ProductName < b.ProductName
+		}
+		if a.ProductCategory != b.ProductCategory {
+			return a.ProductCategory < b.ProductCategory
+		}
+		return a.Duration < b.Duration
+	})
+
+	return entries, nil
+}
@@ -0,0 +1,226 @@
+package namecheap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const fakePricingResponse = `
+	<?xml version="1.0" encoding="utf-8"?>
+	<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+		<Errors />
+		<Warnings />
+		<RequestedCommand>namecheap.users.getPricing</RequestedCommand>
+		<CommandResponse Type="namecheap.users.getPricing">
+			<UserGetPricingResult>
+				<ProductType Name="domains">
+					<ProductCategory Name="register">
+						<Product Name="com">
+							<Price Duration="1" DurationType="YEAR" Price="8.88" RegularPrice="10.98" YourPrice="8.88" CouponPrice="" Currency="USD" />
+							<Price Duration="2" DurationType="YEAR" Price="11.43" RegularPrice="11.43" YourPrice="11.43" CouponPrice="" Currency="USD" />
+						</Product>
+						<Product Name="net">
+							<Price Duration="1" DurationType="YEAR" Price="10.98" RegularPrice="12.98" YourPrice="10.98" CouponPrice="" Currency="USD" />
+						</Product>
+					</ProductCategory>
+					<ProductCategory Name="renew">
+						<Product Name="com">
+							<Price Duration="1" DurationType="YEAR" Price="13.98" RegularPrice="13.98" YourPrice="13.98" CouponPrice="" Currency="USD" />
+						</Product>
+					</ProductCategory>
+				</ProductType>
+			</UserGetPricingResult>
+		</CommandResponse>
+		<Server>PHX01SBAPIEXT05</Server>
+		<GMTTimeDifference>--4:00</GMTTimeDifference>
+		<ExecutionTime>0.011</ExecutionTime>
+	</ApiResponse>
+`
+
+func newPricingMockServer(calls *int32, sentBody *url.Values) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		atomic.AddInt32(calls, 1)
+		if sentBody != nil {
+			body, _ := ioutil.ReadAll(request.Body)
+			*sentBody, _ = url.ParseQuery(string(body))
+		}
+		_, _ = writer.Write([]byte(fakePricingResponse))
+	}))
+}
+
+func TestPricingCatalog(t *testing.T) {
+	t.Run("request_data_passing", func(t *testing.T) {
+		var calls int32
+		var sentBody url.Values
+		mockServer := newPricingMockServer(&calls, &sentBody)
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		catalog := NewPricingCatalog(client, &PricingCatalogOptions{PromotionCode: "PROMO"})
+		_, err := catalog.Entries(context.TODO())
+		if err != nil {
+			t.Fatal("Unable to load pricing", err)
+		}
+
+		assert.Equal(t, "namecheap.users.getPricing", sentBody.Get("Command"))
+		assert.Equal(t, ProductTypeDomain, sentBody.Get("ProductType"))
+		assert.Equal(t, "PROMO", sentBody.Get("PromotionCode"))
+	})
+
+	t.Run("price_lookup", func(t *testing.T) {
+		var calls int32
+		mockServer := newPricingMockServer(&calls, nil)
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		catalog := NewPricingCatalog(client, nil)
+
+		price, err := catalog.Price(context.TODO(), ".COM", ActionNameRegister, 2)
+		if err != nil {
+			t.Fatal("Unable to get price", err)
+		}
+		assert.Equal(t, MustParseMoney("11.43", "USD"), price.YourPrice)
+
+		price, err = catalog.Price(context.TODO(), "com", ActionNameRenew, 1)
+		if err != nil {
+			t.Fatal("Unable to get price", err)
+		}
+		assert.Equal(t, MustParseMoney("13.98", "USD"), price.Price)
+
+		_, err = catalog.Price(context.TODO(), "net", ActionNameTransfer, 1)
+		assert.EqualError(t, err, "no transfer price found for net for 1 year(s)")
+
+		assert.Equal(t, int32(1), calls)
+	})
+
+	t.Run("memory_cache_ttl", func(t *testing.T) {
+		var calls int32
+		mockServer := newPricingMockServer(&calls, nil)
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		now := time.Date(2021, 6, 2, 0, 0, 0, 0, time.UTC)
+		catalog := NewPricingCatalog(client, &PricingCatalogOptions{TTL: time.Hour})
+		catalog.now = func() time.Time { return now }
+
+		_, _ = catalog.Entries(context.TODO())
+		_, _ = catalog.Entries(context.TODO())
+		assert.Equal(t, int32(1), calls)
+
+		now = now.Add(2 * time.Hour)
+		_, _ = catalog.Entries(context.TODO())
+		assert.Equal(t, int32(2), calls)
+
+		_ = catalog.Refresh(context.TODO())
+		assert.Equal(t, int32(3), calls)
+	})
+
+	t.Run("disk_cache", func(t *testing.T) {
+		var calls int32
+		mockServer := newPricingMockServer(&calls, nil)
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		cacheFile := filepath.Join(t.TempDir(), "pricing.json")
+
+		first := NewPricingCatalog(client, &PricingCatalogOptions{CacheFile: cacheFile})
+		_, err := first.Entries(context.TODO())
+		if err != nil {
+			t.Fatal("Unable to load pricing", err)
+		}
+
+		second := NewPricingCatalog(client, &PricingCatalogOptions{CacheFile: cacheFile})
+		price, err := second.Price(context.TODO(), "net", ActionNameRegister, 1)
+		if err != nil {
+			t.Fatal("Unable to get price", err)
+		}
+
+		assert.Equal(t, int32(1), calls)
+		assert.Equal(t, MustParseMoney("10.98", "USD"), price.YourPrice)
+
+		expired := NewPricingCatalog(client, &PricingCatalogOptions{CacheFile: cacheFile})
+		expired.now = func() time.Time { return time.Now().Add(48 * time.Hour) }
+		_, _ = expired.Entries(context.TODO())
+
+		assert.Equal(t, int32(2), calls)
+	})
+
+	t.Run("disk_cache_write_failure", func(t *testing.T) {
+		var calls int32
+		mockServer := newPricingMockServer(&calls, nil)
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		cacheFile := filepath.Join(t.TempDir(), "missing", "pricing.json")
+
+		catalog := NewPricingCatalog(client, &PricingCatalogOptions{CacheFile: cacheFile})
+		price, err := catalog.Price(context.TODO(), "net", ActionNameRegister, 1)
+		if err != nil {
+			t.Fatal("Unable to get price", err)
+		}
+
+		assert.Equal(t, MustParseMoney("10.98", "USD"), price.YourPrice)
+		if assert.Error(t, catalog.CacheError()) {
+			assert.Contains(t, catalog.CacheError().Error(), "unable to write pricing cache")
+		}
+	})
+
+	t.Run("exports", func(t *testing.T) {
+		var calls int32
+		mockServer := newPricingMockServer(&calls, nil)
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		catalog := NewPricingCatalog(client, nil)
+
+		var csvOut bytes.Buffer
+		err := catalog.WriteCSV(context.TODO(), &csvOut)
+		if err != nil {
+			t.Fatal("Unable to write CSV", err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(csvOut.String()), "\n")
+		if assert.Len(t, lines, 5) {
+			assert.Equal(t, "product_type,product_category,product_name,duration,duration_type,price,regular_price,your_price,coupon_price,currency", lines[0])
+			assert.Equal(t, "domains,register,com,1,YEAR,8.88,10.98,8.88,0.00,USD", lines[1])
+		}
+
+		var jsonOut bytes.Buffer
+		err = catalog.WriteJSON(context.TODO(), &jsonOut)
+		if err != nil {
+			t.Fatal("Unable to write JSON", err)
+		}
+
+		var entries []PricingEntry
+		err = json.Unmarshal(jsonOut.Bytes(), &entries)
+		if err != nil {
+			t.Fatal("Unable to parse JSON", err)
+		}
+		assert.Len(t, entries, 4)
+		assert.Equal(t, MustParseMoney("13.98", "USD"), entries[2].Price)
+	})
+}
@@ -0,0 +1,257 @@
+package namecheap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SpendingPolicy limits the money the purchasing operations of a Client can spend.
+// Every purchase is estimated before it's sent, and it's rejected with SpendingPolicyError
+// if the estimate breaks any of the limits. Estimation failures reject the purchase as well.
+//
+// NOTE: commands sent through Client.Call aren't guarded
+type SpendingPolicy struct {
+	// Maximum estimated charge of a single operation. Zero means no limit.
+	MaxChargePerOperation Money
+	// Maximum cumulative charge of all the operations within the Window. Zero means no limit.
+	MaxTotalCharge Money
+	// Time window the MaxTotalCharge applies to. Zero means the lifetime of the Client.
+	Window time.Duration
+	// Whether premium domains are allowed to be purchased
+	AllowPremium bool
+	// Optional callback invoked with the estimated price right before the operation is sent.
+	// Return false to reject the operation.
+	Confirm func(ctx context.Context, request SpendRequest) (bool, error)
+	// Optional estimator used to price the operations
+	// Default value: NewCostEstimator(client, nil)
+	Estimator *CostEstimator
+}
+
+// SpendRequest describes a purchase about to be sent
+type SpendRequest struct {
+	Operation PlannedOperation
+	// Estimated charge including fees
+	Estimate  Money
+	IsPremium bool
+	// Charges already made within the policy window, including the purchases in flight
+	SpentInWindow Money
+}
+
+// SpendingPolicyError is returned when a purchase is rejected by the SpendingPolicy
+type SpendingPolicyError struct {
+	Request SpendRequest
+	Reason  string
+}
+
+func (e *SpendingPolicyError) Error() string {
+	return fmt.Sprintf("spending policy rejected %s estimated at %s: %s", e.Request.Operation, e.Request.Estimate, e.Reason)
+}
+
+type spendRecord struct {
+	at     time.Time
+	amount Money
+}
+
+// spendGuard enforces the SpendingPolicy of a Client
+type spendGuard struct {
+	policy *SpendingPolicy
+	now    func() time.Time
+
+	m       sync.Mutex
+	records []*spendRecord
+}
+
+// SetSpendingPolicy sets the policy all the purchasing operations are checked against.
+// The charges made so far are kept, so replacing the policy doesn't reset the cumulative limit.
+// The charges older than the Window are dropped as the policy is checked, so a wider Window set later doesn't bring them back.
+// Pass nil to remove the policy.
+func (c *Client) SetSpendingPolicy(policy *SpendingPolicy) {
+	c.spendGuard.m.Lock()
+	defer c.spendGuard.m.Unlock()
+
+	if policy != nil {
+		copied := *policy
+		if copied.Estimator == nil {
+			copied.Estimator = NewCostEstimator(c, nil)
+		}
+		policy = &copied
+	}
+	c.spendGuard.policy = policy
+}
+
+// Spent returns the charges made by the Client within the spending policy window,
+// including the estimates of the purchases in flight and the ones with unknown outcome
+func (c *Client) Spent() Money {
+	c.spendGuard.m.Lock()
+	defer c.spendGuard.m.Unlock()
+
+	return c.spendGuard.spentInWindow()
+}
+
+// spend runs the purchase under the spending policy.
+// The purchase func returns the actual charge, and whether the outcome is definitive. The estimate is kept
+// reserved for the failed purchases with unknown outcome (e.g. timeouts), since the money might have been charged.
+func (sg *spendGuard) spend(ctx context.Context, operation PlannedOperation, promotionCode string, purchase func() (charged *Money, definitive bool, err error)) error {
+	sg.m.Lock()
+	policy := sg.policy
+	sg.m.Unlock()
+
+	if policy == nil {
+		_, _, err := purchase()
+		return err
+	}
+
+	record, err := sg.authorize(ctx, policy, operation, promotionCode)
+	if err != nil {
+		return err
+	}
+
+	charged, definitive, err := purchase()
+
+	sg.m.Lock()
+	defer sg.m.Unlock()
+
+	switch {
+	case charged != nil:
+		record.amount = *charged
+	case err != nil && definitive:
+		sg.release(record)
+	}
+
+	return err
+}
+
+func (sg *spendGuard) authorize(ctx context.Context, policy *SpendingPolicy, operation PlannedOperation, promotionCode string) (*spendRecord, error) {
+	estimate, err := policy.Estimator.Estimate(ctx, &CostEstimateArgs{
+		Operations:    []PlannedOperation{operation},
+		PromotionCode: promotionCode,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("spending policy failed to estimate %s: %v", operation, err)
+	}
+	item := estimate.Items[0]
+
+	request := SpendRequest{
+		Operation: item.Operation,
+		Estimate:  item.Total,
+		IsPremium: item.IsPremium,
+	}
+
+	sg.m.Lock()
+	request.SpentInWindow = sg.spentInWindow()
+	err = checkSpendingPolicy(policy, request)
+	sg.m.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if policy.Confirm != nil {
+		confirmed, err := policy.Confirm(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		if !confirmed {
+			return nil, &SpendingPolicyError{Request: request, Reason: "not confirmed"}
+		}
+	}
+
+	sg.m.Lock()
+	defer sg.m.Unlock()
+
+	// the limits are checked again, since other purchases might have been made while waiting for the confirmation
+	request.SpentInWindow = sg.spentInWindow()
+	err = checkSpendingPolicy(policy, request)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &spendRecord{at: sg.now(), amount: request.Estimate}
+	sg.records = append(sg.records, record)
+
+	return record, nil
+}
+
+func checkSpendingPolicy(policy *SpendingPolicy, request SpendRequest) error {
+	if request.IsPremium && !policy.AllowPremium {
+		return &SpendingPolicyError{Request: request, Reason: "premium domains aren't allowed"}
+	}
+
+	if !policy.MaxChargePerOperation.IsZero() {
+		cmp, err := request.Estimate.Cmp(policy.MaxChargePerOperation)
+		if err != nil {
+			return &SpendingPolicyError{Request: request, Reason: err.Error()}
+		}
+		if cmp > 0 {
+			return &SpendingPolicyError{Request: request, Reason: fmt.Sprintf("exceeds the maximum charge per operation of %s", policy.MaxChargePerOperation)}
+		}
+	}
+
+	if !policy.MaxTotalCharge.IsZero() {
+		total, err := request.SpentInWindow.Add(request.Estimate)
+		if err == nil {
+			var cmp int
+			cmp, err = total.Cmp(policy.MaxTotalCharge)
+			if err == nil && cmp > 0 {
+				return &SpendingPolicyError{Request: request, Reason: fmt.Sprintf("%s already spent, exceeds the maximum total charge of %s", request.SpentInWindow, policy.MaxTotalCharge)}
+			}
+		}
+		if err != nil {
+			return &SpendingPolicyError{Request: request, Reason: err.Error()}
+		}
+	}
+
+	return nil
+}
+
+// spentInWindow must be called with the lock held
+func (sg *spendGuard) spentInWindow() Money {
+	sg.prune()
+
+	var spent Money
+	for _, record := range sg.records {
+		// all the API amounts are in the account currency, so the sum doesn't fail
+		spent, _ = spent.Add(record.amount)
+	}
+	return spent
+}
+
+// prune drops the records older than the policy window, it must be called with the lock held
+func (sg *spendGuard) prune() {
+	if sg.policy == nil || sg.policy.Window == 0 {
+		return
+	}
+
+	now := sg.now()
+	kept := sg.records[:0]
+	for _, record := range sg.records {
+		if now.Sub(record.at) < sg.policy.Window {
+			kept = append(kept, record)
+		}
+	}
+	// clear the tail, so the dropped records can be collected
+	for i := len(kept); i < len(sg.records); i++ {
+		sg.records[i] = nil
+	}
+	sg.records = kept
+}
+
+// release must be called with the lock held
+func (sg *spendGuard) release(record *spendRecord) {
+	for i, r := range sg.records {
+		if r == record {
+			sg.records = append(sg.records[:i], sg.records[i+1:]...)
+			return
+		}
+	}
+}
+
+func spendOperationForCreate(args DomainCreateArgs) PlannedOperation {
+	return PlannedOperation{
+		Action: ActionNameRegister,
+		Domain: strings.ToLower(strings.TrimSpace(args.DomainName)),
+		Years:  args.Years,
+	}
+}
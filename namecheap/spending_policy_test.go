@@ -0,0 +1,191 @@
+package namecheap
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newSpendingPolicyMockServer(createCalls *int32, createResponse string) *httptest.Server {
+	estimatorHandler := newCostEstimatorMockHandler("1000.00", nil)
+
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := ioutil.ReadAll(request.Body)
+		query, _ := url.ParseQuery(string(body))
+
+		if query.Get("Command") != "namecheap.domains.create" {
+			request.Body = ioutil.NopCloser(bytes.NewReader(body))
+			estimatorHandler(writer, request)
+			return
+		}
+
+		atomic.AddInt32(createCalls, 1)
+		_, _ = writer.Write([]byte(createResponse))
+	}))
+}
+
+func newPolicyTestCreateResponse(domain string, charged string) string {
+	return `
+		<?xml version="1.0" encoding="utf-8"?>
+		<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+			<Errors />
+			<CommandResponse Type="namecheap.domains.create">
+				<DomainCreateResult Domain="` + domain + `" Registered="true" ChargedAmount="` + charged + `" DomainID="9007" OrderID="196074" TransactionID="380716" WhoisguardEnable="false" NonRealTimeDomain="false" />
+			</CommandResponse>
+		</ApiResponse>
+	`
+}
+
+func TestSpendingPolicy(t *testing.T) {
+	setupPolicyClient := func(createResponse string) (*Client, *int32, func()) {
+		var createCalls int32
+		mockServer := newSpendingPolicyMockServer(&createCalls, createResponse)
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		return client, &createCalls, mockServer.Close
+	}
+
+	t.Run("no_policy", func(t *testing.T) {
+		client, createCalls, closeServer := setupPolicyClient(newPolicyTestCreateResponse("premium.com", "150.18"))
+		defer closeServer()
+
+		result, err := client.Domains.Create(context.TODO(), NewDomainCreateArgs("premium.com", 1, fakeContact))
+		if err != nil {
+			t.Fatal("Unable to create domain", err)
+		}
+
+		assert.Equal(t, int32(1), *createCalls)
+		assert.Equal(t, MustParseMoney("150.18", "USD"), result.ChargedAmount)
+	})
+
+	t.Run("premium_not_allowed", func(t *testing.T) {
+		client, createCalls, closeServer := setupPolicyClient(newPolicyTestCreateResponse("premium.com", "150.18"))
+		defer closeServer()
+
+		client.SetSpendingPolicy(&SpendingPolicy{})
+
+		_, err := client.Domains.Create(context.TODO(), NewDomainCreateArgs("premium.com", 1, fakeContact))
+
+		var policyErr *SpendingPolicyError
+		if assert.True(t, errors.As(err, &policyErr)) {
+			assert.Equal(t, "premium domains aren't allowed", policyErr.Reason)
+			assert.Equal(t, MustParseMoney("150.18", "USD"), policyErr.Request.Estimate)
+		}
+		assert.Equal(t, int32(0), *createCalls)
+	})
+
+	t.Run("max_charge_per_operation", func(t *testing.T) {
+		client, createCalls, closeServer := setupPolicyClient(newPolicyTestCreateResponse("new.com", "22.86"))
+		defer closeServer()
+
+		client.SetSpendingPolicy(&SpendingPolicy{MaxChargePerOperation: MustParseMoney("20", "USD")})
+
+		_, err := client.Domains.Create(context.TODO(), NewDomainCreateArgs("new.com", 2, fakeContact))
+
+		assert.EqualError(t, err, "spending policy rejected register new.com for 2 year(s) estimated at 23.22 USD: exceeds the maximum charge per operation of 20.00 USD")
+		assert.Equal(t, int32(0), *createCalls)
+
+		_, err = client.Domains.Create(context.TODO(), NewDomainCreateArgs("new.com", 1, fakeContact))
+
+		assert.Nil(t, err)
+		assert.Equal(t, int32(1), *createCalls)
+	})
+
+	t.Run("max_total_charge", func(t *testing.T) {
+		client, createCalls, closeServer := setupPolicyClient(newPolicyTestCreateResponse("new.com", "9.06"))
+		defer closeServer()
+
+		now := time.Date(2021, 6, 2, 0, 0, 0, 0, time.UTC)
+		client.spendGuard.now = func() time.Time { return now }
+		client.SetSpendingPolicy(&SpendingPolicy{MaxTotalCharge: MustParseMoney("20", "USD"), Window: time.Hour})
+
+		for i := 0; i < 2; i++ {
+			_, err := client.Domains.Create(context.TODO(), NewDomainCreateArgs("new.com", 1, fakeContact))
+			assert.Nil(t, err)
+		}
+		assert.Equal(t, MustParseMoney("18.12", "USD"), client.Spent())
+
+		_, err := client.Domains.Create(context.TODO(), NewDomainCreateArgs("new.com", 1, fakeContact))
+		assert.EqualError(t, err, "spending policy rejected register new.com for 1 year(s) estimated at 9.06 USD: 18.12 USD already spent, exceeds the maximum total charge of 20.00 USD")
+		assert.Equal(t, int32(2), *createCalls)
+
+		now = now.Add(time.Hour)
+		_, err = client.Domains.Create(context.TODO(), NewDomainCreateArgs("new.com", 1, fakeContact))
+		assert.Nil(t, err)
+		assert.Equal(t, int32(3), *createCalls)
+
+		// the records outside the window are dropped
+		assert.Len(t, client.spendGuard.records, 1)
+		assert.Equal(t, MustParseMoney("9.06", "USD"), client.Spent())
+	})
+
+	t.Run("confirmation_callback", func(t *testing.T) {
+		client, createCalls, closeServer := setupPolicyClient(newPolicyTestCreateResponse("new.com", "9.06"))
+		defer closeServer()
+
+		var confirmed []SpendRequest
+		answer := false
+		client.SetSpendingPolicy(&SpendingPolicy{
+			Confirm: func(ctx context.Context, request SpendRequest) (bool, error) {
+				confirmed = append(confirmed, request)
+				return answer, nil
+			},
+		})
+
+		_, err := client.Domains.Create(context.TODO(), NewDomainCreateArgs("new.com", 1, fakeContact))
+		assert.EqualError(t, err, "spending policy rejected register new.com for 1 year(s) estimated at 9.06 USD: not confirmed")
+		assert.Equal(t, int32(0), *createCalls)
+
+		answer = true
+		_, err = client.Domains.Create(context.TODO(), NewDomainCreateArgs("new.com", 1, fakeContact))
+		assert.Nil(t, err)
+		assert.Equal(t, int32(1), *createCalls)
+
+		if assert.Len(t, confirmed, 2) {
+			assert.Equal(t, MustParseMoney("9.06", "USD"), confirmed[1].Estimate)
+		}
+	})
+
+	t.Run("api_error_releases_reservation", func(t *testing.T) {
+		client, createCalls, closeServer := setupPolicyClient(`
+			<?xml version="1.0" encoding="utf-8"?>
+			<ApiResponse Status="ERROR" xmlns="http://api.namecheap.com/xml.response">
+				<Errors>
+					<Error Number="2033409">Domain is not available</Error>
+				</Errors>
+			</ApiResponse>
+		`)
+		defer closeServer()
+
+		client.SetSpendingPolicy(&SpendingPolicy{MaxTotalCharge: MustParseMoney("100", "USD")})
+
+		_, err := client.Domains.Create(context.TODO(), NewDomainCreateArgs("new.com", 1, fakeContact))
+
+		assert.EqualError(t, err, "Domain is not available (2033409)")
+		assert.Equal(t, int32(1), *createCalls)
+		assert.True(t, client.Spent().IsZero())
+	})
+
+	t.Run("estimation_failure_rejects", func(t *testing.T) {
+		client, createCalls, closeServer := setupPolicyClient(newPolicyTestCreateResponse("mine.net", "9.06"))
+		defer closeServer()
+
+		client.SetSpendingPolicy(&SpendingPolicy{})
+
+		_, err := client.Domains.Create(context.TODO(), NewDomainCreateArgs("mine.net", 5, fakeContact))
+
+		assert.EqualError(t, err, "spending policy failed to estimate register mine.net for 5 year(s): no register price found for net for 5 year(s)")
+		assert.Equal(t, int32(0), *createCalls)
+	})
+}
@@ -0,0 +1,67 @@
+package namecheap
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxTXTStringLength is the length limit of a single character string of a TXT record
+const maxTXTStringLength = 255
+
+// TXTHostRecord returns the TXT host record of the host name with the value, e.g. a DKIM key.
+// The value is written unquoted and unsplit, the way GetHosts returns it, Namecheap splits the values longer
+// than 255 characters into several strings itself. Use UnquoteTXT for the values copied from a zone file or dig.
+func TXTHostRecord(hostName string, value string) DomainsDNSHostRecord {
+	return DomainsDNSHostRecord{
+		HostName:   hostName,
+		RecordType: RecordTypeTXT,
+		Address:    value,
+	}
+}
+
+// SplitTXT splits the value into the character strings of at most 255 bytes a TXT record is made of
+func SplitTXT(value string) []string {
+	chunks := []string{}
+	for len(value) > maxTXTStringLength {
+		chunks = append(chunks, value[:maxTXTStringLength])
+		value = value[maxTXTStringLength:]
+	}
+	return append(chunks, value)
+}
+
+// QuoteTXT encodes the value in the zone file presentation format: the quoted strings of at most 255 bytes
+// separated by spaces, with the quotes and the backslashes escaped, e.g. "v=spf1 -all"
+func QuoteTXT(value string) string {
+	chunks := SplitTXT(value)
+	for i, chunk := range chunks {
+		chunks[i] = quoteZoneString(chunk)
+	}
+	return strings.Join(chunks, " ")
+}
+
+// UnquoteTXT decodes the value from the zone file presentation format, e.g. as printed by dig.
+// The quoted strings are joined without a separator, a value without the quotes is returned as is.
+func UnquoteTXT(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, `"`) {
+		return value, nil
+	}
+
+	var unquoted strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case ' ', '\t':
+			continue
+		case '"':
+			chunk, end, err := readZoneQuoted(value, i+1)
+			if err != nil {
+				return "", fmt.Errorf("invalid TXT value: %s, %v", value, err)
+			}
+			unquoted.WriteString(chunk)
+			i = end
+		default:
+			return "", fmt.Errorf("invalid TXT value: %s, unexpected %q outside of the quoted strings", value, value[i])
+		}
+	}
+	return unquoted.String(), nil
+}
@@ -0,0 +1,142 @@
+package namecheap
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTXTHostRecord(t *testing.T) {
+	record := TXTHostRecord("@", "v=spf1 -all")
+
+	assert.Equal(t, DomainsDNSHostRecord{HostName: "@", RecordType: RecordTypeTXT, Address: "v=spf1 -all"}, record)
+}
+
+func TestSplitTXT(t *testing.T) {
+	var cases = map[string]struct {
+		Value    string
+		Expected []string
+	}{
+		"empty": {
+			Value:    "",
+			Expected: []string{""},
+		},
+		"short": {
+			Value:    "v=spf1 -all",
+			Expected: []string{"v=spf1 -all"},
+		},
+		"exactly_255": {
+			Value:    strings.Repeat("a", 255),
+			Expected: []string{strings.Repeat("a", 255)},
+		},
+		"long": {
+			Value:    strings.Repeat("a", 255) + strings.Repeat("b", 255) + "c",
+			Expected: []string{strings.Repeat("a", 255), strings.Repeat("b", 255), "c"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.Expected, SplitTXT(c.Value))
+		})
+	}
+}
+
+func TestQuoteTXT(t *testing.T) {
+	var cases = map[string]struct {
+		Value    string
+		Expected string
+	}{
+		"empty": {
+			Value:    "",
+			Expected: `""`,
+		},
+		"plain": {
+			Value:    "v=spf1 -all",
+			Expected: `"v=spf1 -all"`,
+		},
+		"escaped": {
+			Value:    `say "hi" \o/`,
+			Expected: `"say \"hi\" \\o/"`,
+		},
+		"long": {
+			Value:    strings.Repeat("a", 256),
+			Expected: `"` + strings.Repeat("a", 255) + `" "a"`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.Expected, QuoteTXT(c.Value))
+		})
+	}
+}
+
+func TestUnquoteTXT(t *testing.T) {
+	var cases = map[string]struct {
+		Value    string
+		Expected string
+	}{
+		"unquoted": {
+			Value:    "v=spf1 -all",
+			Expected: "v=spf1 -all",
+		},
+		"quoted": {
+			Value:    `"v=spf1 -all"`,
+			Expected: "v=spf1 -all",
+		},
+		"escaped": {
+			Value:    `"say \"hi\" \\o/ \059"`,
+			Expected: `say "hi" \o/ ;`,
+		},
+		"several_strings": {
+			Value:    ` "v=DKIM1; k=rsa; " 	"p=MIIB" `,
+			Expected: "v=DKIM1; k=rsa; p=MIIB",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			value, err := UnquoteTXT(c.Value)
+			if err != nil {
+				t.Fatal("Unable to unquote TXT value", err)
+			}
+
+			assert.Equal(t, c.Expected, value)
+		})
+	}
+
+	var errorCases = map[string]struct {
+		Value         string
+		ExpectedError string
+	}{
+		"unterminated": {
+			Value:         `"v=spf1 -all`,
+			ExpectedError: `invalid TXT value: "v=spf1 -all, unterminated quoted string`,
+		},
+		"text_between_strings": {
+			Value:         `"v=spf1" -all`,
+			ExpectedError: `invalid TXT value: "v=spf1" -all, unexpected '-' outside of the quoted strings`,
+		},
+	}
+
+	for name, c := range errorCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := UnquoteTXT(c.Value)
+
+			assert.EqualError(t, err, c.ExpectedError)
+		})
+	}
+
+	t.Run("round_trip", func(t *testing.T) {
+		value := `v=DKIM1; k=rsa; p=` + strings.Repeat(`x"\`, 200)
+
+		unquoted, err := UnquoteTXT(QuoteTXT(value))
+		if err != nil {
+			t.Fatal("Unable to unquote TXT value", err)
+		}
+
+		assert.Equal(t, value, unquoted)
+	})
+}
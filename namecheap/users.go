@@ -2,6 +2,7 @@ package namecheap
 
 // UserssService includes the following methods:
 // UserssService.GetPricing - Returns pricing information for a requested product type.
+// UserssService.GetBalances - Returns information about fund in the user's account.
 //
 // Namecheap doc: https://www.namecheap.com/support/api/methods/users/
 type UsersService service
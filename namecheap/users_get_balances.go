@@ -0,0 +1,91 @@
+package namecheap
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+type UserGetBalancesResponse struct {
+	XMLName xml.Name `xml:"ApiResponse"`
+	Errors  []struct {
+		Message string `xml:",chardata"`
+		Number  string `xml:"Number,attr"`
+	} `xml:"Errors>Error"`
+	CommandResponse *UserGetBalancesCommandResponse `xml:"CommandResponse"`
+}
+
+type UserGetBalancesCommandResponse struct {
+	UserGetBalancesResult UserGetBalancesResult `xml:"UserGetBalancesResult"`
+}
+
+/*
+Currency	Currency in which balances are listed
+AvailableBalance	Available balance on the account
+AccountBalance	Total balance on the account
+EarnedAmount	Amount earned as commission on the account
+WithdrawableAmount	Amount that can be withdrawn from the account
+FundsRequiredForAutoRenew	Funds required to auto-renew the domains and other products
+*/
+type UserGetBalancesResult struct {
+	Currency                  string `xml:"Currency,attr"`
+	AvailableBalance          Money  `xml:"AvailableBalance,attr"`
+	AccountBalance            Money  `xml:"AccountBalance,attr"`
+	EarnedAmount              Money  `xml:"EarnedAmount,attr"`
+	WithdrawableAmount        Money  `xml:"WithdrawableAmount,attr"`
+	FundsRequiredForAutoRenew Money  `xml:"FundsRequiredForAutoRenew,attr"`
+}
+
+// UnmarshalXML decodes the balances and applies their Currency to all the amounts
+func (r *UserGetBalancesResult) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type result UserGetBalancesResult
+	var decoded result
+
+	err := d.DecodeElement(&decoded, &start)
+	if err != nil {
+		return err
+	}
+
+	*r = UserGetBalancesResult(decoded)
+	if r.Currency != "" {
+		currency := strings.ToUpper(r.Currency)
+		r.AvailableBalance.Currency = currency
+		r.AccountBalance.Currency = currency
+		r.EarnedAmount.Currency = currency
+		r.WithdrawableAmount.Currency = currency
+		r.FundsRequiredForAutoRenew.Currency = currency
+	}
+
+	return nil
+}
+
+func (r UserGetBalancesResult) String() string {
+	return fmt.Sprintf("{AvailableBalance: %s, AccountBalance: %s, EarnedAmount: %s, WithdrawableAmount: %s, FundsRequiredForAutoRenew: %s}",
+		r.AvailableBalance, r.AccountBalance, r.EarnedAmount, r.WithdrawableAmount, r.FundsRequiredForAutoRenew)
+}
+
+// GetBalances returns information about fund in the user's account
+//
+// Namecheap doc: https://www.namecheap.com/support/api/methods/users/get-balances/
+func (us *UsersService) GetBalances(ctx context.Context) (*UserGetBalancesResult, error) {
+	var resp UserGetBalancesResponse
+
+	params := map[string]string{
+		"Command": "namecheap.users.getBalances",
+	}
+
+	_, err := us.client.DoXML(ctx, params, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Errors != nil && len(resp.Errors) > 0 {
+		apiErr := resp.Errors[0]
+		return nil, fmt.Errorf("%s (%s)", apiErr.Message, apiErr.Number)
+	}
+	if resp.CommandResponse == nil {
+		return nil, fmt.Errorf("empty command response")
+	}
+
+	return &resp.CommandResponse.UserGetBalancesResult, nil
+}
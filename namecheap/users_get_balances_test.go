@@ -0,0 +1,95 @@
+package namecheap
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsersGetBalances(t *testing.T) {
+	fakeResponse := `
+		<?xml version="1.0" encoding="utf-8"?>
+		<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+			<Errors />
+			<Warnings />
+			<RequestedCommand>namecheap.users.getBalances</RequestedCommand>
+			<CommandResponse Type="namecheap.users.getBalances">
+				<UserGetBalancesResult Currency="USD" AvailableBalance="4932.96" AccountBalance="4932.96" EarnedAmount="381.70" WithdrawableAmount="1243.36" FundsRequiredForAutoRenew="0.00" />
+			</CommandResponse>
+			<Server>PHX01SBAPIEXT05</Server>
+			<GMTTimeDifference>--4:00</GMTTimeDifference>
+			<ExecutionTime>0.024</ExecutionTime>
+		</ApiResponse>
+	`
+
+	t.Run("request_command", func(t *testing.T) {
+		var sentBody url.Values
+
+		mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			body, _ := ioutil.ReadAll(request.Body)
+			query, _ := url.ParseQuery(string(body))
+			sentBody = query
+			_, _ = writer.Write([]byte(fakeResponse))
+		}))
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		_, err := client.UsersService.GetBalances(context.TODO())
+		if err != nil {
+			t.Fatal("Unable to get balances", err)
+		}
+
+		assert.Equal(t, "namecheap.users.getBalances", sentBody.Get("Command"))
+	})
+
+	t.Run("correct_parsing_result", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			_, _ = writer.Write([]byte(fakeResponse))
+		}))
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		balances, err := client.UsersService.GetBalances(context.TODO())
+		if err != nil {
+			t.Fatal("Unable to get balances", err)
+		}
+
+		assert.Equal(t, MustParseMoney("4932.96", "USD"), balances.AvailableBalance)
+		assert.Equal(t, MustParseMoney("381.70", "USD"), balances.EarnedAmount)
+		assert.True(t, balances.FundsRequiredForAutoRenew.IsZero())
+	})
+
+	t.Run("error_response", func(t *testing.T) {
+		fakeResponse := `
+			<?xml version="1.0" encoding="utf-8"?>
+			<ApiResponse Status="ERROR" xmlns="http://api.namecheap.com/xml.response">
+				<Errors>
+					<Error Number="1011102">API Key is invalid or API access has not been enabled</Error>
+				</Errors>
+				<Warnings />
+				<RequestedCommand />
+			</ApiResponse>
+		`
+
+		mockServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			_, _ = writer.Write([]byte(fakeResponse))
+		}))
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		_, err := client.UsersService.GetBalances(context.TODO())
+
+		assert.EqualError(t, err, "API Key is invalid or API access has not been enabled (1011102)")
+	})
+}
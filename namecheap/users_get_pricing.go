@@ -105,13 +105,35 @@ type ProductCategory struct {
 type Price struct {
 	Duration     string `xml:"Duration,attr"`
 	DurationType string `xml:"DurationType,attr"`
-	Price        string `xml:"Price,attr"`
-	RegularPrice string `xml:"RegularPrice,attr"`
-	YourPrice    string `xml:"YourPrice,attr"`
-	CouponPrice  string `xml:"CouponPrice,attr"`
+	Price        Money  `xml:"Price,attr"`
+	RegularPrice Money  `xml:"RegularPrice,attr"`
+	YourPrice    Money  `xml:"YourPrice,attr"`
+	CouponPrice  Money  `xml:"CouponPrice,attr"`
 	Currency     string `xml:"Currency,attr"`
 }
 
+// UnmarshalXML decodes the price and applies its Currency to all the amounts
+func (p *Price) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type price Price
+	var decoded price
+
+	err := d.DecodeElement(&decoded, &start)
+	if err != nil {
+		return err
+	}
+
+	*p = Price(decoded)
+	if p.Currency != "" {
+		currency := strings.ToUpper(p.Currency)
+		p.Price.Currency = currency
+		p.RegularPrice.Currency = currency
+		p.YourPrice.Currency = currency
+		p.CouponPrice.Currency = currency
+	}
+
+	return nil
+}
+
 type Product struct {
 	Name  string  `xml:"Name,attr"`
 	Price []Price `xml:"Price"`
@@ -140,6 +162,10 @@ func (us *UsersService) GetPricing(ctx context.Context, args UserGetPricingArgs)
 		apiErr = fmt.Errorf("%s", strings.Join(errMessages, "; "))
 	}
 
+	if resp.CommandResponse == nil {
+		return nil, apiErr
+	}
+
 	return &resp.CommandResponse.UserGetPricingResult, apiErr
 }
 
@@ -0,0 +1,50 @@
+package namecheap
+
+import "strings"
+
+// ValidationIssue is a single problem found by the client-side validation
+type ValidationIssue struct {
+	// Name of the offending field, e.g. RegistrantPhone or Records[2].Address
+	Field string
+	// Human-readable description of the problem, it includes the field name
+	Message string
+}
+
+func (i ValidationIssue) String() string {
+	return i.Message
+}
+
+// ValidationError is returned when the arguments fail the client-side validation.
+// It lists every issue found rather than the first one.
+type ValidationError struct {
+	Issues []ValidationIssue
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Issues))
+	for _, issue := range e.Issues {
+		messages = append(messages, issue.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Fields returns the names of the offending fields
+func (e *ValidationError) Fields() []string {
+	fields := make([]string, 0, len(e.Issues))
+	for _, issue := range e.Issues {
+		fields = append(fields, issue.Field)
+	}
+	return fields
+}
+
+func (e *ValidationError) add(field string, message string) {
+	e.Issues = append(e.Issues, ValidationIssue{Field: field, Message: message})
+}
+
+// errorOrNil returns nil when there are no issues, so the result can be returned as error directly
+func (e *ValidationError) errorOrNil() error {
+	if len(e.Issues) == 0 {
+		return nil
+	}
+	return e
+}
@@ -0,0 +1,514 @@
+package namecheap
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// zoneFileAnnotation prefixes the Namecheap-only records in the exported zone files,
+// they're comments for the other tools and records for ParseZoneFile
+const zoneFileAnnotation = "; namecheap:"
+
+// zoneFileEmailTypeAnnotation is the comment the EmailType of the exported domain is written to
+const zoneFileEmailTypeAnnotation = "; email type:"
+
+// WriteZoneFile writes the host records as an RFC 1035 zone file relative to the $ORIGIN of the domain.
+// A, AAAA, CNAME, MX, TXT, NS and CAA are written as records. The Namecheap-only types (URL, URL301, FRAME,
+// ALIAS and MXE) have no zone file equivalent, they're written as "; namecheap:" annotated comments which
+// ParseZoneFile reads back.
+func (r DomainDNSGetHostsResult) WriteZoneFile(w io.Writer) error {
+	writer := bufio.NewWriter(w)
+
+	domain := strings.TrimSuffix(strings.ToLower(r.Domain), ".")
+	if domain == "" {
+		return fmt.Errorf("domain is required")
+	}
+
+	fmt.Fprintf(writer, "; %s exported from Namecheap\n", domain)
+	if r.EmailType != "" {
+		fmt.Fprintf(writer, "%s %s\n", zoneFileEmailTypeAnnotation, r.EmailType)
+	}
+	fmt.Fprintf(writer, "$ORIGIN %s.\n", domain)
+	fmt.Fprintf(writer, "$TTL %d\n", defaultHostRecordTTL)
+
+	for _, host := range r.Hosts {
+		prefix := ""
+		var rdata string
+
+		switch strings.ToUpper(host.Type) {
+		case RecordTypeA, RecordTypeAAAA, RecordTypeCAA:
+			rdata = host.Address
+		case RecordTypeCNAME, RecordTypeNS:
+			rdata = absoluteZoneName(host.Address)
+		case RecordTypeMX:
+			rdata = fmt.Sprintf("%d %s", host.MXPref, absoluteZoneName(host.Address))
+		case RecordTypeTXT:
+			rdata = QuoteTXT(host.Address)
+		case RecordTypeAlias:
+			prefix = zoneFileAnnotation + " "
+			rdata = absoluteZoneName(host.Address)
+		case RecordTypeMXE:
+			prefix = zoneFileAnnotation + " "
+			rdata = host.Address
+		case RecordTypeURL, RecordTypeURL301, RecordTypeFrame:
+			prefix = zoneFileAnnotation + " "
+			rdata = quoteZoneString(host.Address)
+		default:
+			return fmt.Errorf("unsupported record type %s of host %s", host.Type, host.Name)
+		}
+
+		ttl := host.TTL
+		if ttl == 0 {
+			ttl = defaultHostRecordTTL
+		}
+
+		fmt.Fprintf(writer, "%s%s\t%d\tIN\t%s\t%s\n", prefix, host.Name, ttl, strings.ToUpper(host.Type), rdata)
+	}
+
+	return writer.Flush()
+}
+
+// ParseZoneFile reads an RFC 1035 zone file of the domain into the SetHosts arguments.
+//
+// The $ORIGIN and $TTL directives, relative and absolute names, blank owners, TTL units (e.g. 1h),
+// parentheses and the "; namecheap:" annotations written by WriteZoneFile are supported.
+// SOA and apex NS records are skipped, since Namecheap manages them.
+// The TTLs are clamped to the range allowed by Namecheap, 60 to 60000 seconds.
+// Other record types, classes and directives, e.g. SRV or $INCLUDE, are reported as errors with the line number.
+//
+// The EmailType is read from the "; email type:" comment written by WriteZoneFile. Without it, it's MXE or MX
+// if the zone has records of the type, since SetHosts only accepts them along with the EmailType.
+func ParseZoneFile(r io.Reader, domain string) (*DomainsDNSSetHostsArgs, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+	if domain == "" {
+		return nil, fmt.Errorf("domain is required")
+	}
+
+	lines, err := tokenizeZoneFile(string(content))
+	if err != nil {
+		return nil, err
+	}
+
+	parser := zoneFileParser{domain: domain, origin: domain + "."}
+
+	args := &DomainsDNSSetHostsArgs{
+		Domain:    domain,
+		Records:   []DomainsDNSHostRecord{},
+		EmailType: zoneFileEmailType(string(content)),
+	}
+	for _, line := range lines {
+		record, err := parser.parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line.number, err)
+		}
+		if record != nil {
+			args.Records = append(args.Records, *record)
+		}
+	}
+
+	if args.EmailType == "" {
+		for _, record := range args.Records {
+			if record.RecordType == RecordTypeMXE {
+				args.EmailType = EmailTypeMXE
+				break
+			}
+			if record.RecordType == RecordTypeMX {
+				args.EmailType = EmailTypeMX
+			}
+		}
+	}
+
+	return args, nil
+}
+
+// zoneFileEmailType returns the EmailType of the "; email type:" comment, if any
+func zoneFileEmailType(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, zoneFileEmailTypeAnnotation) {
+			return strings.ToUpper(strings.TrimSpace(line[len(zoneFileEmailTypeAnnotation):]))
+		}
+	}
+	return ""
+}
+
+type zoneToken struct {
+	value  string
+	quoted bool
+}
+
+type zoneLine struct {
+	number     int
+	tokens     []zoneToken
+	blankOwner bool
+}
+
+// tokenizeZoneFile splits the zone file into logical lines of tokens, joining the lines within parentheses
+func tokenizeZoneFile(content string) ([]zoneLine, error) {
+	var lines []zoneLine
+
+	current := zoneLine{number: 1}
+	number := 1
+	depth := 0
+	lineStart := true
+
+	var token strings.Builder
+	inToken := false
+	flush := func() {
+		if inToken {
+			current.tokens = append(current.tokens, zoneToken{value: token.String()})
+			token.Reset()
+			inToken = false
+		}
+	}
+
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+
+		if lineStart && depth == 0 {
+			lineStart = false
+			// the annotated Namecheap-only records are parsed as the regular ones
+			if strings.HasPrefix(content[i:], zoneFileAnnotation) {
+				i += len(zoneFileAnnotation)
+				for i < len(content) && (content[i] == ' ' || content[i] == '\t') {
+					i++
+				}
+				if i >= len(content) {
+					break
+				}
+				c = content[i]
+			} else {
+				current.blankOwner = c == ' ' || c == '\t'
+			}
+		}
+
+		switch {
+		case c == '\n':
+			flush()
+			number++
+			if depth == 0 {
+				if len(current.tokens) > 0 {
+					lines = append(lines, current)
+				}
+				current = zoneLine{number: number}
+				lineStart = true
+			}
+		case c == ' ' || c == '\t' || c == '\r':
+			flush()
+		case c == ';':
+			flush()
+			for i+1 < len(content) && content[i+1] != '\n' {
+				i++
+			}
+		case c == '(':
+			flush()
+			depth++
+		case c == ')':
+			flush()
+			if depth == 0 {
+				return nil, fmt.Errorf("line %d: unbalanced parentheses", number)
+			}
+			depth--
+		case c == '"':
+			flush()
+			value, end, err := readZoneQuoted(content, i+1)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", number, err)
+			}
+			number += strings.Count(content[i:end], "\n")
+			current.tokens = append(current.tokens, zoneToken{value: value, quoted: true})
+			i = end
+		case c == '\\' && i+1 < len(content):
+			inToken = true
+			i++
+			token.WriteByte(content[i])
+		default:
+			inToken = true
+			token.WriteByte(c)
+		}
+	}
+
+	flush()
+	if depth != 0 {
+		return nil, fmt.Errorf("line %d: unbalanced parentheses", current.number)
+	}
+	if len(current.tokens) > 0 {
+		lines = append(lines, current)
+	}
+
+	return lines, nil
+}
+
+// readZoneQuoted reads a quoted string starting after the opening quote, it returns the index of the closing quote
+func readZoneQuoted(content string, start int) (string, int, error) {
+	var value strings.Builder
+	for i := start; i < len(content); i++ {
+		switch c := content[i]; c {
+		case '"':
+			return value.String(), i, nil
+		case '\\':
+			if i+1 >= len(content) {
+				break
+			}
+			// \DDD is a decimal byte value
+			if i+3 < len(content) && isDigits(content[i+1:i+4]) {
+				code, _ := strconv.Atoi(content[i+1 : i+4])
+				if code > 255 {
+					return "", 0, fmt.Errorf("invalid escape \\%s", content[i+1:i+4])
+				}
+				value.WriteByte(byte(code))
+				i += 3
+				continue
+			}
+			i++
+			value.WriteByte(content[i])
+		default:
+			value.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated quoted string")
+}
+
+type zoneFileParser struct {
+	domain     string
+	origin     string
+	defaultTTL int
+	lastOwner  string
+}
+
+func (p *zoneFileParser) parseLine(line zoneLine) (*DomainsDNSHostRecord, error) {
+	tokens := line.tokens
+
+	if first := tokens[0]; !first.quoted && strings.HasPrefix(first.value, "$") {
+		return nil, p.parseDirective(tokens)
+	}
+
+	owner := p.lastOwner
+	if line.blankOwner {
+		if owner == "" {
+			return nil, fmt.Errorf("record without an owner name")
+		}
+	} else {
+		owner = p.absoluteName(tokens[0].value)
+		tokens = tokens[1:]
+	}
+	p.lastOwner = owner
+
+	ttl := p.defaultTTL
+	// the TTL and the class are optional, in any order
+	for len(tokens) > 0 {
+		value := strings.ToUpper(tokens[0].value)
+		if value == "IN" {
+			tokens = tokens[1:]
+		} else if value == "CH" || value == "HS" || value == "CS" {
+			return nil, fmt.Errorf("unsupported class %s", tokens[0].value)
+		} else if parsed, err := parseZoneTTL(tokens[0].value); err == nil && !tokens[0].quoted {
+			ttl = parsed
+			tokens = tokens[1:]
+		} else {
+			break
+		}
+	}
+
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("record type is missing")
+	}
+	recordType := strings.ToUpper(tokens[0].value)
+	rdata := tokens[1:]
+
+	hostName, err := p.hostName(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &DomainsDNSHostRecord{
+		HostName:   hostName,
+		RecordType: recordType,
+		TTL:        clampTTL(ttl),
+	}
+
+	switch recordType {
+	case "SOA":
+		return nil, nil
+	case RecordTypeNS:
+		if hostName == "@" {
+			return nil, nil
+		}
+		if err := expectZoneRdata(recordType, rdata, 1); err != nil {
+			return nil, err
+		}
+		record.Address = p.absoluteName(rdata[0].value)
+	case RecordTypeA, RecordTypeAAAA, RecordTypeMXE:
+		if err := expectZoneRdata(recordType, rdata, 1); err != nil {
+			return nil, err
+		}
+		ip := net.ParseIP(rdata[0].value)
+		if ip == nil || (ip.To4() == nil) == (recordType != RecordTypeAAAA) {
+			return nil, fmt.Errorf("invalid %s address %s", recordType, rdata[0].value)
+		}
+		record.Address = rdata[0].value
+	case RecordTypeCNAME, RecordTypeAlias:
+		if err := expectZoneRdata(recordType, rdata, 1); err != nil {
+			return nil, err
+		}
+		record.Address = p.absoluteName(rdata[0].value)
+	case RecordTypeMX:
+		if err := expectZoneRdata(recordType, rdata, 2); err != nil {
+			return nil, err
+		}
+		pref, err := strconv.ParseUint(rdata[0].value, 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MX preference %s, maximum value is 255", rdata[0].value)
+		}
+		record.MXPref = UInt8(uint8(pref))
+		record.Address = p.absoluteName(rdata[1].value)
+	case RecordTypeTXT:
+		if len(rdata) == 0 {
+			return nil, fmt.Errorf("TXT record without a value")
+		}
+		var value strings.Builder
+		for _, chunk := range rdata {
+			value.WriteString(chunk.value)
+		}
+		record.Address = value.String()
+	case RecordTypeCAA:
+		if err := expectZoneRdata(recordType, rdata, 3); err != nil {
+			return nil, err
+		}
+		if _, err := strconv.ParseUint(rdata[0].value, 10, 8); err != nil {
+			return nil, fmt.Errorf("invalid CAA flag %s", rdata[0].value)
+		}
+		record.Address = fmt.Sprintf("%s %s %s", rdata[0].value, strings.ToLower(rdata[1].value), quoteZoneString(rdata[2].value))
+	case RecordTypeURL, RecordTypeURL301, RecordTypeFrame:
+		if err := expectZoneRdata(recordType, rdata, 1); err != nil {
+			return nil, err
+		}
+		record.Address = rdata[0].value
+	default:
+		return nil, fmt.Errorf("unsupported record type %s", tokens[0].value)
+	}
+
+	return record, nil
+}
+
+func (p *zoneFileParser) parseDirective(tokens []zoneToken) error {
+	directive := strings.ToUpper(tokens[0].value)
+	switch directive {
+	case "$ORIGIN":
+		if len(tokens) != 2 {
+			return fmt.Errorf("$ORIGIN expects a domain name")
+		}
+		origin := p.absoluteName(tokens[1].value)
+		if _, err := p.hostName(origin); err != nil {
+			return fmt.Errorf("$ORIGIN %s is outside of %s", tokens[1].value, p.domain)
+		}
+		p.origin = origin
+	case "$TTL":
+		if len(tokens) != 2 {
+			return fmt.Errorf("$TTL expects a TTL")
+		}
+		ttl, err := parseZoneTTL(tokens[1].value)
+		if err != nil {
+			return err
+		}
+		p.defaultTTL = ttl
+	default:
+		return fmt.Errorf("unsupported directive %s", tokens[0].value)
+	}
+	return nil
+}
+
+// absoluteName returns the lower case absolute name with the trailing dot, the relative names are appended to the origin
+func (p *zoneFileParser) absoluteName(name string) string {
+	name = strings.ToLower(name)
+	if name == "@" {
+		return p.origin
+	}
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "." + p.origin
+}
+
+// hostName returns the absolute name relative to the domain, e.g. www or @ for the domain itself
+func (p *zoneFileParser) hostName(name string) (string, error) {
+	name = strings.TrimSuffix(name, ".")
+	if name == p.domain {
+		return "@", nil
+	}
+	if strings.HasSuffix(name, "."+p.domain) {
+		return strings.TrimSuffix(name, "."+p.domain), nil
+	}
+	return "", fmt.Errorf("name %s is outside of %s", name, p.domain)
+}
+
+func expectZoneRdata(recordType string, rdata []zoneToken, count int) error {
+	if len(rdata) != count {
+		return fmt.Errorf("%s record expects %d values, got %d", recordType, count, len(rdata))
+	}
+	return nil
+}
+
+// parseZoneTTL parses a TTL in seconds, or with the BIND units, e.g. 1h30m
+func parseZoneTTL(value string) (int, error) {
+	if isDigits(value) {
+		return strconv.Atoi(value)
+	}
+
+	units := map[byte]int{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}
+
+	total, number := 0, ""
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c >= '0' && c <= '9' {
+			number += string(c)
+			continue
+		}
+		unit, ok := units[c|0x20]
+		if !ok || number == "" {
+			return 0, fmt.Errorf("invalid TTL %s", value)
+		}
+		n, _ := strconv.Atoi(number)
+		total += n * unit
+		number = ""
+	}
+	if number != "" {
+		return 0, fmt.Errorf("invalid TTL %s", value)
+	}
+	return total, nil
+}
+
+func clampTTL(ttl int) int {
+	switch {
+	case ttl == 0:
+		return 0
+	case ttl < MinTTL:
+		return MinTTL
+	case ttl > MaxTTL:
+		return MaxTTL
+	}
+	return ttl
+}
+
+func absoluteZoneName(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
+
+func quoteZoneString(value string) string {
+	value = strings.Replace(value, `\`, `\\`, -1)
+	value = strings.Replace(value, `"`, `\"`, -1)
+	return `"` + value + `"`
+}
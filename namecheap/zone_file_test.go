@@ -0,0 +1,228 @@
+package namecheap
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainDNSGetHostsResultWriteZoneFile(t *testing.T) {
+	result := DomainDNSGetHostsResult{
+		Domain:        "domain.net",
+		EmailType:     "MX",
+		IsUsingOurDNS: true,
+		Hosts: []DomainsDNSHostRecordDetailed{
+			{Name: "@", Type: "A", Address: "192.0.2.1", MXPref: 10, TTL: 300},
+			{Name: "@", Type: "AAAA", Address: "2001:db8::1", MXPref: 10, TTL: 300},
+			{Name: "www", Type: "CNAME", Address: "domain.net.", MXPref: 10, TTL: 1800},
+			{Name: "@", Type: "MX", Address: "mx1.domain.net", MXPref: 5, TTL: 1800},
+			{Name: "@", Type: "TXT", Address: `v=spf1 include:"spf".domain.net ~all`, MXPref: 10, TTL: 1800},
+			{Name: "sub", Type: "NS", Address: "ns1.other.net", MXPref: 10, TTL: 1800},
+			{Name: "@", Type: "CAA", Address: `0 issue "letsencrypt.org"`, MXPref: 10, TTL: 3600},
+			{Name: "old", Type: "URL301", Address: "https://domain.net/new", MXPref: 10, TTL: 1800},
+			{Name: "app", Type: "ALIAS", Address: "app.host.net", MXPref: 10, TTL: 300},
+		},
+	}
+
+	t.Run("export", func(t *testing.T) {
+		var buffer bytes.Buffer
+		if err := result.WriteZoneFile(&buffer); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, strings.Join([]string{
+			"; domain.net exported from Namecheap",
+			"; email type: MX",
+			"$ORIGIN domain.net.",
+			"$TTL 1800",
+			"@\t300\tIN\tA\t192.0.2.1",
+			"@\t300\tIN\tAAAA\t2001:db8::1",
+			"www\t1800\tIN\tCNAME\tdomain.net.",
+			"@\t1800\tIN\tMX\t5 mx1.domain.net.",
+			`@	1800	IN	TXT	"v=spf1 include:\"spf\".domain.net ~all"`,
+			"sub\t1800\tIN\tNS\tns1.other.net.",
+			`@	3600	IN	CAA	0 issue "letsencrypt.org"`,
+			`; namecheap: old	1800	IN	URL301	"https://domain.net/new"`,
+			"; namecheap: app\t300\tIN\tALIAS\tapp.host.net.",
+			"",
+		}, "\n"), buffer.String())
+	})
+
+	t.Run("round_trip", func(t *testing.T) {
+		var buffer bytes.Buffer
+		if err := result.WriteZoneFile(&buffer); err != nil {
+			t.Fatal(err)
+		}
+
+		args, err := ParseZoneFile(&buffer, "domain.net")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := HostRecordsFromDetailed(result.Hosts)
+		for i := range expected {
+			switch expected[i].RecordType {
+			case RecordTypeMX, RecordTypeNS, RecordTypeAlias:
+				expected[i].Address = absoluteZoneName(expected[i].Address)
+			}
+		}
+		assert.Equal(t, expected, args.Records)
+		assert.Equal(t, "domain.net", args.Domain)
+		assert.Equal(t, EmailTypeMX, args.EmailType)
+	})
+
+	t.Run("round_trip_set_hosts", func(t *testing.T) {
+		var buffer bytes.Buffer
+		if err := result.WriteZoneFile(&buffer); err != nil {
+			t.Fatal(err)
+		}
+
+		args, err := ParseZoneFile(&buffer, "domain.net")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sentSetHosts := url.Values{}
+		mockServer := newRecordsMockServer(fakeRecordsGetHostsResponse, &sentSetHosts)
+		defer mockServer.Close()
+
+		client := setupClient(nil)
+		client.BaseURL = mockServer.URL
+
+		_, err = client.DomainsDNS.SetHosts(context.TODO(), args)
+		if err != nil {
+			t.Fatal("Unable to set hosts", err)
+		}
+
+		assert.Equal(t, EmailTypeMX, sentSetHosts.Get("EmailType"))
+		assert.Equal(t, "mx1.domain.net.", sentSetHosts.Get("Address4"))
+	})
+
+	t.Run("long_txt", func(t *testing.T) {
+		long := DomainDNSGetHostsResult{
+			Domain: "domain.net",
+			Hosts:  []DomainsDNSHostRecordDetailed{{Name: "dkim._domainkey", Type: "TXT", Address: strings.Repeat("a", 300), TTL: 1800}},
+		}
+
+		var buffer bytes.Buffer
+		if err := long.WriteZoneFile(&buffer); err != nil {
+			t.Fatal(err)
+		}
+		assert.Contains(t, buffer.String(), `"`+strings.Repeat("a", 255)+`" "`+strings.Repeat("a", 45)+`"`)
+
+		args, err := ParseZoneFile(&buffer, "domain.net")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, strings.Repeat("a", 300), args.Records[0].Address)
+		assert.Empty(t, args.EmailType)
+	})
+
+	t.Run("unsupported_type", func(t *testing.T) {
+		invalid := DomainDNSGetHostsResult{Domain: "domain.net", Hosts: []DomainsDNSHostRecordDetailed{{Name: "@", Type: "SRV", Address: "x"}}}
+
+		err := invalid.WriteZoneFile(&bytes.Buffer{})
+		assert.EqualError(t, err, "unsupported record type SRV of host @")
+	})
+}
+
+func TestParseZoneFile(t *testing.T) {
+	t.Run("provider_export", func(t *testing.T) {
+		zone := `
+$ORIGIN domain.net.
+$TTL 1h
+@	IN	SOA	ns-1.awsdns.com. hostmaster.domain.net. (
+		1          ; serial
+		7200       ; refresh
+		900        ; retry
+		1209600    ; expire
+		86400 )    ; minimum
+domain.net.	172800	IN	NS	ns-1.awsdns.com.
+	IN	A	192.0.2.1 ; apex address
+www	300	CNAME	@
+blog.domain.net.	IN	300	CNAME	hosting.net.
+@	MX	10	mail
+	MX	20	backup.mail.net.
+@	TXT	"v=spf1 -all" 
+_dmarc	TXT	( "v=DMARC1; p=reject;"
+		" rua=mailto:\"dmarc\"@domain.net" )
+@	86400	CAA	0 ISSUE "letsencrypt.org"
+$ORIGIN sub.domain.net.
+api	30	AAAA	2001:db8::1
+`
+
+		args, err := ParseZoneFile(strings.NewReader(zone), "Domain.net")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []DomainsDNSHostRecord{
+			{HostName: "@", RecordType: "A", Address: "192.0.2.1", TTL: 3600},
+			{HostName: "www", RecordType: "CNAME", Address: "domain.net.", TTL: 300},
+			{HostName: "blog", RecordType: "CNAME", Address: "hosting.net.", TTL: 300},
+			{HostName: "@", RecordType: "MX", Address: "mail.domain.net.", MXPref: UInt8(10), TTL: 3600},
+			{HostName: "@", RecordType: "MX", Address: "backup.mail.net.", MXPref: UInt8(20), TTL: 3600},
+			{HostName: "@", RecordType: "TXT", Address: "v=spf1 -all", TTL: 3600},
+			{HostName: "_dmarc", RecordType: "TXT", Address: `v=DMARC1; p=reject; rua=mailto:"dmarc"@domain.net`, TTL: 3600},
+			{HostName: "@", RecordType: "CAA", Address: `0 issue "letsencrypt.org"`, TTL: 60000},
+			{HostName: "api.sub", RecordType: "AAAA", Address: "2001:db8::1", TTL: 60},
+		}, args.Records)
+		// inferred from the MX records, there's no email type comment
+		assert.Equal(t, EmailTypeMX, args.EmailType)
+	})
+
+	t.Run("email_type", func(t *testing.T) {
+		args, err := ParseZoneFile(strings.NewReader("; namecheap: @ MXE 192.0.2.1\n"), "domain.net")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, EmailTypeMXE, args.EmailType)
+
+		args, err = ParseZoneFile(strings.NewReader("; email type: fwd\nwww A 192.0.2.1\n"), "domain.net")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, EmailTypeForward, args.EmailType)
+	})
+
+	t.Run("no_ttl", func(t *testing.T) {
+		args, err := ParseZoneFile(strings.NewReader("www A 192.0.2.1\n"), "domain.net")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []DomainsDNSHostRecord{{HostName: "www", RecordType: "A", Address: "192.0.2.1"}}, args.Records)
+		assert.Empty(t, args.EmailType)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var errorsCases = map[string]struct {
+			Zone          string
+			ExpectedError string
+		}{
+			"unsupported_type":      {"www A 192.0.2.1\n_sip._tcp SRV 10 5 5060 sip.domain.net.\n", "line 2: unsupported record type SRV"},
+			"unsupported_directive": {"$INCLUDE other.zone\n", "line 1: unsupported directive $INCLUDE"},
+			"unsupported_class":     {"www CH A 192.0.2.1\n", "line 1: unsupported class CH"},
+			"outside_domain":        {"www.other.net. A 192.0.2.1\n", "line 1: name www.other.net is outside of domain.net"},
+			"outside_origin":        {"$ORIGIN other.net.\n", "line 1: $ORIGIN other.net. is outside of domain.net"},
+			"invalid_address":       {"www A 2001:db8::1\n", "line 1: invalid A address 2001:db8::1"},
+			"invalid_mx_pref":       {"@ MX 300 mail\n", "line 1: invalid MX preference 300, maximum value is 255"},
+			"missing_values":        {"@ CAA 0 issue\n", "line 1: CAA record expects 3 values, got 2"},
+			"missing_owner":         {"  A 192.0.2.1\n", "line 1: record without an owner name"},
+			"unterminated_quote":    {"@ TXT \"v=spf1\n", "line 1: unterminated quoted string"},
+			"unbalanced":            {"@ TXT ( \"a\"\n", "line 1: unbalanced parentheses"},
+			"invalid_ttl":           {"$TTL 1x\n", "line 1: invalid TTL 1x"},
+		}
+
+		for name, errorCase := range errorsCases {
+			t.Run(name, func(t *testing.T) {
+				_, err := ParseZoneFile(strings.NewReader(errorCase.Zone), "domain.net")
+				assert.EqualError(t, err, errorCase.ExpectedError)
+			})
+		}
+	})
+}